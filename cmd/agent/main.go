@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"github.com/Eqke/metric-collector/internal/agent/config"
 	"github.com/Eqke/metric-collector/internal/agent/grpcagent"
 	"github.com/Eqke/metric-collector/internal/agent/httpagent"
 	"github.com/Eqke/metric-collector/internal/agent/poller"
+	"github.com/Eqke/metric-collector/internal/certs"
 	"github.com/Eqke/metric-collector/internal/encrypting"
 	"log"
 	"os/signal"
@@ -37,22 +39,36 @@ func main() {
 		sugarLogger.Fatal(err)
 	}
 
-	publicKey, err := encrypting.GetPublicKey(settings.CryptoKey)
+	keys, err := encrypting.NewKeyring(sugarLogger, settings.CryptoKey, settings.CryptoKeyReload)
 	if err != nil {
 		sugarLogger.Fatal(err)
 	}
 	var wg sync.WaitGroup
+	wg.Add(1)
+	go keys.Run(ctx, &wg)
+
+	var tlsConfig *tls.Config
+	if settings.TLSEnabled() {
+		reloader, err := certs.NewReloader(sugarLogger, settings.TLSCert, settings.TLSKey,
+			settings.TLSCA, settings.TLSReloadInterval)
+		if err != nil {
+			sugarLogger.Fatal(err)
+		}
+		wg.Add(1)
+		go reloader.Run(ctx, &wg)
+		tlsConfig = reloader.ClientConfig(settings.TLSServerName)
+	}
 
 	poll := poller.NewPoller(sugarLogger, settings)
 
 	go poll.Poll(ctx, &wg)
 
-	httpAgent := httpagent.New(settings, sugarLogger, publicKey, poll)
+	httpAgent := httpagent.New(settings, sugarLogger, keys, tlsConfig, poll)
 
 	wg.Add(2)
 	go httpAgent.Run(ctx, &wg)
 
-	grpcAgent := grpcagent.New(sugarLogger, settings, poll)
+	grpcAgent := grpcagent.New(sugarLogger, settings, poll, tlsConfig)
 
 	go grpcAgent.Run(ctx, &wg)
 
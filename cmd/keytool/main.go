@@ -0,0 +1,65 @@
+// Утилита keytool управляет ключами шифрования в каталоге CRYPTO_KEY:
+//
+//	keytool -dir keys generate            создать новый ключ
+//	keytool -dir keys -grace 72h retire   удалить ключи, замененные новым
+//	                                      ключом более grace назад
+//	keytool -dir keys list                вывести ключи
+//
+// Новый ключ создается в каталоге сервера. Сервер начинает принимать его
+// после перечитывания каталога, после чего открытый ключ копируется
+// агентам. Агенты шифруют данные самым новым ключом своего каталога.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/encrypting"
+	"go.uber.org/zap"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "crypto keys directory")
+	grace := flag.Duration("grace", 72*time.Hour, "time agents get to switch to a new key before old keys are retired")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] generate|retire|list\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	switch flag.Arg(0) {
+	case "generate":
+		id, err := encrypting.GenerateKey(*dir, time.Now())
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("generated key", id)
+	case "retire":
+		retired, err := encrypting.RetireKeys(*dir, *grace, time.Now())
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, id := range retired {
+			fmt.Printf("retired key %q\n", id)
+		}
+	case "list":
+		keys, err := encrypting.NewKeyring(zap.NewNop().Sugar(), *dir, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, key := range keys.Keys() {
+			fmt.Printf("%q\tcreated %s\tprivate %t\tcurrent %t\n",
+				key.ID, key.Created.Format(time.RFC3339), key.Private, key.ID == keys.Current())
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
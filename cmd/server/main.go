@@ -2,15 +2,26 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"github.com/Eqke/metric-collector/internal/alerting"
+	"github.com/Eqke/metric-collector/internal/certs"
+	"github.com/Eqke/metric-collector/internal/compactor"
 	"github.com/Eqke/metric-collector/internal/encrypting"
+	"github.com/Eqke/metric-collector/internal/hub"
+	"github.com/Eqke/metric-collector/internal/janitor"
 	"github.com/Eqke/metric-collector/internal/restorer"
 	"github.com/Eqke/metric-collector/internal/server/config"
+	"github.com/Eqke/metric-collector/internal/server/graphite"
 	"github.com/Eqke/metric-collector/internal/server/grpcserver"
 	"github.com/Eqke/metric-collector/internal/server/httpserver"
+	"github.com/Eqke/metric-collector/internal/server/statsd"
+	"github.com/Eqke/metric-collector/internal/storage/postgres"
 	"log"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/Eqke/metric-collector/internal/storagemanager"
 	"go.uber.org/zap"
@@ -43,38 +54,117 @@ func main() {
 		sugarLogger.Fatal(err)
 	}
 
-	storage, err := storagemanager.GetStorage(ctx, sugarLogger, settings)
+	if settings.MigrateOnly() {
+		err = postgres.Migrate(ctx, sugarLogger, settings.DatabaseDSN, settings.Migrate == config.MigrateDown)
+		if err != nil {
+			sugarLogger.Fatal(err)
+		}
+		return
+	}
+
+	store, err := storagemanager.GetStorage(ctx, sugarLogger, settings)
 	if err != nil {
 		sugarLogger.Fatal(err)
 	}
+	// Принятые метрики рассылаются подписчикам /stream, метрики без
+	// обновлений дольше StaleTTL отмечаются устаревшими
+	updates := hub.New(sugarLogger)
+	storage := janitor.NewStorage(hub.NewStorage(store, updates),
+		time.Duration(settings.StaleTTL)*time.Second)
+	go func() {
+		<-ctx.Done()
+		updates.Close()
+	}()
 
-	privateKey, err := encrypting.GetPrivateKey(settings.CryptoKey)
-	if err != nil {
+	keys, err := encrypting.NewKeyring(sugarLogger, settings.CryptoKey, settings.CryptoKeyReload)
+	if errors.Is(err, encrypting.ErrNoKeys) {
 		sugarLogger.Error(err)
-		err = encrypting.GenerateIfNotExist(settings.CryptoKey)
+		if _, err = encrypting.GenerateKey(settings.CryptoKey, time.Now()); err != nil {
+			sugarLogger.Fatal(err)
+		}
+		keys, err = encrypting.NewKeyring(sugarLogger, settings.CryptoKey, settings.CryptoKeyReload)
+	}
+	if err != nil {
+		sugarLogger.Fatal(err)
+	}
+	wg.Add(1)
+	go keys.Run(ctx, &wg)
+
+	if settings.Restore && settings.DatabaseDSN == "" {
+		restore := restorer.New(sugarLogger, storage, settings.FileStoragePath, settings.StoreInterval)
+		wg.Add(1)
+		go restore.Run(ctx, &wg)
+	}
+
+	if settings.Retention != "" {
+		tiers, err := compactor.ParseTiers(settings.Retention)
 		if err != nil {
 			sugarLogger.Fatal(err)
 		}
+		compact := compactor.New(sugarLogger, storage, tiers, settings.CompactInterval)
+		wg.Add(1)
+		go compact.Run(ctx, &wg)
+	}
 
-		privateKey, err = encrypting.GetPrivateKey(settings.CryptoKey)
+	if settings.PurgeTTL > 0 {
+		purge := janitor.New(sugarLogger, storage, settings.PurgeTTL, settings.JanitorInterval)
+		wg.Add(1)
+		go purge.Run(ctx, &wg)
+	}
+
+	var rules []alerting.Rule
+	if settings.AlertRules != "" {
+		rules, err = alerting.LoadRules(settings.AlertRules)
 		if err != nil {
 			sugarLogger.Fatal(err)
 		}
 	}
+	alerts := alerting.New(sugarLogger, storage, rules,
+		alerting.NewWebhookNotifier(settings.AlertWebhooks), settings.AlertInterval)
+	if settings.AlertRules != "" {
+		wg.Add(1)
+		go alerts.Run(ctx, &wg)
+	}
 
-	if settings.Restore && settings.DatabaseDSN == "" {
-		restore := restorer.New(sugarLogger, storage, settings.FileStoragePath, settings.StoreInterval)
+	var tlsConfig *tls.Config
+	if settings.TLSEnabled() {
+		reloader, err := certs.NewReloader(sugarLogger, settings.TLSCert, settings.TLSKey,
+			settings.TLSClientCA, settings.TLSReloadInterval)
+		if err != nil {
+			sugarLogger.Fatal(err)
+		}
 		wg.Add(1)
-		go restore.Run(ctx, &wg)
+		go reloader.Run(ctx, &wg)
+		tlsConfig = reloader.ServerConfig(settings.TLSClientAuth)
 	}
 
-	server := httpserver.New(settings, storage, sugarLogger, privateKey)
+	guard := settings.ReplayGuard()
+	server := httpserver.New(settings, storage, sugarLogger, keys, updates, alerts, guard, tlsConfig)
 	wg.Add(2)
 	go server.Run(ctx, &wg)
 
-	grpcServer := grpcserver.New(sugarLogger, storage, settings.GrpcServerHost)
+	// Токены проверяются при чтении конфигурации
+	tenants, _ := settings.Tenants()
+	tokens, _ := settings.AccessTokens()
+	grpcServer := grpcserver.New(sugarLogger, storage, settings.GrpcServerHost, settings.AdminToken, tenants, tokens, settings.HashKey, guard, tlsConfig)
 
 	go grpcServer.Run(ctx, &wg)
 
+	if settings.StatsdAddress != "" {
+		statsdServer := statsd.New(sugarLogger, storage, settings.StatsdAddress, settings.StatsdFlushInterval)
+		wg.Add(1)
+		go statsdServer.Run(ctx, &wg)
+	}
+
+	if settings.GraphiteAddress != "" {
+		rules, err := graphite.ParseRules(settings.GraphiteRules)
+		if err != nil {
+			sugarLogger.Fatal(err)
+		}
+		graphiteServer := graphite.New(sugarLogger, storage, settings.GraphiteAddress, rules, settings.GraphiteMaxConns)
+		wg.Add(1)
+		go graphiteServer.Run(ctx, &wg)
+	}
+
 	wg.Wait()
 }
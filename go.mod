@@ -14,6 +14,7 @@ require (
 	go.uber.org/zap v1.27.0
 	golang.org/x/tools v0.24.0
 	google.golang.org/grpc v1.67.0
+	google.golang.org/protobuf v1.34.2
 	honnef.co/go/tools v0.0.1-2020.1.4
 )
 
@@ -57,7 +58,12 @@ require (
 	golang.org/x/sys v0.24.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
-	google.golang.org/protobuf v1.34.2 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	olympos.io/encoding/edn v0.0.0-20201019073823-d3554ca0b0a3 // indirect
 )
+
+// Копия metric-collector-grpc-api с расширенным proto: RPC ReadMetricRange,
+// FindMetrics, сервис MetricCollectorAdmin, метки и гистограммы в Metric.
+// Заменяется новой версией зависимости после публикации изменений API,
+// см. third_party/metric-collector-grpc-api/README.md.
+replace github.com/eqkez0r/metric-collector-grpc-api => ./third_party/metric-collector-grpc-api
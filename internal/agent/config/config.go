@@ -4,9 +4,13 @@ package config
 import (
 	"errors"
 	"flag"
+	"fmt"
+	"github.com/Eqke/metric-collector/internal/tenant"
 	e "github.com/Eqke/metric-collector/pkg/error"
+	"github.com/Eqke/metric-collector/pkg/metric"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"strings"
 )
 
 const (
@@ -23,10 +27,16 @@ const (
 	defaultRateLimit = 100
 	// Значение адреса gRPC-сервера по умолчанию
 	defaultGrpcAddr = "127.0.0.1:8081"
+	// Значение периода проверки изменения файлов TLS по умолчанию
+	defaultTLSReloadInterval = 10
+	// Значение периода проверки каталога ключей шифрования по умолчанию
+	defaultCryptoKeyReload = 60
 )
 
 var (
 	ErrUnexpectedArguments = errors.New("unexpected arguments")
+	ErrInvalidTLS          = errors.New("tls certificate and key must be set together")
+	ErrInvalidInterval     = errors.New("invalid interval")
 )
 
 // Тип AgentConfig является типом конфигурации для Agent
@@ -37,7 +47,27 @@ type AgentConfig struct {
 	HashKey        string `env:"KEY"`
 	RateLimit      int    `env:"RATE_LIMIT"`
 	CryptoKey      string `env:"CRYPTO_KEY" json:"crypto_key"`
-	GrpcServerHost string `env:"GRPC_SERVER_HOST" json:"grpc_server_host"`
+	// Период в секундах проверки новых ключей в каталоге CryptoKey
+	CryptoKeyReload int    `env:"CRYPTO_KEY_RELOAD" json:"crypto_key_reload"`
+	GrpcServerHost  string `env:"GRPC_SERVER_HOST" json:"grpc_server_host"`
+	// Метки, добавляемые ко всем метрикам агента, в формате name=value,name=value
+	Labels string `env:"LABELS" json:"labels"`
+	// Арендатор, от имени которого агент публикует метрики
+	Tenant string `env:"TENANT" json:"tenant"`
+	// Токен арендатора, передаваемый в заголовке X-Tenant-Token
+	Token string `env:"TOKEN" json:"token"`
+	// Токен доступа к API, передаваемый в заголовке X-API-Key
+	APIKey string `env:"API_KEY" json:"api_key"`
+	// TLS включает TLS для обоих транспортов. Сертификат сервера
+	// проверяется по TLSCA или системным корневым сертификатам, TLSCert и
+	// TLSKey задают сертификат клиента. Файлы перечитываются при изменении
+	// каждые TLSReloadInterval секунд.
+	TLS               bool   `env:"TLS" json:"tls"`
+	TLSCA             string `env:"TLS_CA" json:"tls_ca"`
+	TLSCert           string `env:"TLS_CERT" json:"tls_cert"`
+	TLSKey            string `env:"TLS_KEY" json:"tls_key"`
+	TLSServerName     string `env:"TLS_SERVER_NAME" json:"tls_server_name"`
+	TLSReloadInterval int    `env:"TLS_RELOAD_INTERVAL" json:"tls_reload_interval"`
 }
 
 // Функция NewAgentConfig создает экземлпяр типа AgentConfig
@@ -51,8 +81,19 @@ func NewAgentConfig() (*AgentConfig, error) {
 	flag.IntVar(&cfg.PollInterval, "p", defaultPollInterval, "poll interval in seconds")
 	flag.StringVar(&cfg.HashKey, "k", "", "hash key")
 	flag.IntVar(&cfg.RateLimit, "l", defaultRateLimit, "rate limit")
-	flag.StringVar(&cfg.CryptoKey, "s", "", "path to crypto key")
+	flag.StringVar(&cfg.CryptoKey, "s", "", "crypto keys directory")
+	flag.IntVar(&cfg.CryptoKeyReload, "crypto-key-reload", defaultCryptoKeyReload, "crypto keys directory check interval in seconds")
 	flag.StringVar(&cfgPathFl, "c", "", "path to cfg")
+	flag.StringVar(&cfg.Labels, "labels", "", "metric labels, e.g. host=web1,zone=eu")
+	flag.StringVar(&cfg.Tenant, "tenant", "", "tenant id")
+	flag.StringVar(&cfg.Token, "token", "", "tenant token")
+	flag.StringVar(&cfg.APIKey, "api-key", "", "api token")
+	flag.BoolVar(&cfg.TLS, "tls", false, "use tls")
+	flag.StringVar(&cfg.TLSCA, "tls-ca", "", "path to CA certificates for server verification")
+	flag.StringVar(&cfg.TLSCert, "tls-cert", "", "path to client tls certificate")
+	flag.StringVar(&cfg.TLSKey, "tls-key", "", "path to client tls private key")
+	flag.StringVar(&cfg.TLSServerName, "tls-server-name", "", "expected server name (host of the address by default)")
+	flag.IntVar(&cfg.TLSReloadInterval, "tls-reload-interval", defaultTLSReloadInterval, "tls files check interval in seconds")
 	flag.Parse()
 
 	if configPath := os.Getenv("CONFIG"); configPath != "" {
@@ -72,6 +113,53 @@ func NewAgentConfig() (*AgentConfig, error) {
 	if err != nil {
 		return nil, e.WrapError(errPointNewAgentConfig, err)
 	}
+	if _, err = cfg.MetricLabels(); err != nil {
+		return nil, e.WrapError(errPointNewAgentConfig, err)
+	}
+	if (cfg.TLSCert == "") != (cfg.TLSKey == "") {
+		return nil, e.WrapError(errPointNewAgentConfig, ErrInvalidTLS)
+	}
+	if cfg.Tenant != "" {
+		if err = tenant.Validate(cfg.Tenant); err != nil {
+			return nil, e.WrapError(errPointNewAgentConfig, err)
+		}
+	}
+	if err = cfg.validateIntervals(); err != nil {
+		return nil, e.WrapError(errPointNewAgentConfig, err)
+	}
 
 	return cfg, nil
 }
+
+// Метод MetricLabels возвращает метки, добавляемые ко всем метрикам агента
+func (c *AgentConfig) MetricLabels() (map[string]string, error) {
+	if c.Labels == "" {
+		return nil, nil
+	}
+	return metric.ParseLabels(strings.Split(c.Labels, ","))
+}
+
+// Метод validateIntervals проверяет, что периоды агента положительны:
+// нулевой период не допускает time.NewTicker
+func (c *AgentConfig) validateIntervals() error {
+	intervals := []struct {
+		name  string
+		value int
+	}{
+		{"report-interval", c.ReportInterval},
+		{"poll-interval", c.PollInterval},
+		{"tls-reload-interval", c.TLSReloadInterval},
+		{"crypto-key-reload", c.CryptoKeyReload},
+	}
+	for _, i := range intervals {
+		if i.value <= 0 {
+			return fmt.Errorf("%w: %s must be positive, got %d", ErrInvalidInterval, i.name, i.value)
+		}
+	}
+	return nil
+}
+
+// Метод TLSEnabled сообщает, включен ли TLS
+func (c *AgentConfig) TLSEnabled() bool {
+	return c.TLS || c.TLSCA != "" || c.TLSCert != ""
+}
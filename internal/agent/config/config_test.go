@@ -14,3 +14,33 @@ func TestNewAgentConfig(t *testing.T) {
 		require.NotNil(t, c)
 	})
 }
+
+func TestValidateIntervals(t *testing.T) {
+	valid := func() *AgentConfig {
+		return &AgentConfig{
+			ReportInterval:    defaultReportInterval,
+			PollInterval:      defaultPollInterval,
+			TLSReloadInterval: defaultTLSReloadInterval,
+			CryptoKeyReload:   defaultCryptoKeyReload,
+		}
+	}
+	require.NoError(t, valid().validateIntervals())
+
+	tests := []struct {
+		name   string
+		modify func(*AgentConfig)
+	}{
+		{"zero report interval", func(c *AgentConfig) { c.ReportInterval = 0 }},
+		{"zero poll interval", func(c *AgentConfig) { c.PollInterval = 0 }},
+		{"zero tls reload interval", func(c *AgentConfig) { c.TLSReloadInterval = 0 }},
+		{"negative tls reload interval", func(c *AgentConfig) { c.TLSReloadInterval = -1 }},
+		{"zero crypto key reload", func(c *AgentConfig) { c.CryptoKeyReload = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+			require.ErrorIs(t, cfg.validateIntervals(), ErrInvalidInterval)
+		})
+	}
+}
@@ -4,14 +4,19 @@ package generator
 import (
 	"bytes"
 	"compress/gzip"
-	"crypto/rsa"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"github.com/Eqke/metric-collector/internal/agent/config"
+	"github.com/Eqke/metric-collector/internal/auth"
 	"github.com/Eqke/metric-collector/internal/encrypting"
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/internal/tenant"
 	"io"
 	"log"
 	"net"
+	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,33 +53,54 @@ type Generator struct {
 	mp                metric.Map
 	errChan           chan error
 	client            *resty.Client
-	publicKey         *rsa.PublicKey
+	keys              *encrypting.Keyring
+	scheme            string
 	endpoint          string
 	hashkey           string
+	labels            map[string]string
 }
 
 // Функция NewGenerator возвращает экземпляр Generator
 func NewGenerator(
 	logger *zap.SugaredLogger,
 	settings *config.AgentConfig,
-	publicKey *rsa.PublicKey,
+	keys *encrypting.Keyring,
+	tlsConfig *tls.Config,
 ) *Generator {
 	client := resty.New()
+	scheme := "http:/"
+	if tlsConfig != nil {
+		client.SetTLSClientConfig(tlsConfig)
+		scheme = "https:/"
+	}
 	client.SetRetryCount(3)
 	client.SetRetryWaitTime(1 * time.Second)
 	client.SetRetryAfter(func(c *resty.Client, r *resty.Response) (time.Duration, error) {
 		return c.RetryWaitTime + 2*time.Duration(r.Request.Attempt)*time.Second, nil
 	})
 	client.SetRetryMaxWaitTime(5 * time.Second)
+	if settings.Tenant != "" {
+		client.SetHeader(tenant.Header, settings.Tenant)
+	}
+	if settings.Token != "" {
+		client.SetHeader(tenant.TokenHeader, settings.Token)
+	}
+	if settings.APIKey != "" {
+		client.SetHeader(auth.Header, settings.APIKey)
+	}
+	// Метки проверяются при чтении конфигурации
+	labels, _ := settings.MetricLabels()
 	return &Generator{
 		logger:            logger,
 		generatedRequests: make(chan *reqtype.ReqType, settings.RateLimit),
 		mu:                sync.Mutex{},
 		errChan:           make(chan error),
 		client:            client,
-		publicKey:         publicKey,
+		keys:              keys,
+		scheme:            scheme,
 		endpoint:          settings.AgentEndpoint,
 		hashkey:           settings.HashKey,
+		labels:            labels,
 	}
 }
 
@@ -159,6 +185,13 @@ func (g *Generator) pollSingleMetric() {
 func (g *Generator) pollUsualMetric(metricName, metricType, metricValue string) (*reqtype.ReqType, error) {
 	endPoint := g.getEndpointToUsualMetric(metricType, metricName, metricValue)
 	req := g.client.R().SetHeader("Content-Type", "text/plain").SetHeader("X-Real-IP", getIP())
+	for name, value := range g.labels {
+		req.QueryParam.Add("label", name+"="+value)
+	}
+	req, err := g.sign(req, endPoint, nil)
+	if err != nil {
+		return nil, err
+	}
 	return &reqtype.ReqType{Req: req, Endpoint: endPoint}, nil
 }
 
@@ -169,15 +202,16 @@ func (g *Generator) pollJSONMetric(metricName, metricType, metricValue string) (
 		return nil, err
 	}
 	endPoint := g.getEndpointToJSONMetric()
-	encryptedData, err := encrypting.Encrypt(g.publicKey, b)
+	encryptedData, err := g.keys.Seal(b)
 	if err != nil {
 		return nil, err
 	}
 	req := g.client.R().
 		SetHeader("Content-Type", "application/json").
 		SetBody(encryptedData)
-	if g.hashkey != "" {
-		req = req.SetHeader("HashSHA256", hash.Sign(encryptedData, g.hashkey))
+	req, err = g.sign(req, endPoint, encryptedData)
+	if err != nil {
+		return nil, err
 	}
 
 	req.SetHeader("X-Real-IP", getIP())
@@ -197,7 +231,7 @@ func (g *Generator) pollEncodeMetric(metricName, metricType, metricValue string)
 		return nil, err
 	}
 
-	encryptedData, err := encrypting.Encrypt(g.publicKey, encoded)
+	encryptedData, err := g.keys.Seal(encoded)
 	if err != nil {
 		return nil, err
 	}
@@ -206,8 +240,9 @@ func (g *Generator) pollEncodeMetric(metricName, metricType, metricValue string)
 		SetHeader("Content-Type", "application/json").
 		SetHeader("Content-Encoding", "gzip").
 		SetBody(encryptedData)
-	if g.hashkey != "" {
-		req = req.SetHeader("HashSHA256", hash.Sign(encryptedData, g.hashkey))
+	req, err = g.sign(req, endPoint, encryptedData)
+	if err != nil {
+		return nil, err
 	}
 	req.SetHeader("X-Real-IP", getIP())
 	return &reqtype.ReqType{Req: req, Endpoint: endPoint}, nil
@@ -227,19 +262,24 @@ func (g *Generator) pollMetricByBatch() {
 		g.errChan <- err
 		return
 	}
-	encryptedData, err := encrypting.Encrypt(g.publicKey, b)
+	encryptedData, err := g.keys.Seal(b)
 	if err != nil {
 		g.errChan <- err
 		return
 	}
+	// Идентификатор пакета сохраняется при повторах запроса клиентом,
+	// поэтому сервер применяет пакет один раз
 	req := g.client.R().
 		SetHeader("Content-Type", "application/json").
+		SetHeader(storage.BatchIDHeader, hash.NewNonce()).
 		SetBody(encryptedData)
-	if g.hashkey != "" {
-		req = req.SetHeader("HashSHA256", hash.Sign(encryptedData, g.hashkey))
+	endpoint := g.getEndpointToBatchMetric()
+	req, err = g.sign(req, endpoint, encryptedData)
+	if err != nil {
+		g.errChan <- err
+		return
 	}
 	req.SetHeader("X-Real-IP", getIP())
-	endpoint := g.getEndpointToBatchMetric()
 	g.generatedRequests <- &reqtype.ReqType{Req: req, Endpoint: endpoint}
 }
 
@@ -262,7 +302,7 @@ func (g *Generator) pollEncodedMetricByBatch() {
 		g.errChan <- err
 		return
 	}
-	encryptedData, err := encrypting.Encrypt(g.publicKey, encoded)
+	encryptedData, err := g.keys.Seal(encoded)
 	if err != nil {
 		g.errChan <- err
 		return
@@ -270,36 +310,62 @@ func (g *Generator) pollEncodedMetricByBatch() {
 	req := g.client.R().
 		SetHeader("Content-Type", "application/json").
 		SetHeader("Content-Encoding", "gzip").
+		SetHeader(storage.BatchIDHeader, hash.NewNonce()).
 		SetBody(encryptedData)
-	if g.hashkey != "" {
-		req = req.SetHeader("HashSHA256", hash.Sign(encryptedData, g.hashkey))
+	endpoint := g.getEndpointToBatchMetric()
+	req, err = g.sign(req, endpoint, encryptedData)
+	if err != nil {
+		g.errChan <- err
+		return
 	}
 	req.SetHeader("X-Real-IP", getIP())
-	g.generatedRequests <- &reqtype.ReqType{Req: req, Endpoint: g.getEndpointToBatchMetric()}
+	g.generatedRequests <- &reqtype.ReqType{Req: req, Endpoint: endpoint}
+}
+
+// Метод sign подписывает запрос к endpoint с телом data ключом hashkey
+// вместе со временем отправки и nonce, по которым сервер отклоняет
+// повторы запроса. Подпись покрывает путь и параметры запроса, поэтому
+// параметры добавляются до вызова sign. Агент отправляет запросы методом
+// POST.
+func (g *Generator) sign(req *resty.Request, endpoint string, data []byte) (*resty.Request, error) {
+	if g.hashkey == "" {
+		return req, nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	payload := hash.RequestPayload(http.MethodPost, u.Path, req.QueryParam, data)
+	timestamp, nonce := hash.Timestamp(time.Now()), hash.NewNonce()
+	return req.
+		SetHeader(hash.HeaderTimestamp, timestamp).
+		SetHeader(hash.HeaderNonce, nonce).
+		SetHeader(hash.HeaderSignature, hash.SignRequest(payload, timestamp, nonce, g.hashkey)), nil
 }
 
 // Метод getEndpointToUsualMetric формирует конечную точку для запроса
 func (g *Generator) getEndpointToUsualMetric(metricType, metricName, metricValue string) string {
-	return strings.Join([]string{"http:/", g.endpoint, "update", metricType, metricName, metricValue}, "/")
+	return strings.Join([]string{g.scheme, g.endpoint, "update", metricType, metricName, metricValue}, "/")
 }
 
 // Метод getEndpointToJSONMetric формирует конечную точку для запроса в формате JSON
 func (g *Generator) getEndpointToJSONMetric() string {
-	return strings.Join([]string{"http:/", g.endpoint, "update"}, "/")
+	return strings.Join([]string{g.scheme, g.endpoint, "update"}, "/")
 }
 
 // Метод getEndpointToBatchMetric формирует конечную точку для запроса в формате пачки
 func (g *Generator) getEndpointToBatchMetric() string {
-	return strings.Join([]string{"http:/", g.endpoint, "updates"}, "/")
+	return strings.Join([]string{g.scheme, g.endpoint, "updates"}, "/")
 }
 
 // Метод prepareJSONMetric отвечает за подготовку метрики в формате JSON
 func (g *Generator) prepareJSONMetric(metricName, metricType, metricValue string) ([]byte, error) {
 	m := metric.Metrics{
-		ID:    metricName,
-		MType: metricType,
-		Delta: nil,
-		Value: nil,
+		ID:     metricName,
+		MType:  metricType,
+		Delta:  nil,
+		Value:  nil,
+		Labels: g.labels,
 	}
 
 	switch metricType {
@@ -349,7 +415,7 @@ func (g *Generator) prepareMetricArray() []metric.Metrics {
 						g.errChan <- err
 						continue
 					}
-					met := metric.Metrics{ID: metricName.String(), MType: k.String(), Value: &val}
+					met := metric.Metrics{ID: metricName.String(), MType: k.String(), Value: &val, Labels: g.labels}
 					arr = append(arr, met)
 				}
 			}
@@ -361,7 +427,19 @@ func (g *Generator) prepareMetricArray() []metric.Metrics {
 						g.errChan <- err
 						continue
 					}
-					met := metric.Metrics{ID: metricName.String(), MType: k.String(), Delta: &val}
+					met := metric.Metrics{ID: metricName.String(), MType: k.String(), Delta: &val, Labels: g.labels}
+					arr = append(arr, met)
+				}
+			}
+		case metric.TypeHistogram:
+			{
+				for metricName, metricValue := range v {
+					h, err := metric.ParseHistogram(metricValue)
+					if err != nil {
+						g.errChan <- err
+						continue
+					}
+					met := metric.Metrics{ID: metricName.String(), MType: k.String(), Histogram: h, Labels: g.labels}
 					arr = append(arr, met)
 				}
 			}
@@ -2,14 +2,21 @@ package grpcagent
 
 import (
 	"context"
+	"crypto/tls"
 	"github.com/Eqke/metric-collector/internal/agent/config"
 	"github.com/Eqke/metric-collector/internal/agent/poller"
+	"github.com/Eqke/metric-collector/internal/auth"
 	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/pkg/storeapi"
+	"github.com/Eqke/metric-collector/utils/hash"
 	pb "github.com/eqkez0r/metric-collector-grpc-api/grpc/metric_collector"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -19,6 +26,12 @@ type GRPCClient struct {
 	logger *zap.SugaredLogger
 
 	reportInterval time.Duration
+	labels         map[string]string
+	tenant         string
+	token          string
+	apiKey         string
+	hashKey        string
+	creds          credentials.TransportCredentials
 
 	poller poller.MetricPoller
 }
@@ -27,11 +40,24 @@ func New(
 	logger *zap.SugaredLogger,
 	settings *config.AgentConfig,
 	poller poller.MetricPoller,
+	tlsConfig *tls.Config,
 ) *GRPCClient {
+	creds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	// Метки проверяются при чтении конфигурации
+	labels, _ := settings.MetricLabels()
 	return &GRPCClient{
 		host:           settings.GrpcServerHost,
 		logger:         logger,
 		reportInterval: time.Second * time.Duration(settings.ReportInterval),
+		labels:         labels,
+		tenant:         settings.Tenant,
+		token:          settings.Token,
+		apiKey:         settings.APIKey,
+		hashKey:        settings.HashKey,
+		creds:          creds,
 		poller:         poller,
 	}
 }
@@ -62,15 +88,27 @@ func (gc *GRPCClient) Run(ctx context.Context, wg *sync.WaitGroup) {
 }
 
 func (gc *GRPCClient) Poll(ctx context.Context) {
-	conn, err := grpc.NewClient(gc.host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(gc.host,
+		grpc.WithTransportCredentials(gc.creds),
+		grpc.WithUnaryInterceptor(gc.sign),
+	)
 	if err != nil {
 		gc.logger.Errorw("failed to connect to grpc server", "host", gc.host, "error", err)
 		return
 	}
 	defer conn.Close()
+	if gc.tenant != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, storeapi.TenantMetadataKey, gc.tenant)
+	}
+	if gc.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, storeapi.TenantTokenMetadataKey, gc.token)
+	}
+	if gc.apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, auth.MetadataKey, gc.apiKey)
+	}
 	grpcConn := pb.NewMetricCollectorClient(conn)
 	metricMap := gc.poller.GetMetrics()
-	metricList := make([]*pb.Metric, 0, len(metricMap["gauge"])+len(metricMap["counter"]))
+	metricList := make([]*pb.Metric, 0, len(metricMap["gauge"])+len(metricMap["counter"])+len(metricMap["histogram"]))
 
 	for mt, mm := range metricMap {
 
@@ -78,6 +116,7 @@ func (gc *GRPCClient) Poll(ctx context.Context) {
 			pushMetric := &pb.Metric{
 				MetricName: mn.String(),
 				MetricType: mt.String(),
+				Labels:     gc.labels,
 			}
 			switch mt {
 			case metric.TypeGauge:
@@ -99,6 +138,19 @@ func (gc *GRPCClient) Poll(ctx context.Context) {
 					}
 					pushMetric.Delta = &counter
 				}
+			case metric.TypeHistogram:
+				{
+					h, err := metric.ParseHistogram(v)
+					if err != nil {
+						gc.logger.Errorw("failed to parse value", "metric", mt, "value", v)
+						continue
+					}
+					pushMetric = storeapi.ToProto(metric.Metrics{ID: mn.String(), MType: mt.String(), Histogram: h, Labels: gc.labels})
+					// Гистограммы отправляются только в пакете, чтобы
+					// сервер не объединил их дважды
+					metricList = append(metricList, pushMetric)
+					continue
+				}
 			default:
 				{
 					gc.logger.Errorw("unknown metric type", "type", mt)
@@ -115,7 +167,7 @@ func (gc *GRPCClient) Poll(ctx context.Context) {
 		}
 
 	}
-	_, err = grpcConn.ReceiveMetricBatch(ctx, &pb.ReceiveMetricBatchRequest{
+	_, err = grpcConn.ReceiveMetricBatch(withBatchID(ctx), &pb.ReceiveMetricBatchRequest{
 		Metrics: metricList,
 	})
 	if err != nil {
@@ -124,3 +176,35 @@ func (gc *GRPCClient) Poll(ctx context.Context) {
 	}
 	gc.logger.Infow("send metric batch success")
 }
+
+// Функция withBatchID добавляет в метаданные вызова идентификатор пакета,
+// по которому сервер применяет пакет не более одного раза
+func withBatchID(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, storeapi.BatchIDMetadataKey, hash.NewNonce())
+}
+
+// Метод sign подписывает вызов ключом hashKey: имя метода, сообщение
+// запроса, время отправки и nonce, по которым сервер отклоняет повторы
+// вызова
+func (gc *GRPCClient) sign(
+	ctx context.Context,
+	method string,
+	req, reply any,
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	if gc.hashKey != "" {
+		payload, err := storeapi.SignedPayload(method, req)
+		if err != nil {
+			return err
+		}
+		timestamp, nonce := hash.Timestamp(time.Now()), hash.NewNonce()
+		ctx = metadata.AppendToOutgoingContext(ctx,
+			strings.ToLower(hash.HeaderTimestamp), timestamp,
+			strings.ToLower(hash.HeaderNonce), nonce,
+			strings.ToLower(hash.HeaderSignature), hash.SignRequest(payload, timestamp, nonce, gc.hashKey),
+		)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -4,7 +4,7 @@ package httpagent
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto/tls"
 	"github.com/Eqke/metric-collector/internal/agent/config"
 	"strconv"
 	"sync"
@@ -13,6 +13,7 @@ import (
 	"github.com/Eqke/metric-collector/internal/agent/generator"
 	"github.com/Eqke/metric-collector/internal/agent/poller"
 	"github.com/Eqke/metric-collector/internal/agent/poster"
+	"github.com/Eqke/metric-collector/internal/encrypting"
 	"github.com/Eqke/metric-collector/pkg/metric"
 	"github.com/go-resty/resty/v2"
 	"go.uber.org/zap"
@@ -37,7 +38,8 @@ type Agent struct {
 func New(
 	settings *config.AgentConfig,
 	logger *zap.SugaredLogger,
-	publicKey *rsa.PublicKey,
+	keys *encrypting.Keyring,
+	tlsConfig *tls.Config,
 	poller poller.MetricPoller,
 ) *Agent {
 	client := resty.New()
@@ -48,7 +50,7 @@ func New(
 		pollCounter:    0,
 		mp:             make(metric.Map),
 		poller:         poller,
-		generator:      generator.NewGenerator(logger, settings, publicKey),
+		generator:      generator.NewGenerator(logger, settings, keys, tlsConfig),
 		poster:         poster.NewPoster(logger, settings),
 		mu:             sync.RWMutex{},
 		pollInterval:   time.Duration(settings.PollInterval) * time.Second,
@@ -24,6 +24,10 @@ type Poller struct {
 	mp           metric.Map
 	mu           sync.Mutex
 	pollInterval time.Duration
+	// Номер последней учтенной сборки мусора и паузы сборок, накопленные
+	// с последнего вызова GetMetrics
+	lastNumGC uint32
+	gcPauses  *metric.Histogram
 }
 
 // Функция NewPoller возвращает объект типа Poller
@@ -34,11 +38,13 @@ func NewPoller(
 	mp := make(metric.Map)
 	mp[metric.TypeGauge] = make(map[metric.Name]string)
 	mp[metric.TypeCounter] = make(map[metric.Name]string)
+	mp[metric.TypeHistogram] = make(map[metric.Name]string)
 	return &Poller{
 		logger:       logger,
 		mp:           mp,
 		mu:           sync.Mutex{},
 		pollInterval: time.Duration(settings.PollInterval) * time.Second,
+		gcPauses:     metric.NewHistogram(metric.GCPauseBounds),
 	}
 }
 
@@ -62,13 +68,21 @@ func (p *Poller) Poll(ctx context.Context, wg *sync.WaitGroup) {
 
 }
 
+// Метод GetMetrics возвращает копию собранных метрик. Паузы GC
+// передаются один раз: гистограмма пауз очищается при каждом вызове.
 func (p *Poller) GetMetrics() metric.Map {
 	cp := make(metric.Map, len(p.mp))
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	for k, v := range p.mp {
-		cp[k] = v
+		values := make(map[metric.Name]string, len(v)+1)
+		for name, value := range v {
+			values[name] = value
+		}
+		cp[k] = values
 	}
+	cp[metric.TypeHistogram][metric.GCPauseNs] = p.gcPauses.String()
+	p.gcPauses = metric.NewHistogram(metric.GCPauseBounds)
 
 	return cp
 }
@@ -78,7 +92,12 @@ func (p *Poller) updateRuntime(wg *sync.WaitGroup) {
 	defer wg.Done()
 	ms := &runtime.MemStats{}
 	runtime.ReadMemStats(ms)
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	metric.UpdateRuntimeMetrics(ms, p.mp)
+	// Границы гистограмм совпадают, поэтому Merge не возвращает ошибку
+	_ = p.gcPauses.Merge(*metric.GCPauseHistogram(ms, p.lastNumGC))
+	p.lastNumGC = ms.NumGC
 }
 
 // Метод updateUtil обновляет метрики cpu, totalmemory и freememory
@@ -2,8 +2,11 @@ package poller
 
 import (
 	"github.com/Eqke/metric-collector/internal/agent/config"
+	"github.com/Eqke/metric-collector/pkg/metric"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
+	"runtime"
+	"sync"
 	"testing"
 )
 
@@ -15,3 +18,25 @@ func TestNewPoller(t *testing.T) {
 		require.NotNil(t, poller)
 	})
 }
+
+func TestPoller_GCPausesAccumulate(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	p := NewPoller(l, &config.AgentConfig{})
+	var wg sync.WaitGroup
+
+	count := func(mp metric.Map) uint64 {
+		h, err := metric.ParseHistogram(mp[metric.TypeHistogram][metric.GCPauseNs])
+		require.NoError(t, err)
+		return h.Count
+	}
+
+	// Паузы нескольких опросов передаются в одном снимке
+	p.GetMetrics()
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+		wg.Add(1)
+		p.updateRuntime(&wg)
+	}
+	require.GreaterOrEqual(t, count(p.GetMetrics()), uint64(3))
+	require.Zero(t, count(p.GetMetrics()))
+}
@@ -0,0 +1,204 @@
+// Пакет alerting периодически проверяет правила оповещения по значениям
+// метрик хранилища и отправляет оповещения о срабатывании и разрешении
+package alerting
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"go.uber.org/zap"
+)
+
+// Тип State - состояние оповещения
+type State string
+
+// Перечень состояний оповещения. Оповещение находится в состоянии
+// pending, пока условие выполняется меньше For правила, затем
+// переходит в firing. Когда условие перестает выполняться, сработавшее
+// оповещение переходит в resolved и удаляется из активных.
+const (
+	StatePending  = State("pending")
+	StateFiring   = State("firing")
+	StateResolved = State("resolved")
+)
+
+//go:generate moq -out metricsFinder_moq_test.go . MetricsFinder
+type MetricsFinder interface {
+	FindMetrics(context.Context, metric.Metrics, []*metric.LabelMatcher) ([]metric.Metrics, error)
+}
+
+//go:generate moq -out notifier_moq_test.go . Notifier
+type Notifier interface {
+	Notify(context.Context, []Alert) error
+}
+
+// Тип Alert - оповещение по серии метрики
+type Alert struct {
+	Rule       string            `json:"rule"`
+	Severity   string            `json:"severity"`
+	Metric     string            `json:"metric"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	Op         Op                `json:"op"`
+	Threshold  float64           `json:"threshold"`
+	Value      float64           `json:"value"`
+	State      State             `json:"state"`
+	ActiveAt   time.Time         `json:"active_at"`
+	FiredAt    *time.Time        `json:"fired_at,omitempty"`
+	ResolvedAt *time.Time        `json:"resolved_at,omitempty"`
+}
+
+type Manager struct {
+	logger   *zap.SugaredLogger
+	storage  MetricsFinder
+	rules    []Rule
+	notifier Notifier
+	ticker   time.Duration
+
+	mu sync.RWMutex
+	// Активные оповещения по имени правила и ключу серии
+	active map[string]map[string]*Alert
+}
+
+func New(
+	logger *zap.SugaredLogger,
+	storage MetricsFinder,
+	rules []Rule,
+	notifier Notifier,
+	interval int,
+) *Manager {
+	return &Manager{
+		logger:   logger.Named("alerting"),
+		storage:  storage,
+		rules:    rules,
+		notifier: notifier,
+		ticker:   time.Duration(interval) * time.Second,
+		active:   make(map[string]map[string]*Alert),
+	}
+}
+
+func (m *Manager) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	m.logger.Infof("Alerting was started with %d rules", len(m.rules))
+	t := time.NewTicker(m.ticker)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			{
+				m.logger.Info("Alerting was stopped")
+				return
+			}
+		case now := <-t.C:
+			{
+				m.Evaluate(ctx, now)
+			}
+		}
+	}
+}
+
+// Метод Evaluate проверяет все правила и отправляет оповещения о
+// переходах в firing и resolved. Если метрики правила не удалось
+// прочитать, состояние его оповещений не меняется.
+func (m *Manager) Evaluate(ctx context.Context, now time.Time) {
+	var changed []Alert
+	for _, rule := range m.rules {
+		series, err := m.storage.FindMetrics(ctx,
+			metric.Metrics{ID: rule.Metric, MType: rule.Type}, rule.matchers)
+		if err != nil {
+			m.logger.Errorf("Rule %s evaluation error: %v", rule.Name, err)
+			continue
+		}
+		changed = append(changed, m.evaluateRule(rule, series, now)...)
+	}
+	if len(changed) == 0 || m.notifier == nil {
+		return
+	}
+	if err := m.notifier.Notify(ctx, changed); err != nil {
+		m.logger.Errorf("Notify error: %v", err)
+	}
+}
+
+// Метод evaluateRule обновляет оповещения правила и возвращает
+// оповещения, изменившие состояние на firing или resolved
+func (m *Manager) evaluateRule(rule Rule, series []metric.Metrics, now time.Time) []Alert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	prev := m.active[rule.Name]
+	active := make(map[string]*Alert)
+	var changed []Alert
+	for _, s := range series {
+		value, ok := metricValue(s)
+		if !ok || !rule.Op.Compare(value, rule.Threshold) {
+			continue
+		}
+		key := metric.SeriesKey(s.ID, s.Labels)
+		a, ok := prev[key]
+		if !ok {
+			a = &Alert{
+				Rule:      rule.Name,
+				Severity:  rule.Severity,
+				Metric:    s.ID,
+				Labels:    s.Labels,
+				Op:        rule.Op,
+				Threshold: rule.Threshold,
+				State:     StatePending,
+				ActiveAt:  now,
+			}
+		}
+		a.Value = value
+		if a.State == StatePending && now.Sub(a.ActiveAt) >= time.Duration(rule.For) {
+			firedAt := now
+			a.State, a.FiredAt = StateFiring, &firedAt
+			changed = append(changed, *a)
+		}
+		active[key] = a
+	}
+	// Сработавшие оповещения, условие которых больше не выполняется
+	for key, a := range prev {
+		if _, ok := active[key]; ok || a.State != StateFiring {
+			continue
+		}
+		resolvedAt := now
+		a.State, a.ResolvedAt = StateResolved, &resolvedAt
+		changed = append(changed, *a)
+	}
+	m.active[rule.Name] = active
+	return changed
+}
+
+// Метод Alerts возвращает активные оповещения в состояниях pending и
+// firing, упорядоченные по правилу и серии
+func (m *Manager) Alerts() []Alert {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	type keyed struct {
+		key   string
+		alert Alert
+	}
+	var all []keyed
+	for rule, alerts := range m.active {
+		for key, a := range alerts {
+			all = append(all, keyed{key: rule + "/" + key, alert: *a})
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].key < all[j].key })
+	res := make([]Alert, 0, len(all))
+	for _, a := range all {
+		res = append(res, a.alert)
+	}
+	return res
+}
+
+// Функция metricValue возвращает значение gauge или counter
+func metricValue(m metric.Metrics) (float64, bool) {
+	switch {
+	case m.Value != nil:
+		return *m.Value, true
+	case m.Delta != nil:
+		return float64(*m.Delta), true
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`{"rules": [{"name": "LowFreeMemory", "metric": "FreeMemory", "type": "gauge",
+		"match": ["host=~web.*"], "op": "<", "threshold": 524288000, "for": "5m", "severity": "critical"}]}`))
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, Duration(5*time.Minute), rules[0].For)
+	require.Len(t, rules[0].matchers, 1)
+
+	for _, data := range []string{
+		`{"rules": [{"name": "a", "metric": "m", "type": "histogram", "op": "<"}]}`,
+		`{"rules": [{"name": "a", "metric": "m", "type": "gauge", "op": "<>"}]}`,
+		`{"rules": [{"name": "a", "metric": "m", "type": "gauge", "op": "<", "for": "soon"}]}`,
+		`{"rules": [{"name": "a", "metric": "m", "type": "gauge", "op": "<", "match": ["host"]}]}`,
+		`{"rules": [{"name": "a", "metric": "m", "type": "gauge", "op": "<"}, {"name": "a", "metric": "m", "type": "gauge", "op": ">"}]}`,
+	} {
+		_, err = ParseRules([]byte(data))
+		require.ErrorIs(t, err, ErrInvalidRule, data)
+	}
+}
+
+func TestManager_Evaluate(t *testing.T) {
+	rules, err := ParseRules([]byte(`{"rules": [{"name": "LowFreeMemory", "metric": "FreeMemory", "type": "gauge",
+		"op": "<", "threshold": 500, "for": "1m", "severity": "critical"}]}`))
+	require.NoError(t, err)
+
+	values := map[string]float64{"web1": 100, "web2": 1000}
+	var findErr error
+	storage := &MetricsFinderMock{
+		FindMetricsFunc: func(_ context.Context, m metric.Metrics, _ []*metric.LabelMatcher) ([]metric.Metrics, error) {
+			if findErr != nil {
+				return nil, findErr
+			}
+			res := make([]metric.Metrics, 0, len(values))
+			for host, v := range values {
+				v := v
+				res = append(res, metric.Metrics{ID: m.ID, MType: m.MType, Value: &v, Labels: map[string]string{"host": host}})
+			}
+			return res, nil
+		},
+	}
+	var notified [][]Alert
+	notifier := &NotifierMock{
+		NotifyFunc: func(_ context.Context, alerts []Alert) error {
+			notified = append(notified, alerts)
+			return nil
+		},
+	}
+	m := New(zaptest.NewLogger(t).Sugar(), storage, rules, notifier, 1)
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0).UTC()
+
+	m.Evaluate(ctx, now)
+	alerts := m.Alerts()
+	require.Len(t, alerts, 1)
+	require.Equal(t, StatePending, alerts[0].State)
+	require.Equal(t, "web1", alerts[0].Labels["host"])
+	require.Empty(t, notified)
+
+	m.Evaluate(ctx, now.Add(time.Minute))
+	require.Equal(t, StateFiring, m.Alerts()[0].State)
+	require.Len(t, notified, 1)
+	require.Equal(t, StateFiring, notified[0][0].State)
+
+	// Ошибка чтения не разрешает оповещения
+	findErr = errors.New("storage is unavailable")
+	m.Evaluate(ctx, now.Add(2*time.Minute))
+	require.Len(t, m.Alerts(), 1)
+	findErr = nil
+
+	values["web1"] = 800
+	values["web2"] = 200
+	m.Evaluate(ctx, now.Add(3*time.Minute))
+	alerts = m.Alerts()
+	require.Len(t, alerts, 1)
+	require.Equal(t, "web2", alerts[0].Labels["host"])
+	require.Equal(t, StatePending, alerts[0].State)
+	require.Len(t, notified, 2)
+	require.Equal(t, StateResolved, notified[1][0].State)
+	require.Equal(t, "web1", notified[1][0].Labels["host"])
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got struct {
+		Alerts []Alert `json:"alerts"`
+	}
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	n := NewWebhookNotifier(ok.URL + ", " + failing.URL)
+	err := n.Notify(context.Background(), []Alert{{Rule: "LowFreeMemory", State: StateFiring}})
+	require.Error(t, err)
+	require.Len(t, got.Alerts, 1)
+	require.Equal(t, "LowFreeMemory", got.Alerts[0].Rule)
+}
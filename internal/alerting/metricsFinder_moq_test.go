@@ -0,0 +1,89 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package alerting
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Ensure, that MetricsFinderMock does implement MetricsFinder.
+// If this is not the case, regenerate this file with moq.
+var _ MetricsFinder = &MetricsFinderMock{}
+
+// MetricsFinderMock is a mock implementation of MetricsFinder.
+//
+//	func TestSomethingThatUsesMetricsFinder(t *testing.T) {
+//
+//		// make and configure a mocked MetricsFinder
+//		mockedMetricsFinder := &MetricsFinderMock{
+//			FindMetricsFunc: func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error) {
+//				panic("mock out the FindMetrics method")
+//			},
+//		}
+//
+//		// use mockedMetricsFinder in code that requires MetricsFinder
+//		// and then make assertions.
+//
+//	}
+type MetricsFinderMock struct {
+	// FindMetricsFunc mocks the FindMetrics method.
+	FindMetricsFunc func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// FindMetrics holds details about calls to the FindMetrics method.
+		FindMetrics []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+			// LabelMatchers is the labelMatchers argument value.
+			LabelMatchers []*metric.LabelMatcher
+		}
+	}
+	lockFindMetrics sync.RWMutex
+}
+
+// FindMetrics calls FindMetricsFunc.
+func (mock *MetricsFinderMock) FindMetrics(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error) {
+	if mock.FindMetricsFunc == nil {
+		panic("MetricsFinderMock.FindMetricsFunc: method is nil but MetricsFinder.FindMetrics was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+		LabelMatchers:   labelMatchers,
+	}
+	mock.lockFindMetrics.Lock()
+	mock.calls.FindMetrics = append(mock.calls.FindMetrics, callInfo)
+	mock.lockFindMetrics.Unlock()
+	return mock.FindMetricsFunc(contextMoqParam, metrics, labelMatchers)
+}
+
+// FindMetricsCalls gets all the calls that were made to FindMetrics.
+// Check the length with:
+//
+//	len(mockedMetricsFinder.FindMetricsCalls())
+func (mock *MetricsFinderMock) FindMetricsCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+	LabelMatchers   []*metric.LabelMatcher
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}
+	mock.lockFindMetrics.RLock()
+	calls = mock.calls.FindMetrics
+	mock.lockFindMetrics.RUnlock()
+	return calls
+}
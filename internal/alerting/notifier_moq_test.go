@@ -0,0 +1,81 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package alerting
+
+import (
+	"context"
+	"sync"
+)
+
+// Ensure, that NotifierMock does implement Notifier.
+// If this is not the case, regenerate this file with moq.
+var _ Notifier = &NotifierMock{}
+
+// NotifierMock is a mock implementation of Notifier.
+//
+//	func TestSomethingThatUsesNotifier(t *testing.T) {
+//
+//		// make and configure a mocked Notifier
+//		mockedNotifier := &NotifierMock{
+//			NotifyFunc: func(contextMoqParam context.Context, alerts []Alert) error {
+//				panic("mock out the Notify method")
+//			},
+//		}
+//
+//		// use mockedNotifier in code that requires Notifier
+//		// and then make assertions.
+//
+//	}
+type NotifierMock struct {
+	// NotifyFunc mocks the Notify method.
+	NotifyFunc func(contextMoqParam context.Context, alerts []Alert) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Notify holds details about calls to the Notify method.
+		Notify []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Alerts is the alerts argument value.
+			Alerts []Alert
+		}
+	}
+	lockNotify sync.RWMutex
+}
+
+// Notify calls NotifyFunc.
+func (mock *NotifierMock) Notify(contextMoqParam context.Context, alerts []Alert) error {
+	if mock.NotifyFunc == nil {
+		panic("NotifierMock.NotifyFunc: method is nil but Notifier.Notify was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Alerts          []Alert
+	}{
+		ContextMoqParam: contextMoqParam,
+		Alerts:          alerts,
+	}
+	mock.lockNotify.Lock()
+	mock.calls.Notify = append(mock.calls.Notify, callInfo)
+	mock.lockNotify.Unlock()
+	return mock.NotifyFunc(contextMoqParam, alerts)
+}
+
+// NotifyCalls gets all the calls that were made to Notify.
+// Check the length with:
+//
+//	len(mockedNotifier.NotifyCalls())
+func (mock *NotifierMock) NotifyCalls() []struct {
+	ContextMoqParam context.Context
+	Alerts          []Alert
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Alerts          []Alert
+	}
+	mock.lockNotify.RLock()
+	calls = mock.calls.Notify
+	mock.lockNotify.RUnlock()
+	return calls
+}
@@ -0,0 +1,141 @@
+package alerting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+var (
+	ErrInvalidRule = errors.New("invalid alerting rule")
+)
+
+// Тип Op - оператор сравнения значения метрики с порогом
+type Op string
+
+// Перечень операторов сравнения
+const (
+	OpLess         = Op("<")
+	OpLessEqual    = Op("<=")
+	OpGreater      = Op(">")
+	OpGreaterEqual = Op(">=")
+	OpEqual        = Op("==")
+	OpNotEqual     = Op("!=")
+)
+
+// Метод Compare проверяет условие value op threshold
+func (o Op) Compare(value, threshold float64) bool {
+	switch o {
+	case OpLess:
+		return value < threshold
+	case OpLessEqual:
+		return value <= threshold
+	case OpGreater:
+		return value > threshold
+	case OpGreaterEqual:
+		return value >= threshold
+	case OpEqual:
+		return value == threshold
+	case OpNotEqual:
+		return value != threshold
+	}
+	return false
+}
+
+func (o Op) valid() bool {
+	switch o {
+	case OpLess, OpLessEqual, OpGreater, OpGreaterEqual, OpEqual, OpNotEqual:
+		return true
+	}
+	return false
+}
+
+// Тип Duration - длительность, задаваемая в файле правил строкой
+// в формате time.Duration, например "5m"
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Тип Rule - правило оповещения. Оповещение создается для каждой серии
+// метрики, удовлетворяющей условиям на метки Match, значение которой
+// удовлетворяет условию Op Threshold. Оповещение срабатывает, если
+// условие выполняется не меньше For.
+type Rule struct {
+	Name      string   `json:"name"`
+	Metric    string   `json:"metric"`
+	Type      string   `json:"type"`
+	Match     []string `json:"match,omitempty"`
+	Op        Op       `json:"op"`
+	Threshold float64  `json:"threshold"`
+	For       Duration `json:"for"`
+	Severity  string   `json:"severity"`
+
+	matchers []*metric.LabelMatcher
+}
+
+// Тип rulesFile - содержимое файла правил
+type rulesFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Функция LoadRules читает правила из JSON-файла вида
+// {"rules": [{"name": "LowFreeMemory", "metric": "FreeMemory",
+// "type": "gauge", "match": ["host=~.+"], "op": "<",
+// "threshold": 524288000, "for": "5m", "severity": "critical"}]}
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(data)
+}
+
+// Функция ParseRules разбирает и проверяет правила
+func ParseRules(data []byte) ([]Rule, error) {
+	var f rulesFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, errors.Join(ErrInvalidRule, err)
+	}
+	names := make(map[string]bool, len(f.Rules))
+	for i := range f.Rules {
+		r := &f.Rules[i]
+		if r.Name == "" || r.Metric == "" || names[r.Name] {
+			return nil, fmt.Errorf("%w: rule %d must have a unique name and a metric", ErrInvalidRule, i)
+		}
+		names[r.Name] = true
+		if r.Type != metric.TypeGauge.String() && r.Type != metric.TypeCounter.String() {
+			return nil, fmt.Errorf("%w: %s: type must be gauge or counter", ErrInvalidRule, r.Name)
+		}
+		if !r.Op.valid() {
+			return nil, fmt.Errorf("%w: %s: unknown operator %q", ErrInvalidRule, r.Name, r.Op)
+		}
+		if r.For < 0 {
+			return nil, fmt.Errorf("%w: %s: negative for", ErrInvalidRule, r.Name)
+		}
+		matchers, err := metric.ParseLabelMatchers(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidRule, r.Name, err)
+		}
+		r.matchers = matchers
+	}
+	return f.Rules, nil
+}
@@ -0,0 +1,71 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Время ожидания ответа webhook
+	webhookTimeout = 10 * time.Second
+)
+
+// Тип WebhookNotifier отправляет оповещения POST-запросом с телом
+// {"alerts": [...]} в формате JSON на каждый из адресов
+type WebhookNotifier struct {
+	urls   []string
+	client *http.Client
+}
+
+// Функция NewWebhookNotifier возвращает WebhookNotifier для адресов,
+// перечисленных через запятую
+func NewWebhookNotifier(urls string) *WebhookNotifier {
+	n := &WebhookNotifier{client: &http.Client{Timeout: webhookTimeout}}
+	for _, u := range strings.Split(urls, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			n.urls = append(n.urls, u)
+		}
+	}
+	return n
+}
+
+// Метод Notify отправляет оповещения на все адреса. Ошибка отправки
+// на один адрес не прерывает отправку на остальные.
+func (n *WebhookNotifier) Notify(ctx context.Context, alerts []Alert) error {
+	body, err := json.Marshal(struct {
+		Alerts []Alert `json:"alerts"`
+	}{Alerts: alerts})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, u := range n.urls {
+		if err := n.post(ctx, u, body); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", u, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
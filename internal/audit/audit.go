@@ -0,0 +1,33 @@
+// Пакет audit записывает журнал аудита разрушающих операций
+package audit
+
+import (
+	"go.uber.org/zap"
+)
+
+// Имя журнала аудита
+const loggerName = "audit"
+
+// Тип Logger записывает строку журнала аудита для каждой разрушающей
+// операции: действие, источник запроса, параметры и результат
+type Logger struct {
+	logger *zap.SugaredLogger
+}
+
+// Функция New возвращает журнал аудита на основе logger
+func New(logger *zap.SugaredLogger) *Logger {
+	return &Logger{logger: logger.Named(loggerName)}
+}
+
+// Метод Log записывает строку аудита. Параметры операции передаются
+// парами ключ-значение, как в zap.SugaredLogger.Infow.
+func (a *Logger) Log(action, source string, err error, keysAndValues ...any) {
+	kv := make([]any, 0, len(keysAndValues)+6)
+	kv = append(kv, "action", action, "source", source)
+	kv = append(kv, keysAndValues...)
+	if err != nil {
+		a.logger.Warnw("audit", append(kv, "result", "error", "error", err.Error())...)
+		return
+	}
+	a.logger.Infow("audit", append(kv, "result", "success")...)
+}
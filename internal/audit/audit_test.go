@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogger_Log(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	a := New(zap.New(core).Sugar())
+
+	a.Log("DeleteMetric", "10.0.0.1", nil, "type", "gauge", "name", "CPUutilization7", "deleted", 1)
+	a.Log("ResetCounter", "10.0.0.1", errors.New("metric doesn't exist"), "name", "PollCount")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	require.Equal(t, "audit", entries[0].LoggerName)
+	require.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	require.Equal(t, map[string]any{
+		"action":  "DeleteMetric",
+		"source":  "10.0.0.1",
+		"type":    "gauge",
+		"name":    "CPUutilization7",
+		"deleted": int64(1),
+		"result":  "success",
+	}, entries[0].ContextMap())
+	require.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	require.Equal(t, "error", entries[1].ContextMap()["result"])
+	require.Equal(t, "metric doesn't exist", entries[1].ContextMap()["error"])
+}
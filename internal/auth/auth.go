@@ -0,0 +1,123 @@
+// Пакет auth проверяет токены доступа к API. Каждый токен имеет набор
+// областей доступа: чтение, запись и администрирование.
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Тип Scope - область доступа токена
+type Scope string
+
+const (
+	// Чтение метрик
+	ScopeRead Scope = "read"
+	// Запись метрик
+	ScopeWrite Scope = "write"
+	// Административные операции, включает чтение и запись
+	ScopeAdmin Scope = "admin"
+)
+
+const (
+	// HTTP-заголовок с токеном доступа
+	Header = "X-API-Key"
+	// Ключ метаданных gRPC с токеном доступа
+	MetadataKey = "x-api-key"
+)
+
+var (
+	ErrInvalidTokens = errors.New("invalid api tokens")
+	ErrInvalidScope  = errors.New("invalid token scope")
+	ErrTokenRequired = errors.New("api token required")
+	ErrInvalidToken  = errors.New("invalid api token")
+	ErrForbidden     = errors.New("token scope is insufficient")
+)
+
+// Тип Tokens - токены доступа и их области <Token, Scopes>. Пустой
+// набор токенов отключает проверку.
+type Tokens map[string][]Scope
+
+// Функция ParseScope разбирает область доступа
+func ParseScope(s string) (Scope, error) {
+	switch scope := Scope(s); scope {
+	case ScopeRead, ScopeWrite, ScopeAdmin:
+		return scope, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidScope, s)
+}
+
+// Функция ParseTokens разбирает токены доступа вида
+// "token1:read+write,token2:admin"
+func ParseTokens(s string) (Tokens, error) {
+	tokens := make(Tokens)
+	if s == "" {
+		return tokens, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		token, scopes, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || token == "" || scopes == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTokens, part)
+		}
+		if _, ok := tokens[token]; ok {
+			return nil, fmt.Errorf("%w: duplicate token", ErrInvalidTokens)
+		}
+		for _, name := range strings.Split(scopes, "+") {
+			scope, err := ParseScope(name)
+			if err != nil {
+				return nil, err
+			}
+			tokens[token] = append(tokens[token], scope)
+		}
+	}
+	return tokens, nil
+}
+
+// Метод Authorize проверяет, что токен token дает доступ к области scope.
+// Область ScopeAdmin дает доступ ко всем областям.
+func (t Tokens) Authorize(token string, scope Scope) error {
+	if len(t) == 0 {
+		return nil
+	}
+	if token == "" {
+		return ErrTokenRequired
+	}
+	var scopes []Scope
+	for tok, s := range t {
+		if subtle.ConstantTimeCompare([]byte(tok), []byte(token)) == 1 {
+			scopes = s
+		}
+	}
+	if scopes == nil {
+		return ErrInvalidToken
+	}
+	for _, s := range scopes {
+		if s == scope || s == ScopeAdmin {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s required", ErrForbidden, scope)
+}
+
+// Метод IsAdmin сообщает, что токен token задан в наборе и имеет область
+// ScopeAdmin. Пустой набор токенов не дает административного доступа.
+func (t Tokens) IsAdmin(token string) bool {
+	return len(t) > 0 && t.Authorize(token, ScopeAdmin) == nil
+}
+
+// Функция MethodScope возвращает область доступа, необходимую для
+// вызова gRPC-метода с полным именем method. Методы административного
+// сервиса adminService требуют ScopeAdmin, методы записи метрик -
+// ScopeWrite, остальные методы - ScopeRead.
+func MethodScope(method, adminService string) Scope {
+	if strings.HasPrefix(method, "/"+adminService+"/") {
+		return ScopeAdmin
+	}
+	name := method[strings.LastIndex(method, "/")+1:]
+	if strings.HasPrefix(name, "Receive") {
+		return ScopeWrite
+	}
+	return ScopeRead
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTokens(t *testing.T) {
+	tokens, err := ParseTokens("agent:write, grafana:read, ops:read+admin")
+	require.NoError(t, err)
+	require.Equal(t, Tokens{
+		"agent":   {ScopeWrite},
+		"grafana": {ScopeRead},
+		"ops":     {ScopeRead, ScopeAdmin},
+	}, tokens)
+
+	tokens, err = ParseTokens("")
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+
+	for _, s := range []string{"agent", "agent:", ":read", "agent:delete", "agent:read,agent:write"} {
+		_, err = ParseTokens(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestTokens_Authorize(t *testing.T) {
+	tokens := Tokens{
+		"agent":   {ScopeWrite},
+		"grafana": {ScopeRead},
+		"ops":     {ScopeAdmin},
+	}
+	tests := []struct {
+		name    string
+		tokens  Tokens
+		token   string
+		scope   Scope
+		wantErr error
+	}{
+		{
+			name:  "disabled",
+			scope: ScopeAdmin,
+		},
+		{
+			name:   "write",
+			tokens: tokens,
+			token:  "agent",
+			scope:  ScopeWrite,
+		},
+		{
+			name:    "write_cannot_read",
+			tokens:  tokens,
+			token:   "agent",
+			scope:   ScopeRead,
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "read_cannot_write",
+			tokens:  tokens,
+			token:   "grafana",
+			scope:   ScopeWrite,
+			wantErr: ErrForbidden,
+		},
+		{
+			name:   "admin_can_read",
+			tokens: tokens,
+			token:  "ops",
+			scope:  ScopeRead,
+		},
+		{
+			name:    "missing",
+			tokens:  tokens,
+			scope:   ScopeRead,
+			wantErr: ErrTokenRequired,
+		},
+		{
+			name:    "unknown",
+			tokens:  tokens,
+			token:   "other",
+			scope:   ScopeRead,
+			wantErr: ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.tokens.Authorize(tt.token, tt.scope)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTokens_IsAdmin(t *testing.T) {
+	tokens := Tokens{"agent": {ScopeWrite}, "ops": {ScopeAdmin}}
+	require.True(t, tokens.IsAdmin("ops"))
+	require.False(t, tokens.IsAdmin("agent"))
+	require.False(t, tokens.IsAdmin("other"))
+	require.False(t, Tokens{}.IsAdmin(""))
+}
+
+func TestMethodScope(t *testing.T) {
+	const admin = "metric_collector_grpc.MetricCollectorAdmin"
+	require.Equal(t, ScopeAdmin, MethodScope("/metric_collector_grpc.MetricCollectorAdmin/DeleteMetric", admin))
+	require.Equal(t, ScopeWrite, MethodScope("/metric_collector_grpc.MetricCollector/ReceiveMetric", admin))
+	require.Equal(t, ScopeWrite, MethodScope("/metric_collector_grpc.MetricCollector/ReceiveMetricBatch", admin))
+	require.Equal(t, ScopeRead, MethodScope("/metric_collector_grpc.MetricCollector/ReadMetricRange", admin))
+	require.Equal(t, ScopeRead, MethodScope("/metric_collector_grpc.MetricCollector/FindMetrics", admin))
+}
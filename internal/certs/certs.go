@@ -0,0 +1,274 @@
+// Пакет certs предоставляет конфигурации TLS для серверов и агента.
+// Сертификат, ключ и сертификаты удостоверяющих центров перечитываются
+// при изменении файлов без перезапуска.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	e "github.com/Eqke/metric-collector/pkg/error"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointNewReloader = "error in certs.NewReloader(): "
+	errPointLoad        = "error in certs.load(): "
+)
+
+var (
+	ErrNoCertificates = errors.New("no certificates found in CA file")
+	ErrNoClientCert   = errors.New("client certificate required")
+)
+
+// Тип Reloader хранит текущие сертификат и пул удостоверяющих центров
+// и перечитывает их при изменении файлов
+type Reloader struct {
+	logger   *zap.SugaredLogger
+	certFile string
+	keyFile  string
+	caFile   string
+	interval time.Duration
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+	mods map[string]time.Time
+}
+
+// Функция NewReloader загружает сертификат certFile с ключом keyFile и
+// сертификаты удостоверяющих центров caFile. Пустые пути пропускаются.
+// Файлы проверяются на изменение каждые interval секунд.
+func NewReloader(
+	logger *zap.SugaredLogger,
+	certFile, keyFile, caFile string,
+	interval int,
+) (*Reloader, error) {
+	r := &Reloader{
+		logger:   logger.Named("certs"),
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		interval: time.Duration(interval) * time.Second,
+	}
+	if err := r.load(); err != nil {
+		return nil, e.WrapError(errPointNewReloader, err)
+	}
+	return r, nil
+}
+
+// Метод Run проверяет изменение файлов до отмены контекста
+func (r *Reloader) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("certificate reloader was stopped")
+			return
+		case <-ticker.C:
+			r.Reload()
+		}
+	}
+}
+
+// Метод Reload перечитывает файлы, если они изменились. При ошибке
+// остаются прежние сертификаты, а файлы перечитываются при следующей
+// проверке.
+func (r *Reloader) Reload() {
+	mods, err := r.modTimes()
+	if err != nil {
+		r.logger.Errorf("check certificates: %v", err)
+		return
+	}
+	r.mu.RLock()
+	changed := false
+	for file, mod := range mods {
+		if !mod.Equal(r.mods[file]) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return
+	}
+	if err = r.load(); err != nil {
+		r.logger.Errorf("reload certificates: %v", err)
+		return
+	}
+	r.logger.Info("certificates were reloaded")
+}
+
+// Метод load читает файлы и заменяет текущие сертификаты
+func (r *Reloader) load() error {
+	mods, err := r.modTimes()
+	if err != nil {
+		return e.WrapError(errPointLoad, err)
+	}
+	var cert *tls.Certificate
+	if r.certFile != "" || r.keyFile != "" {
+		c, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return e.WrapError(errPointLoad, err)
+		}
+		cert = &c
+	}
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		data, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return e.WrapError(errPointLoad, err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return e.WrapError(errPointLoad, fmt.Errorf("%w: %s", ErrNoCertificates, r.caFile))
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = cert
+	r.pool = pool
+	r.mods = mods
+	return nil
+}
+
+// Метод modTimes возвращает время изменения файлов
+func (r *Reloader) modTimes() (map[string]time.Time, error) {
+	mods := make(map[string]time.Time, 3)
+	for _, file := range []string{r.certFile, r.keyFile, r.caFile} {
+		if file == "" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		mods[file] = info.ModTime()
+	}
+	return mods, nil
+}
+
+// Метод Certificate возвращает текущий сертификат или nil
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// Метод Pool возвращает текущий пул удостоверяющих центров или nil
+func (r *Reloader) Pool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+// Метод ServerConfig возвращает конфигурацию TLS сервера. Если задан
+// пул удостоверяющих центров, сертификаты клиентов проверяются по нему,
+// а requireClientCert запрещает подключения без сертификата.
+func (r *Reloader) ServerConfig(requireClientCert bool) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+	}
+	if r.caFile == "" {
+		return cfg
+	}
+	// Пул проверяется при каждом подключении, чтобы учитывать перечитанные
+	// сертификаты, поэтому стандартная проверка tls отключена
+	cfg.ClientAuth = tls.RequestClientCert
+	if requireClientCert {
+		cfg.ClientAuth = tls.RequireAnyClientCert
+	}
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			if requireClientCert {
+				return ErrNoClientCert
+			}
+			return nil
+		}
+		return verify(cs.PeerCertificates, x509.VerifyOptions{
+			Roots:     r.Pool(),
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+	}
+	return cfg
+}
+
+// Метод ClientConfig возвращает конфигурацию TLS клиента. Сертификат
+// клиента передается, если он задан. Без пула удостоверяющих центров
+// сертификат сервера проверяется по системным корневым сертификатам.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.Certificate(); cert != nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+	if r.caFile == "" {
+		return cfg
+	}
+	// Сертификат сервера проверяется в VerifyConnection по текущему пулу
+	cfg.InsecureSkipVerify = true
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		return verify(cs.PeerCertificates, x509.VerifyOptions{
+			DNSName: cs.ServerName,
+			Roots:   r.Pool(),
+		})
+	}
+	return cfg
+}
+
+// Функция verify проверяет цепочку сертификатов certs
+func verify(certs []*x509.Certificate, opts x509.VerifyOptions) error {
+	if len(certs) == 0 {
+		return ErrNoClientCert
+	}
+	opts.Intermediates = x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(opts)
+	return err
+}
+
+// Функция AgentID возвращает идентификатор агента по сертификату
+// клиента: Common Name или первое DNS-имя
+func AgentID(cs *tls.ConnectionState) string {
+	if cs == nil || len(cs.PeerCertificates) == 0 {
+		return ""
+	}
+	cert := cs.PeerCertificates[0]
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	if len(cert.DNSNames) > 0 {
+		return cert.DNSNames[0]
+	}
+	return ""
+}
+
+type contextKey struct{}
+
+// Функция WithAgent возвращает контекст с идентификатором агента id
+func WithAgent(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// Функция AgentFromContext возвращает идентификатор агента из контекста
+func AgentFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,187 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newCert(t *testing.T, cn string, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+		tmpl.DNSNames = []string{"localhost"}
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCert{cert: cert, key: key}
+}
+
+func (c *testCert) write(t *testing.T, dir, name string) (string, string) {
+	t.Helper()
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600))
+	der, err := x509.MarshalECPrivateKey(c.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600))
+	return certFile, keyFile
+}
+
+// Функция handshake выполняет рукопожатие TLS и возвращает состояние
+// соединения на стороне сервера
+func handshake(t *testing.T, server, client *tls.Config) (*tls.ConnectionState, error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	errc := make(chan error, 1)
+	go func() {
+		conn, err := tls.Dial("tcp", ln.Addr().String(), client)
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer conn.Close()
+		// В TLS 1.3 сервер проверяет сертификат клиента после завершения
+		// рукопожатия клиентом, поэтому ошибка приходит при чтении
+		_, err = conn.Read(make([]byte, 1))
+		errc <- err
+	}()
+	raw, err := ln.Accept()
+	require.NoError(t, err)
+	defer raw.Close()
+	conn := tls.Server(raw, server)
+	err = conn.Handshake()
+	if err == nil {
+		_, err = conn.Write([]byte{1})
+	}
+	clientErr := <-errc
+	if err != nil {
+		return nil, err
+	}
+	if clientErr != nil {
+		return nil, clientErr
+	}
+	state := conn.ConnectionState()
+	return &state, nil
+}
+
+func TestReloader_MutualTLS(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil, 0)
+	caFile, _ := ca.write(t, dir, "ca")
+	serverCert, serverKey := newCert(t, "localhost", ca, x509.ExtKeyUsageServerAuth).write(t, dir, "server")
+	agentCert, agentKey := newCert(t, "agent-1", ca, x509.ExtKeyUsageClientAuth).write(t, dir, "agent")
+
+	server, err := NewReloader(l, serverCert, serverKey, caFile, 1)
+	require.NoError(t, err)
+	agent, err := NewReloader(l, agentCert, agentKey, caFile, 1)
+	require.NoError(t, err)
+	anonymous, err := NewReloader(l, "", "", caFile, 1)
+	require.NoError(t, err)
+
+	t.Run("client_cert", func(t *testing.T) {
+		state, err := handshake(t, server.ServerConfig(true), agent.ClientConfig("localhost"))
+		require.NoError(t, err)
+		require.Equal(t, "agent-1", AgentID(state))
+	})
+
+	t.Run("client_cert_required", func(t *testing.T) {
+		_, err := handshake(t, server.ServerConfig(true), anonymous.ClientConfig("localhost"))
+		require.Error(t, err)
+	})
+
+	t.Run("client_cert_optional", func(t *testing.T) {
+		state, err := handshake(t, server.ServerConfig(false), anonymous.ClientConfig("localhost"))
+		require.NoError(t, err)
+		require.Empty(t, AgentID(state))
+	})
+
+	t.Run("wrong_server_name", func(t *testing.T) {
+		_, err := handshake(t, server.ServerConfig(false), anonymous.ClientConfig("example.com"))
+		require.Error(t, err)
+	})
+
+	t.Run("unknown_ca", func(t *testing.T) {
+		other := newCert(t, "other", nil, 0)
+		otherCert, otherKey := newCert(t, "agent-2", other, x509.ExtKeyUsageClientAuth).write(t, t.TempDir(), "agent")
+		stranger, err := NewReloader(l, otherCert, otherKey, caFile, 1)
+		require.NoError(t, err)
+		_, err = handshake(t, server.ServerConfig(true), stranger.ClientConfig("localhost"))
+		require.Error(t, err)
+	})
+}
+
+func TestReloader_Reload(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	dir := t.TempDir()
+	ca := newCert(t, "ca", nil, 0)
+	first := newCert(t, "first", ca, x509.ExtKeyUsageServerAuth)
+	certFile, keyFile := first.write(t, dir, "server")
+
+	r, err := NewReloader(l, certFile, keyFile, "", 1)
+	require.NoError(t, err)
+	require.Equal(t, first.cert.Raw, r.Certificate().Certificate[0])
+
+	// Файлы без изменений не перечитываются
+	r.Reload()
+	require.Equal(t, first.cert.Raw, r.Certificate().Certificate[0])
+
+	second := newCert(t, "second", ca, x509.ExtKeyUsageServerAuth)
+	second.write(t, dir, "server")
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+	require.NoError(t, os.Chtimes(keyFile, later, later))
+	r.Reload()
+	require.Equal(t, second.cert.Raw, r.Certificate().Certificate[0])
+
+	// Поврежденный файл не заменяет текущий сертификат
+	require.NoError(t, os.WriteFile(certFile, []byte("broken"), 0o600))
+	later = later.Add(time.Minute)
+	require.NoError(t, os.Chtimes(certFile, later, later))
+	r.Reload()
+	require.Equal(t, second.cert.Raw, r.Certificate().Certificate[0])
+
+	_, err = NewReloader(l, certFile, keyFile, "", 1)
+	require.Error(t, err)
+}
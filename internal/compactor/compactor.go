@@ -0,0 +1,128 @@
+// Пакет compactor применяет к хранилищу политику хранения значений метрик
+package compactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"go.uber.org/zap"
+)
+
+const (
+	// Обозначение уровня исходных значений в описании политики
+	rawTier = "raw"
+)
+
+var (
+	ErrInvalidTier = errors.New("invalid retention tier")
+)
+
+type CompactProvider interface {
+	Compact(context.Context, []storage.RetentionTier, time.Time) error
+}
+
+type Compactor struct {
+	logger  *zap.SugaredLogger
+	storage CompactProvider
+	tiers   []storage.RetentionTier
+	ticker  time.Duration
+}
+
+func New(
+	logger *zap.SugaredLogger,
+	storage CompactProvider,
+	tiers []storage.RetentionTier,
+	duration int,
+) *Compactor {
+	return &Compactor{
+		logger:  logger.Named("compactor"),
+		storage: storage,
+		tiers:   tiers,
+		ticker:  time.Duration(duration) * time.Second,
+	}
+}
+
+func (c *Compactor) Run(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+	c.logger.Infof("Compactor was started with tiers: %v", c.tiers)
+	t := time.NewTicker(c.ticker)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			{
+				c.logger.Info("Compactor was stopped")
+				return
+			}
+		case now := <-t.C:
+			{
+				c.logger.Info("Compacting...")
+				if err := c.storage.Compact(ctx, c.tiers, now); err != nil {
+					c.logger.Errorf("Compact error: %v", err)
+					continue
+				}
+				c.logger.Info("Compacting was finished")
+			}
+		}
+	}
+}
+
+// Функция ParseTiers разбирает политику хранения вида
+// "raw:1h,1m:7d,1h:90d", где для каждого уровня указаны разрешение
+// и срок хранения. Первым должен идти уровень исходных значений,
+// разрешение каждого следующего уровня должно быть кратно предыдущему.
+// Длительности принимаются в формате time.Duration, а также в днях (7d).
+func ParseTiers(s string) ([]storage.RetentionTier, error) {
+	parts := strings.Split(s, ",")
+	tiers := make([]storage.RetentionTier, 0, len(parts))
+	for i, part := range parts {
+		resolution, retention, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTier, part)
+		}
+		var tier storage.RetentionTier
+		var err error
+		if i == 0 {
+			if resolution != rawTier {
+				return nil, fmt.Errorf("%w: first tier must be %q", ErrInvalidTier, rawTier)
+			}
+		} else {
+			tier.Resolution, err = parseDuration(resolution)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", ErrInvalidTier, part, err)
+			}
+			prev := tiers[i-1].Resolution
+			if tier.Resolution < time.Second || tier.Resolution%time.Second != 0 ||
+				tier.Resolution <= prev || (prev > 0 && tier.Resolution%prev != 0) {
+				return nil, fmt.Errorf("%w: %q: resolution must be whole seconds and a multiple of the previous one", ErrInvalidTier, part)
+			}
+		}
+		tier.Retention, err = parseDuration(retention)
+		if err != nil || tier.Retention <= 0 {
+			return nil, fmt.Errorf("%w: %q: invalid retention", ErrInvalidTier, part)
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+// Функция parseDuration дополняет time.ParseDuration поддержкой дней
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,61 @@
+package compactor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    []storage.RetentionTier
+		wantErr bool
+	}{
+		{
+			name:   "default_policy",
+			policy: "raw:1h,1m:7d,1h:90d",
+			want: []storage.RetentionTier{
+				{Resolution: 0, Retention: time.Hour},
+				{Resolution: time.Minute, Retention: 7 * 24 * time.Hour},
+				{Resolution: time.Hour, Retention: 90 * 24 * time.Hour},
+			},
+		},
+		{
+			name:   "raw_only",
+			policy: "raw:30m",
+			want: []storage.RetentionTier{
+				{Resolution: 0, Retention: 30 * time.Minute},
+			},
+		},
+		{
+			name:    "missing_raw_tier",
+			policy:  "1m:7d",
+			wantErr: true,
+		},
+		{
+			name:    "not_multiple_resolution",
+			policy:  "raw:1h,1m:7d,90s:30d",
+			wantErr: true,
+		},
+		{
+			name:    "invalid_retention",
+			policy:  "raw:abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTiers(tt.policy)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -118,6 +118,10 @@ func GetPublicKey(keysPath string) (*rsa.PublicKey, error) {
 	return publicKey, nil
 }
 
+// Функция Encrypt шифрует данные поблочно RSA PKCS#1 v1.5.
+//
+// Deprecated: используется только для проверки совместимости, агент
+// шифрует данные функцией Seal.
 func Encrypt(key *rsa.PublicKey, data []byte) ([]byte, error) {
 	var encryptedData bytes.Buffer
 
@@ -144,6 +148,8 @@ func Encrypt(key *rsa.PublicKey, data []byte) ([]byte, error) {
 	return encryptedData.Bytes(), nil
 }
 
+// Функция Decrypt расшифровывает данные устаревшего формата Encrypt.
+// Сервер принимает такие данные, пока агенты не перейдут на Seal.
 func Decrypt(key *rsa.PrivateKey, data []byte) ([]byte, error) {
 	chunkSize := key.Size()
 
@@ -0,0 +1,187 @@
+package encrypting
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Формат конверта:
+//
+//	magic "MCE" | версия (1 байт) | [длина идентификатора ключа (1 байт) |
+//	идентификатор ключа] | длина ключа (2 байта, big-endian) |
+//	ключ AES-256, зашифрованный RSA-OAEP (SHA-256) | nonce AES-GCM |
+//	шифротекст AES-GCM
+//
+// Идентификатор ключа RSA есть только в версии 2. Все поля до
+// шифротекста аутентифицируются AES-GCM как дополнительные данные.
+const (
+	// Версия конверта RSA-OAEP + AES-256-GCM
+	Version1 byte = 1
+	// Версия 1 с идентификатором ключа RSA
+	Version2 byte = 2
+
+	aesKeySize = 32
+	// Размеры nonce и тега AES-GCM по умолчанию
+	nonceSize = 12
+	tagSize   = 16
+)
+
+// Признак конверта в начале данных
+var magic = []byte("MCE")
+
+var (
+	ErrInvalidEnvelope    = errors.New("invalid envelope")
+	ErrUnsupportedVersion = errors.New("unsupported envelope version")
+)
+
+// Функция IsEnvelope сообщает, что данные зашифрованы в формате конверта,
+// а не устаревшим поблочным RSA PKCS#1 v1.5
+func IsEnvelope(data []byte) bool {
+	return len(data) > len(magic) && bytes.HasPrefix(data, magic)
+}
+
+// Функция Seal шифрует данные случайным ключом AES-256-GCM и возвращает
+// конверт с ключом, зашифрованным открытым ключом RSA-OAEP
+func Seal(key *rsa.PublicKey, data []byte) ([]byte, error) {
+	return seal("", key, data)
+}
+
+// Функция SealWithID шифрует данные как Seal и указывает в конверте
+// идентификатор ключа id, по которому получатель выбирает закрытый ключ
+func SealWithID(id string, key *rsa.PublicKey, data []byte) ([]byte, error) {
+	if err := ValidateKeyID(id); err != nil {
+		return nil, err
+	}
+	return seal(id, key, data)
+}
+
+// Функция seal формирует конверт версии 1 или, если задан id, версии 2
+func seal(id string, key *rsa.PublicKey, data []byte) ([]byte, error) {
+	aesKey := make([]byte, aesKeySize)
+	if _, err := rand.Read(aesKey); err != nil {
+		return nil, err
+	}
+	wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, aesKey, nil)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(aesKey)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, len(magic)+4+len(id)+len(wrapped)+gcm.NonceSize())
+	header = append(header, magic...)
+	if id == "" {
+		header = append(header, Version1)
+	} else {
+		header = append(header, Version2, byte(len(id)))
+		header = append(header, id...)
+	}
+	header = binary.BigEndian.AppendUint16(header, uint16(len(wrapped)))
+	header = append(header, wrapped...)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header = append(header, nonce...)
+	return gcm.Seal(header, nonce, data, header), nil
+}
+
+// Тип envelope - разобранный конверт
+type envelope struct {
+	keyID      string
+	wrapped    []byte
+	nonce      []byte
+	ciphertext []byte
+	header     []byte
+}
+
+// Функция KeyID возвращает идентификатор ключа конверта. Конверт
+// версии 1 не содержит идентификатора.
+func KeyID(data []byte) (string, error) {
+	env, err := parse(data)
+	if err != nil {
+		return "", err
+	}
+	return env.keyID, nil
+}
+
+// Функция Open проверяет и расшифровывает конверт закрытым ключом RSA
+func Open(key *rsa.PrivateKey, data []byte) ([]byte, error) {
+	env, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return env.open(key)
+}
+
+// Функция parse разбирает конверт без расшифровки
+func parse(data []byte) (*envelope, error) {
+	if !IsEnvelope(data) {
+		return nil, ErrInvalidEnvelope
+	}
+	env := &envelope{}
+	rest := data[len(magic):]
+	version := rest[0]
+	rest = rest[1:]
+	switch version {
+	case Version1:
+	case Version2:
+		if len(rest) < 1 || len(rest) < 1+int(rest[0]) {
+			return nil, ErrInvalidEnvelope
+		}
+		env.keyID = string(rest[1 : 1+int(rest[0])])
+		rest = rest[1+int(rest[0]):]
+		if ValidateKeyID(env.keyID) != nil {
+			return nil, ErrInvalidEnvelope
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version)
+	}
+	if len(rest) < 2 {
+		return nil, ErrInvalidEnvelope
+	}
+	keyLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[2:]
+	if len(rest) < keyLen+nonceSize+tagSize {
+		return nil, ErrInvalidEnvelope
+	}
+	env.wrapped = rest[:keyLen]
+	env.nonce = rest[keyLen : keyLen+nonceSize]
+	env.ciphertext = rest[keyLen+nonceSize:]
+	env.header = data[:len(data)-len(env.ciphertext)]
+	return env, nil
+}
+
+// Метод open расшифровывает конверт закрытым ключом RSA
+func (env *envelope) open(key *rsa.PrivateKey) ([]byte, error) {
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), nil, key, env.wrapped, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	gcm, err := newGCM(aesKey)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, env.nonce, env.ciphertext, env.header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+	return plain, nil
+}
+
+// Функция newGCM возвращает AES-GCM для ключа key
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
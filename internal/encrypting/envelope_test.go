@@ -0,0 +1,70 @@
+package encrypting
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// Пакет больше размера ключа RSA
+	data := bytes.Repeat([]byte(testData), 1000)
+	sealed, err := Seal(&key.PublicKey, data)
+	require.NoError(t, err)
+	require.True(t, IsEnvelope(sealed))
+	require.Equal(t, Version1, sealed[len(magic)])
+
+	opened, err := Open(key, sealed)
+	require.NoError(t, err)
+	require.Equal(t, data, opened)
+
+	t.Run("empty", func(t *testing.T) {
+		sealed, err := Seal(&key.PublicKey, nil)
+		require.NoError(t, err)
+		opened, err := Open(key, sealed)
+		require.NoError(t, err)
+		require.Empty(t, opened)
+	})
+
+	t.Run("wrong_key", func(t *testing.T) {
+		_, err := Open(other, sealed)
+		require.ErrorIs(t, err, ErrInvalidEnvelope)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		for _, i := range []int{len(magic) + 3, len(sealed) - 1} {
+			tampered := bytes.Clone(sealed)
+			tampered[i] ^= 1
+			_, err := Open(key, tampered)
+			require.ErrorIs(t, err, ErrInvalidEnvelope, i)
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		for _, n := range []int{len(magic) + 1, len(magic) + 3, 300, len(sealed) - 1} {
+			_, err := Open(key, sealed[:n])
+			require.ErrorIs(t, err, ErrInvalidEnvelope, n)
+		}
+	})
+
+	t.Run("unsupported_version", func(t *testing.T) {
+		unknown := bytes.Clone(sealed)
+		unknown[len(magic)] = 99
+		_, err := Open(key, unknown)
+		require.ErrorIs(t, err, ErrUnsupportedVersion)
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		legacy, err := Encrypt(&key.PublicKey, []byte(testData))
+		require.NoError(t, err)
+		require.False(t, IsEnvelope(legacy))
+	})
+}
@@ -0,0 +1,382 @@
+package encrypting
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	e "github.com/Eqke/metric-collector/pkg/error"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointLoadKeys = "error in encrypting.loadKeys(): "
+
+	// Суффиксы файлов ключей с идентификатором: <id>.private.pem
+	privateKeySuffix = "." + privateKeyName
+	publicKeySuffix  = "." + publicKeyName
+	// Формат идентификатора ключа, создаваемого GenerateKey
+	keyIDLayout = "20060102T150405Z"
+)
+
+var (
+	ErrInvalidKeyID = errors.New("invalid key id")
+	ErrUnknownKey   = errors.New("unknown encryption key")
+	ErrNoKeys       = errors.New("no encryption keys")
+	ErrKeyExists    = errors.New("encryption key already exists")
+)
+
+// Допустимый идентификатор ключа
+var keyIDRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// Функция ValidateKeyID проверяет идентификатор ключа: латинские буквы,
+// цифры, '_', '-', не длиннее 64 символов
+func ValidateKeyID(id string) error {
+	if !keyIDRe.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidKeyID, id)
+	}
+	return nil
+}
+
+// Тип keySet - ключи каталога. Ключ без идентификатора - это пара
+// private.pem/public.pem, созданная GenerateIfNotExist.
+type keySet struct {
+	private map[string]*rsa.PrivateKey
+	public  map[string]*rsa.PublicKey
+	created map[string]time.Time
+	current string
+}
+
+// Тип Keyring хранит ключи шифрования каталога и перечитывает его, чтобы
+// новые ключи применялись без перезапуска. Агент шифрует данные самым
+// новым открытым ключом, сервер выбирает закрытый ключ по идентификатору
+// из конверта.
+type Keyring struct {
+	logger   *zap.SugaredLogger
+	dir      string
+	interval time.Duration
+
+	mu   sync.RWMutex
+	keys *keySet
+}
+
+// Функция NewKeyring загружает ключи каталога dir. Каталог
+// перечитывается каждые interval секунд. Если в каталоге нет ключей,
+// возвращается ErrNoKeys.
+func NewKeyring(logger *zap.SugaredLogger, dir string, interval int) (*Keyring, error) {
+	if dir == "" {
+		dir = "."
+	}
+	keys, err := loadKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Keyring{
+		logger:   logger.Named("keyring"),
+		dir:      dir,
+		interval: time.Duration(interval) * time.Second,
+		keys:     keys,
+	}, nil
+}
+
+// Метод Run перечитывает каталог ключей до отмены контекста
+func (k *Keyring) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(k.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			k.logger.Info("keyring was stopped")
+			return
+		case <-ticker.C:
+			if err := k.Reload(); err != nil {
+				k.logger.Errorf("reload keys: %v", err)
+			}
+		}
+	}
+}
+
+// Метод Reload перечитывает каталог ключей. При ошибке остаются
+// прежние ключи.
+func (k *Keyring) Reload() error {
+	keys, err := loadKeys(k.dir)
+	if err != nil {
+		return err
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if keys.current != k.keys.current {
+		k.logger.Infof("current encryption key: %q", keys.current)
+	}
+	k.keys = keys
+	return nil
+}
+
+// Метод Current возвращает идентификатор текущего ключа
+func (k *Keyring) Current() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.keys.current
+}
+
+// Тип KeyInfo описывает ключ каталога
+type KeyInfo struct {
+	ID      string
+	Created time.Time
+	Private bool
+}
+
+// Метод Keys возвращает ключи от старых к новым
+func (k *Keyring) Keys() []KeyInfo {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	ids := k.keys.ids()
+	keys := make([]KeyInfo, 0, len(ids))
+	for _, id := range ids {
+		_, private := k.keys.private[id]
+		keys = append(keys, KeyInfo{ID: id, Created: k.keys.created[id], Private: private})
+	}
+	return keys
+}
+
+// Метод Seal шифрует данные текущим открытым ключом и указывает его
+// идентификатор в конверте
+func (k *Keyring) Seal(data []byte) ([]byte, error) {
+	k.mu.RLock()
+	id, key := k.keys.current, k.keys.public[k.keys.current]
+	k.mu.RUnlock()
+	if key == nil {
+		return nil, ErrNoKeys
+	}
+	if id == "" {
+		return Seal(key, data)
+	}
+	return SealWithID(id, key, data)
+}
+
+// Метод Open расшифровывает данные закрытым ключом с идентификатором
+// из конверта. Конверты без идентификатора и данные устаревшего формата
+// расшифровываются ключом без идентификатора, а если его нет - текущим.
+func (k *Keyring) Open(data []byte) ([]byte, error) {
+	id := ""
+	if IsEnvelope(data) {
+		var err error
+		if id, err = KeyID(data); err != nil {
+			return nil, err
+		}
+	}
+	k.mu.RLock()
+	key, ok := k.keys.private[id]
+	if !ok && id == "" {
+		key, ok = k.keys.private[k.keys.current]
+	}
+	k.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, id)
+	}
+	if IsEnvelope(data) {
+		return Open(key, data)
+	}
+	return Decrypt(key, data)
+}
+
+// Функция loadKeys читает ключи каталога dir
+func loadKeys(dir string) (*keySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, e.WrapError(errPointLoadKeys, err)
+	}
+	keys := &keySet{
+		private: make(map[string]*rsa.PrivateKey),
+		public:  make(map[string]*rsa.PublicKey),
+		created: make(map[string]time.Time),
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		id, private, ok := parseKeyFile(name)
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, e.WrapError(errPointLoadKeys, err)
+		}
+		if private {
+			key, err := parsePrivateKey(data)
+			if err != nil {
+				return nil, e.WrapError(errPointLoadKeys, fmt.Errorf("%s: %w", name, err))
+			}
+			keys.private[id] = key
+			if _, ok := keys.public[id]; !ok {
+				keys.public[id] = &key.PublicKey
+			}
+		} else {
+			key, err := parsePublicKey(data)
+			if err != nil {
+				return nil, e.WrapError(errPointLoadKeys, fmt.Errorf("%s: %w", name, err))
+			}
+			keys.public[id] = key
+		}
+		if _, ok := keys.created[id]; !ok {
+			keys.created[id] = keyCreated(id, entry)
+		}
+	}
+	if len(keys.public) == 0 {
+		// Ошибка не оборачивается, чтобы ее можно было проверить errors.Is
+		return nil, fmt.Errorf("%w in %s", ErrNoKeys, dir)
+	}
+	ids := keys.ids()
+	keys.current = ids[len(ids)-1]
+	return keys, nil
+}
+
+// Метод ids возвращает идентификаторы ключей от старых к новым
+func (s *keySet) ids() []string {
+	ids := make([]string, 0, len(s.public))
+	for id := range s.public {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		ci, cj := s.created[ids[i]], s.created[ids[j]]
+		if !ci.Equal(cj) {
+			return ci.Before(cj)
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// Функция parseKeyFile возвращает идентификатор ключа по имени файла
+// и признак закрытого ключа
+func parseKeyFile(name string) (string, bool, bool) {
+	switch name {
+	case privateKeyName:
+		return "", true, true
+	case publicKeyName:
+		return "", false, true
+	}
+	if id, ok := strings.CutSuffix(name, privateKeySuffix); ok && ValidateKeyID(id) == nil {
+		return id, true, true
+	}
+	if id, ok := strings.CutSuffix(name, publicKeySuffix); ok && ValidateKeyID(id) == nil {
+		return id, false, true
+	}
+	return "", false, false
+}
+
+// Функция keyCreated возвращает время создания ключа: время из
+// идентификатора GenerateKey или время изменения файла
+func keyCreated(id string, entry os.DirEntry) time.Time {
+	if created, err := time.Parse(keyIDLayout, id); err == nil {
+		return created
+	}
+	if info, err := entry.Info(); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+// Функция parsePrivateKey разбирает закрытый ключ RSA в формате PEM
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// Функция parsePublicKey разбирает открытый ключ RSA в формате PEM
+func parsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data")
+	}
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
+
+// Функция GenerateKey создает в каталоге dir пару ключей с
+// идентификатором по времени now и возвращает идентификатор
+func GenerateKey(dir string, now time.Time) (string, error) {
+	id := now.UTC().Format(keyIDLayout)
+	privateKeyPath := path.Join(dir, id+privateKeySuffix)
+	publicKeyPath := path.Join(dir, id+publicKeySuffix)
+	if _, err := os.Stat(privateKeyPath); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrKeyExists, id)
+	}
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, KeyLengthBits)
+	if err != nil {
+		return "", err
+	}
+	var privateKeyPEM, publicKeyPEM bytes.Buffer
+	if err = pem.Encode(&privateKeyPEM, &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}); err != nil {
+		return "", err
+	}
+	if err = pem.Encode(&publicKeyPEM, &pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&privateKey.PublicKey),
+	}); err != nil {
+		return "", err
+	}
+	// Открытый ключ записывается последним: агент, перечитывающий
+	// каталог, не должен выбрать ключ, закрытой части которого еще нет
+	if err = os.WriteFile(privateKeyPath, privateKeyPEM.Bytes(), 0600); err != nil {
+		return "", err
+	}
+	if err = os.WriteFile(publicKeyPath, publicKeyPEM.Bytes(), 0644); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Функция RetireKeys удаляет из каталога dir ключи, замененные более
+// новым ключом, созданным раньше now-grace, и возвращает их
+// идентификаторы. Текущий ключ не удаляется.
+func RetireKeys(dir string, grace time.Duration, now time.Time) ([]string, error) {
+	keys, err := loadKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+	ids := keys.ids()
+	// Самый новый ключ, которым агенты пользуются дольше grace
+	last := -1
+	for i, id := range ids {
+		if !keys.created[id].After(now.Add(-grace)) {
+			last = i
+		}
+	}
+	var retired []string
+	for _, id := range ids[:max(last, 0)] {
+		for _, name := range keyFileNames(id) {
+			if err = os.Remove(path.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return retired, err
+			}
+		}
+		retired = append(retired, id)
+	}
+	return retired, nil
+}
+
+// Функция keyFileNames возвращает имена файлов ключа с идентификатором id
+func keyFileNames(id string) []string {
+	if id == "" {
+		return []string{privateKeyName, publicKeyName}
+	}
+	return []string{id + privateKeySuffix, id + publicKeySuffix}
+}
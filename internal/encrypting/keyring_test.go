@@ -0,0 +1,172 @@
+package encrypting
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// Функция writeKey записывает в каталог dir пару ключей с именами,
+// соответствующими идентификатору id
+func writeKey(t *testing.T, dir, id string) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	names := keyFileNames(id)
+	require.NoError(t, os.WriteFile(path.Join(dir, names[0]), pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}), 0600))
+	require.NoError(t, os.WriteFile(path.Join(dir, names[1]), pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey),
+	}), 0644))
+	return key
+}
+
+// Функция copyPublicKey копирует открытый ключ id из каталога src в dst
+func copyPublicKey(t *testing.T, src, dst, id string) {
+	t.Helper()
+	name := keyFileNames(id)[1]
+	data, err := os.ReadFile(path.Join(src, name))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path.Join(dst, name), data, 0644))
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	serverDir, agentDir := t.TempDir(), t.TempDir()
+	const oldID, newID = "20260101T000000Z", "20260201T000000Z"
+	writeKey(t, serverDir, oldID)
+	copyPublicKey(t, serverDir, agentDir, oldID)
+
+	server, err := NewKeyring(l, serverDir, 1)
+	require.NoError(t, err)
+	agent, err := NewKeyring(l, agentDir, 1)
+	require.NoError(t, err)
+	require.Equal(t, oldID, agent.Current())
+
+	oldPayload, err := agent.Seal([]byte(testData))
+	require.NoError(t, err)
+	id, err := KeyID(oldPayload)
+	require.NoError(t, err)
+	require.Equal(t, oldID, id)
+
+	// Новый ключ сначала появляется на сервере, затем у агента
+	writeKey(t, serverDir, newID)
+	require.NoError(t, server.Reload())
+	copyPublicKey(t, serverDir, agentDir, newID)
+	require.NoError(t, agent.Reload())
+	require.Equal(t, newID, agent.Current())
+
+	newPayload, err := agent.Seal([]byte(testData))
+	require.NoError(t, err)
+	for _, payload := range [][]byte{oldPayload, newPayload} {
+		data, err := server.Open(payload)
+		require.NoError(t, err)
+		require.Equal(t, testData, string(data))
+	}
+
+	// После удаления старого ключа его конверты не расшифровываются
+	retired, err := RetireKeys(serverDir, time.Hour, time.Date(2026, 2, 1, 1, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, []string{oldID}, retired)
+	require.NoError(t, server.Reload())
+	_, err = server.Open(oldPayload)
+	require.ErrorIs(t, err, ErrUnknownKey)
+	_, err = server.Open(newPayload)
+	require.NoError(t, err)
+}
+
+func TestKeyring_Legacy(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	dir := t.TempDir()
+	key := writeKey(t, dir, "")
+	k, err := NewKeyring(l, dir, 1)
+	require.NoError(t, err)
+	require.Equal(t, "", k.Current())
+
+	sealed, err := Seal(&key.PublicKey, []byte(testData))
+	require.NoError(t, err)
+	legacy, err := Encrypt(&key.PublicKey, []byte(testData))
+	require.NoError(t, err)
+	for _, payload := range [][]byte{sealed, legacy} {
+		data, err := k.Open(payload)
+		require.NoError(t, err)
+		require.Equal(t, testData, string(data))
+	}
+
+	// Ключ без идентификатора используется для конвертов версии 1 и
+	// после появления ключей с идентификаторами
+	writeKey(t, dir, "20260101T000000Z")
+	require.NoError(t, k.Reload())
+	data, err := k.Open(sealed)
+	require.NoError(t, err)
+	require.Equal(t, testData, string(data))
+}
+
+func TestNewKeyring_Empty(t *testing.T) {
+	_, err := NewKeyring(zaptest.NewLogger(t).Sugar(), t.TempDir(), 1)
+	require.ErrorIs(t, err, ErrNoKeys)
+}
+
+func TestRetireKeys(t *testing.T) {
+	dir := t.TempDir()
+	ids := []string{"20260101T000000Z", "20260201T000000Z", "20260301T000000Z"}
+	for _, id := range ids {
+		writeKey(t, dir, id)
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		want []string
+	}{
+		{
+			name: "grace_not_passed",
+			now:  time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "first_retired",
+			now:  time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC),
+			want: ids[:1],
+		},
+		{
+			name: "current_kept",
+			now:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			want: ids[1:2],
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retired, err := RetireKeys(dir, 24*time.Hour, tt.now)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, retired)
+		})
+	}
+	keys, err := loadKeys(dir)
+	require.NoError(t, err)
+	require.Equal(t, ids[2:], keys.ids())
+}
+
+func TestGenerateKey(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 3, 1, 10, 30, 0, 0, time.UTC)
+	id, err := GenerateKey(dir, now)
+	require.NoError(t, err)
+	require.Equal(t, "20260301T103000Z", id)
+	keys, err := loadKeys(dir)
+	require.NoError(t, err)
+	require.Equal(t, id, keys.current)
+	require.Equal(t, now, keys.created[id])
+
+	_, err = GenerateKey(dir, now)
+	require.ErrorIs(t, err, ErrKeyExists)
+}
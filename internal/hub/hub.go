@@ -0,0 +1,141 @@
+// Пакет hub предоставляет внутрипроцессную рассылку принятых метрик
+// подписчикам. Публикация не блокируется: если буфер подписчика
+// заполнен, событие для него отбрасывается.
+package hub
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"go.uber.org/zap"
+)
+
+const (
+	// Размер буфера событий подписчика
+	subscriptionBuffer = 256
+)
+
+// Тип Filter ограничивает события подписки. Подписчик получает метрики
+// только своего арендатора, остальные пустые поля не ограничивают.
+type Filter struct {
+	// Tenant - арендатор подписчика
+	Tenant string
+	// Types - допустимые типы метрик
+	Types []string
+	// Prefix - префикс имени метрики
+	Prefix string
+}
+
+// Метод Match проверяет, удовлетворяет ли метрика арендатора tenant
+// фильтру
+func (f Filter) Match(tenant string, m metric.Metrics) bool {
+	if f.Tenant != tenant {
+		return false
+	}
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			if t == m.MType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return strings.HasPrefix(m.ID, f.Prefix)
+}
+
+// Тип Subscription - подписка на события. Канал C закрывается после
+// Unsubscribe или закрытия Hub.
+type Subscription struct {
+	C <-chan metric.Metrics
+
+	c       chan metric.Metrics
+	filter  Filter
+	dropped atomic.Uint64
+}
+
+// Метод Dropped возвращает число отброшенных из-за переполнения событий
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+type Hub struct {
+	logger *zap.SugaredLogger
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+func New(logger *zap.SugaredLogger) *Hub {
+	return &Hub{
+		logger: logger.Named("hub"),
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Метод Subscribe создает подписку с фильтром. Для закрытого Hub
+// возвращается подписка с уже закрытым каналом.
+func (h *Hub) Subscribe(filter Filter) *Subscription {
+	c := make(chan metric.Metrics, subscriptionBuffer)
+	s := &Subscription{C: c, c: c, filter: filter}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		close(c)
+		return s
+	}
+	h.subs[s] = struct{}{}
+	return s
+}
+
+// Метод Unsubscribe завершает подписку
+func (h *Hub) Unsubscribe(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s]; !ok {
+		return
+	}
+	delete(h.subs, s)
+	close(s.c)
+}
+
+// Метод Publish рассылает метрики арендатора tenant подписчикам,
+// фильтр которых им удовлетворяет
+func (h *Hub) Publish(tenant string, metrics ...metric.Metrics) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.subs {
+		for _, m := range metrics {
+			if !s.filter.Match(tenant, m) {
+				continue
+			}
+			select {
+			case s.c <- m:
+			default:
+				if s.dropped.Add(1) == 1 {
+					h.logger.Warn("Subscriber is too slow, events are dropped")
+				}
+			}
+		}
+	}
+}
+
+// Метод Close завершает все подписки
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	for s := range h.subs {
+		close(s.c)
+	}
+	h.subs = nil
+}
@@ -0,0 +1,62 @@
+package hub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/storage/localstorage"
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestHub(t *testing.T) {
+	h := New(zaptest.NewLogger(t).Sugar())
+	all := h.Subscribe(Filter{Tenant: tenant.Default})
+	counters := h.Subscribe(Filter{Tenant: tenant.Default, Types: []string{"counter"}})
+	other := h.Subscribe(Filter{Tenant: "team-a"})
+
+	value := 1.0
+	for i := 0; i < subscriptionBuffer+10; i++ {
+		h.Publish(tenant.Default, metric.Metrics{ID: "Alloc", MType: "gauge", Value: &value})
+	}
+	// Переполненный подписчик не блокирует публикацию
+	require.Len(t, all.C, subscriptionBuffer)
+	require.Equal(t, uint64(10), all.Dropped())
+	require.Len(t, counters.C, 0)
+	require.Len(t, other.C, 0)
+
+	h.Unsubscribe(counters)
+	_, ok := <-counters.C
+	require.False(t, ok)
+
+	h.Close()
+	require.Len(t, all.C, subscriptionBuffer)
+	_, ok = <-h.Subscribe(Filter{}).C
+	require.False(t, ok)
+}
+
+func TestStorage(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	h := New(l)
+	s := NewStorage(localstorage.New(l), h)
+	sub := h.Subscribe(Filter{Tenant: tenant.Default, Prefix: "Poll"})
+	ctx := context.Background()
+
+	require.NoError(t, s.SetValue(ctx, "counter", `PollCount{host="web1"}`, "5"))
+	require.Error(t, s.SetValue(ctx, "counter", "PollCount", "abc"))
+	delta := int64(2)
+	require.NoError(t, s.SetMetrics(ctx, []metric.Metrics{{ID: "PollCount", MType: "counter", Delta: &delta}}))
+
+	require.Len(t, sub.C, 2)
+	m := <-sub.C
+	require.Equal(t, int64(5), *m.Delta)
+	require.Equal(t, map[string]string{"host": "web1"}, m.Labels)
+	m = <-sub.C
+	require.Equal(t, int64(2), *m.Delta)
+
+	v, err := s.GetValue(ctx, "counter", "PollCount")
+	require.NoError(t, err)
+	require.Equal(t, "2", v)
+}
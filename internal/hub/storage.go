@@ -0,0 +1,82 @@
+package hub
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Тип Storage публикует в Hub метрики, успешно записанные в хранилище.
+// Публикуется принятое изменение, а не итоговое значение: для counter -
+// полученное приращение.
+type Storage struct {
+	storage.Storage
+	hub *Hub
+}
+
+// Функция NewStorage возвращает хранилище, публикующее записи в hub
+func NewStorage(s storage.Storage, hub *Hub) *Storage {
+	return &Storage{Storage: s, hub: hub}
+}
+
+func (s *Storage) SetValue(ctx context.Context, metricType, name, value string) error {
+	if err := s.Storage.SetValue(ctx, metricType, name, value); err != nil {
+		return err
+	}
+	if m, ok := valueToMetric(metricType, name, value); ok {
+		s.hub.Publish(tenant.FromContext(ctx), m)
+	}
+	return nil
+}
+
+func (s *Storage) SetMetric(ctx context.Context, m metric.Metrics) error {
+	if err := s.Storage.SetMetric(ctx, m); err != nil {
+		return err
+	}
+	s.hub.Publish(tenant.FromContext(ctx), m)
+	return nil
+}
+
+func (s *Storage) SetMetrics(ctx context.Context, metrics []metric.Metrics) error {
+	if err := s.Storage.SetMetrics(ctx, metrics); err != nil {
+		return err
+	}
+	s.hub.Publish(tenant.FromContext(ctx), metrics...)
+	return nil
+}
+
+// Функция valueToMetric переводит строковое значение SetValue в
+// metric.Metrics. Имя может быть ключом серии metric.SeriesKey.
+func valueToMetric(metricType, name, value string) (metric.Metrics, bool) {
+	id, labels, err := metric.ParseSeriesKey(name)
+	if err != nil {
+		return metric.Metrics{}, false
+	}
+	m := metric.Metrics{ID: id, MType: metricType, Labels: labels}
+	switch metricType {
+	case metric.TypeGauge.String():
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return m, false
+		}
+		m.Value = &v
+	case metric.TypeCounter.String():
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return m, false
+		}
+		m.Delta = &v
+	case metric.TypeHistogram.String():
+		h, err := metric.ParseHistogram(value)
+		if err != nil {
+			return m, false
+		}
+		m.Histogram = h
+	default:
+		return m, false
+	}
+	return m, true
+}
@@ -0,0 +1,87 @@
+// Пакет janitor отмечает устаревшие метрики и удаляет их из хранилища
+package janitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/audit"
+	"go.uber.org/zap"
+)
+
+const (
+	// Источник записей аудита об удалении устаревших метрик
+	auditSource = "janitor"
+)
+
+type PurgeProvider interface {
+	DeleteStale(context.Context, time.Time) (int, error)
+}
+
+// Тип Janitor периодически удаляет серии, которые не обновлялись
+// дольше purgeAfter
+type Janitor struct {
+	logger     *zap.SugaredLogger
+	storage    PurgeProvider
+	audit      *audit.Logger
+	purgeAfter time.Duration
+	ticker     time.Duration
+}
+
+func New(
+	logger *zap.SugaredLogger,
+	storage PurgeProvider,
+	purgeAfter int,
+	duration int,
+) *Janitor {
+	return &Janitor{
+		logger:     logger.Named("janitor"),
+		storage:    storage,
+		audit:      audit.New(logger),
+		purgeAfter: time.Duration(purgeAfter) * time.Second,
+		ticker:     time.Duration(duration) * time.Second,
+	}
+}
+
+func (j *Janitor) Run(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+) {
+	defer wg.Done()
+	j.logger.Infof("Janitor was started with purge after: %v", j.purgeAfter)
+	t := time.NewTicker(j.ticker)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			{
+				j.logger.Info("Janitor was stopped")
+				return
+			}
+		case now := <-t.C:
+			{
+				if _, err := j.Purge(ctx, now); err != nil {
+					j.logger.Errorf("Purge error: %v", err)
+				}
+			}
+		}
+	}
+}
+
+// Метод Purge удаляет серии, не обновлявшиеся дольше purgeAfter к
+// моменту now, и возвращает их число. Каждое удаление записывается
+// в журнал аудита.
+func (j *Janitor) Purge(ctx context.Context, now time.Time) (int, error) {
+	before := now.Add(-j.purgeAfter)
+	deleted, err := j.storage.DeleteStale(ctx, before)
+	if err != nil || deleted > 0 {
+		j.audit.Log("purge_stale", auditSource, err,
+			"before", before.UTC().Format(time.RFC3339), "deleted", deleted)
+	}
+	if err != nil {
+		return 0, err
+	}
+	j.logger.Infof("Purging was finished, deleted: %d", deleted)
+	return deleted, nil
+}
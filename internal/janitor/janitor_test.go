@@ -0,0 +1,77 @@
+package janitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage/localstorage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestStorage(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	ctx := context.Background()
+	s := NewStorage(localstorage.New(l), time.Minute)
+	require.NoError(t, s.SetValue(ctx, "gauge", "Alloc", "1.5"))
+	require.NoError(t, s.SetValue(ctx, "counter", `PollCount{host="web1"}`, "5"))
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		stale bool
+	}{
+		{
+			name:  "fresh",
+			now:   time.Now(),
+			stale: false,
+		},
+		{
+			name:  "stale",
+			now:   time.Now().Add(2 * time.Minute),
+			stale: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.now = func() time.Time { return tt.now }
+
+			m, err := s.GetMetric(ctx, metric.Metrics{ID: "Alloc", MType: "gauge"})
+			require.NoError(t, err)
+			require.NotNil(t, m.UpdatedAt)
+			require.Equal(t, tt.stale, m.Stale)
+
+			series, err := s.FindMetrics(ctx, metric.Metrics{ID: "PollCount", MType: "counter"}, nil)
+			require.NoError(t, err)
+			require.Len(t, series, 1)
+			require.Equal(t, tt.stale, series[0].Stale)
+
+			all, err := s.GetMetrics(ctx)
+			require.NoError(t, err)
+			require.Len(t, all["gauge"], 1)
+			require.Equal(t, tt.stale, all["gauge"][0].Stale)
+		})
+	}
+}
+
+func TestJanitor_Purge(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	ctx := context.Background()
+	s := localstorage.New(l)
+	require.NoError(t, s.SetValue(ctx, "gauge", "Alloc", "1.5"))
+	require.NoError(t, s.SetValue(ctx, "counter", "PollCount", "5"))
+	j := New(l, s, 60, 60)
+
+	deleted, err := j.Purge(ctx, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, 0, deleted)
+
+	deleted, err = j.Purge(ctx, time.Now().Add(2*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	_, err = s.GetValue(ctx, "gauge", "Alloc")
+	require.Error(t, err)
+}
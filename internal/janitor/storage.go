@@ -0,0 +1,71 @@
+package janitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Тип Storage отмечает в ответах хранилища серии, которые не
+// обновлялись дольше staleAfter. Нулевое значение staleAfter
+// отключает отметку.
+type Storage struct {
+	storage.Storage
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// Функция NewStorage возвращает хранилище, отмечающее устаревшие серии
+func NewStorage(s storage.Storage, staleAfter time.Duration) *Storage {
+	return &Storage{Storage: s, staleAfter: staleAfter, now: time.Now}
+}
+
+func (s *Storage) GetMetric(ctx context.Context, m metric.Metrics) (metric.Metrics, error) {
+	res, err := s.Storage.GetMetric(ctx, m)
+	if err != nil {
+		return res, err
+	}
+	res.Stale = s.isStale(res.UpdatedAt, s.now())
+	return res, nil
+}
+
+func (s *Storage) GetMetrics(ctx context.Context) (map[string][]storage.Metric, error) {
+	res, err := s.Storage.GetMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	for _, metrics := range res {
+		for i := range metrics {
+			metrics[i].Stale = s.isStale(metrics[i].UpdatedAt, now)
+		}
+	}
+	return res, nil
+}
+
+func (s *Storage) FindMetrics(
+	ctx context.Context,
+	m metric.Metrics,
+	matchers []*metric.LabelMatcher,
+) ([]metric.Metrics, error) {
+	res, err := s.Storage.FindMetrics(ctx, m, matchers)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	for i := range res {
+		res[i].Stale = s.isStale(res[i].UpdatedAt, now)
+	}
+	return res, nil
+}
+
+// Метод isStale проверяет, что серия не обновлялась дольше staleAfter.
+// Серии без времени обновления устаревшими не считаются.
+func (s *Storage) isStale(updatedAt *time.Time, now time.Time) bool {
+	if s.staleAfter <= 0 || updatedAt == nil {
+		return false
+	}
+	return now.Sub(*updatedAt) > s.staleAfter
+}
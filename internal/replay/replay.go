@@ -0,0 +1,129 @@
+// Пакет replay защищает подписанные запросы агента от повторной отправки.
+// Запрос содержит время отправки и случайный nonce; запрос со временем
+// вне допустимого окна или с уже встречавшимся nonce отклоняется.
+package replay
+
+import (
+	"container/heap"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	ErrMissing   = errors.New("request timestamp and nonce required")
+	ErrTimestamp = errors.New("invalid request timestamp")
+	ErrStale     = errors.New("request timestamp is outside the allowed window")
+	ErrReplayed  = errors.New("request nonce was already used")
+)
+
+// Максимальная длина nonce
+const maxNonceLen = 128
+
+// Тип Guard хранит nonce запросов, время которых входит в окно window.
+// Число хранимых nonce ограничено size: при переполнении удаляется nonce
+// с самым ранним временем, а запросы не позже этого времени отклоняются.
+type Guard struct {
+	window time.Duration
+	size   int
+	now    func() time.Time
+
+	mu    sync.Mutex
+	seen  map[string]time.Time
+	queue nonceHeap
+	floor time.Time
+}
+
+// Функция New возвращает Guard с окном window и не более size nonce
+func New(window time.Duration, size int) *Guard {
+	return &Guard{
+		window: window,
+		size:   size,
+		now:    time.Now,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Функция ParseTimestamp разбирает время запроса в секундах Unix
+func ParseTimestamp(s string) (time.Time, error) {
+	sec, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrTimestamp
+	}
+	return time.Unix(sec, 0), nil
+}
+
+// Метод Check проверяет время запроса ts и nonce и запоминает nonce
+func (g *Guard) Check(ts time.Time, nonce string) error {
+	if nonce == "" || len(nonce) > maxNonceLen {
+		return ErrMissing
+	}
+	now := g.now()
+	if ts.Before(now.Add(-g.window)) || ts.After(now.Add(g.window)) {
+		return ErrStale
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.expire(now)
+	if !ts.After(g.floor) {
+		return ErrStale
+	}
+	if _, ok := g.seen[nonce]; ok {
+		return ErrReplayed
+	}
+	for g.queue.Len() > 0 && g.queue.Len() >= g.size {
+		oldest := heap.Pop(&g.queue).(entry)
+		delete(g.seen, oldest.nonce)
+		if oldest.ts.After(g.floor) {
+			g.floor = oldest.ts
+		}
+		if !ts.After(g.floor) {
+			return ErrStale
+		}
+	}
+	g.seen[nonce] = ts
+	heap.Push(&g.queue, entry{nonce: nonce, ts: ts})
+	return nil
+}
+
+// Метод Release забывает nonce запроса, который не был выполнен, чтобы
+// агент мог повторить его
+func (g *Guard) Release(nonce string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	// Запись остается в очереди и удаляется при истечении окна
+	delete(g.seen, nonce)
+}
+
+// Метод expire удаляет nonce запросов, время которых вышло из окна
+func (g *Guard) expire(now time.Time) {
+	limit := now.Add(-g.window)
+	for g.queue.Len() > 0 && g.queue[0].ts.Before(limit) {
+		oldest := heap.Pop(&g.queue).(entry)
+		if ts, ok := g.seen[oldest.nonce]; ok && ts.Equal(oldest.ts) {
+			delete(g.seen, oldest.nonce)
+		}
+	}
+}
+
+// Тип entry - nonce и время запроса
+type entry struct {
+	nonce string
+	ts    time.Time
+}
+
+// Тип nonceHeap - очередь nonce по времени запроса
+type nonceHeap []entry
+
+func (h nonceHeap) Len() int           { return len(h) }
+func (h nonceHeap) Less(i, j int) bool { return h[i].ts.Before(h[j].ts) }
+func (h nonceHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *nonceHeap) Push(x any)        { *h = append(*h, x.(entry)) }
+func (h *nonceHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
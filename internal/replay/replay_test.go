@@ -0,0 +1,110 @@
+package replay
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuard_Check(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	tests := []struct {
+		name  string
+		ts    time.Time
+		nonce string
+		err   error
+	}{
+		{
+			name:  "ok",
+			ts:    now,
+			nonce: "a",
+		},
+		{
+			name:  "replayed",
+			ts:    now,
+			nonce: "a",
+			err:   ErrReplayed,
+		},
+		{
+			name:  "skew_allowed",
+			ts:    now.Add(-time.Minute),
+			nonce: "b",
+		},
+		{
+			name:  "too_old",
+			ts:    now.Add(-2 * time.Minute),
+			nonce: "c",
+			err:   ErrStale,
+		},
+		{
+			name:  "too_new",
+			ts:    now.Add(2 * time.Minute),
+			nonce: "d",
+			err:   ErrStale,
+		},
+		{
+			name: "no_nonce",
+			ts:   now,
+			err:  ErrMissing,
+		},
+	}
+	g := New(time.Minute, 10)
+	g.now = func() time.Time { return now }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, g.Check(tt.ts, tt.nonce), tt.err)
+		})
+	}
+}
+
+func TestGuard_Expire(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	g := New(time.Minute, 10)
+	g.now = func() time.Time { return now }
+	require.NoError(t, g.Check(now, "a"))
+
+	now = now.Add(30 * time.Second)
+	require.ErrorIs(t, g.Check(now.Add(-30*time.Second), "a"), ErrReplayed)
+
+	// После выхода из окна nonce забывается, а запрос отклоняется по времени
+	now = now.Add(time.Minute)
+	require.ErrorIs(t, g.Check(now.Add(-90*time.Second), "a"), ErrStale)
+	require.NoError(t, g.Check(now, "b"))
+	require.Len(t, g.seen, 1)
+}
+
+func TestGuard_Bounded(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	g := New(time.Hour, 3)
+	g.now = func() time.Time { return now }
+	for i := range 5 {
+		require.NoError(t, g.Check(now.Add(time.Duration(i)*time.Second), strconv.Itoa(i)))
+	}
+	require.Len(t, g.seen, 3)
+	require.Equal(t, 3, g.queue.Len())
+
+	// Вытесненные nonce нельзя повторить: их время не позже границы
+	require.ErrorIs(t, g.Check(now, "0"), ErrStale)
+	require.ErrorIs(t, g.Check(now.Add(time.Second), "1"), ErrStale)
+	require.ErrorIs(t, g.Check(now.Add(4*time.Second), "4"), ErrReplayed)
+}
+
+func TestGuard_Release(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	g := New(time.Minute, 10)
+	g.now = func() time.Time { return now }
+	require.NoError(t, g.Check(now, "a"))
+	g.Release("a")
+	require.NoError(t, g.Check(now, "a"))
+	require.ErrorIs(t, g.Check(now, "a"), ErrReplayed)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	ts, err := ParseTimestamp("1800000000")
+	require.NoError(t, err)
+	require.Equal(t, time.Unix(1_800_000_000, 0), ts)
+	_, err = ParseTimestamp("yesterday")
+	require.ErrorIs(t, err, ErrTimestamp)
+}
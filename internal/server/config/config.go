@@ -4,9 +4,14 @@ package config
 import (
 	"errors"
 	"flag"
+	"fmt"
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/Eqke/metric-collector/internal/replay"
+	"github.com/Eqke/metric-collector/internal/tenant"
 	e "github.com/Eqke/metric-collector/pkg/error"
 	"github.com/ilyakaznacheev/cleanenv"
 	"os"
+	"time"
 )
 
 const (
@@ -21,11 +26,50 @@ const (
 	defaultRestoreVal = true
 	// Значение адреса gRPC-сервера по умолчанию
 	defaultGrpcAddr = "127.0.0.1:8081"
+	// Политика хранения по умолчанию: исходные значения - час,
+	// минутные агрегаты - неделя, часовые агрегаты - 90 дней
+	defaultRetention = "raw:1h,1m:7d,1h:90d"
+	// Значение периода применения политики хранения по умолчанию
+	defaultCompactInterval = 60
+	// Значение периода сброса метрик StatsD по умолчанию
+	defaultStatsdFlushInterval = 10
+	// Максимальное число соединений Graphite по умолчанию
+	defaultGraphiteMaxConns = 100
+	// Значение периода проверки правил оповещения по умолчанию
+	defaultAlertInterval = 30
+	// Значение периода удаления устаревших метрик по умолчанию
+	defaultJanitorInterval = 60
+	// Значение периода проверки изменения файлов TLS по умолчанию
+	defaultTLSReloadInterval = 10
+	// Значение периода проверки каталога ключей шифрования по умолчанию
+	defaultCryptoKeyReload = 60
+	// Допустимое расхождение времени подписанного запроса в секундах и
+	// число запоминаемых nonce по умолчанию
+	defaultReplayWindow    = 300
+	defaultReplayCacheSize = 100000
+	// Время хранения идентификаторов примененных пакетов по умолчанию
+	defaultBatchIDTTL = 3600
+)
+
+// Режимы миграции схемы базы данных
+const (
+	// Применить недостающие миграции и запустить сервер
+	MigrateAuto = "auto"
+	// Проверить версию схемы без миграции и запустить сервер
+	MigrateVerify = "verify"
+	// Применить недостающие миграции и завершить работу
+	MigrateOnly = "only"
+	// Отменить последнюю миграцию и завершить работу
+	MigrateDown = "down"
 )
 
 var (
 	// Объявление ошибки об недопустимых переменных
 	ErrUnexpectedArguments = errors.New("unexpected arguments")
+	ErrInvalidTLS          = errors.New("invalid tls settings")
+	ErrInvalidReplay       = errors.New("invalid replay protection settings")
+	ErrInvalidMigrate      = errors.New("invalid migrate mode")
+	ErrInvalidInterval     = errors.New("invalid interval")
 )
 
 // Тип ServerConfig представляет структуру для конфигурации сервера
@@ -38,8 +82,64 @@ type ServerConfig struct {
 	DatabaseDSN     string `env:"DATABASE_DSN" json:"database_dsn"`
 	HashKey         string `env:"KEY"`
 	CryptoKey       string `env:"CRYPTO_KEY" json:"crypto_key"`
+	// CryptoKeyReload - период в секундах проверки новых ключей в
+	// каталоге CryptoKey
+	CryptoKeyReload int    `env:"CRYPTO_KEY_RELOAD" json:"crypto_key_reload"`
 	TrustedSubnet   string `env:"TRUSTED_SUBNET" json:"trusted_subnet"`
 	GrpcServerHost  string `env:"GRPC_SERVER_HOST" json:"grpc_server_host"`
+	Retention       string `env:"RETENTION" json:"retention"`
+	CompactInterval int    `env:"COMPACT_INTERVAL" json:"compact_interval"`
+	// StatsdAddress - UDP-адрес приема метрик StatsD, пустое значение
+	// отключает прием
+	StatsdAddress       string `env:"STATSD_ADDRESS" json:"statsd_address"`
+	StatsdFlushInterval int    `env:"STATSD_FLUSH_INTERVAL" json:"statsd_flush_interval"`
+	// GraphiteAddress - TCP-адрес приема метрик Graphite, пустое значение
+	// отключает прием. GraphiteRules - правила извлечения меток из пути.
+	GraphiteAddress  string `env:"GRAPHITE_ADDRESS" json:"graphite_address"`
+	GraphiteRules    string `env:"GRAPHITE_RULES" json:"graphite_rules"`
+	GraphiteMaxConns int    `env:"GRAPHITE_MAX_CONNECTIONS" json:"graphite_max_connections"`
+	// AlertRules - путь к файлу правил оповещения, пустое значение
+	// отключает проверку. AlertWebhooks - адреса через запятую.
+	AlertRules    string `env:"ALERT_RULES" json:"alert_rules"`
+	AlertWebhooks string `env:"ALERT_WEBHOOKS" json:"alert_webhooks"`
+	AlertInterval int    `env:"ALERT_INTERVAL" json:"alert_interval"`
+	// AdminToken - токен доступа к удалению метрик по HTTP и к
+	// административным методам gRPC, пустое значение отключает их, если
+	// не заданы токены доступа области admin
+	AdminToken string `env:"ADMIN_TOKEN" json:"admin_token"`
+	// StaleTTL - время в секундах без обновлений, после которого метрика
+	// отмечается устаревшей, PurgeTTL - после которого она удаляется,
+	// нулевые значения отключают отметку и удаление
+	StaleTTL        int `env:"STALE_TTL" json:"stale_ttl"`
+	PurgeTTL        int `env:"PURGE_TTL" json:"purge_ttl"`
+	JanitorInterval int `env:"JANITOR_INTERVAL" json:"janitor_interval"`
+	// TenantTokens - токены арендаторов в формате tenant:token,tenant:token,
+	// TenantMaxSeries - ограничение числа серий арендатора, 0 - без ограничения
+	TenantTokens    string `env:"TENANT_TOKENS" json:"tenant_tokens"`
+	TenantMaxSeries int    `env:"TENANT_MAX_SERIES" json:"tenant_max_series"`
+	// AuthTokens - токены доступа к API в формате token:scope+scope,...,
+	// области read, write, admin; пустое значение отключает проверку
+	AuthTokens string `env:"AUTH_TOKENS" json:"auth_tokens"`
+	// TLSCert, TLSKey - сертификат и ключ сервера, пустые значения
+	// отключают TLS. TLSClientCA - сертификаты удостоверяющих центров для
+	// проверки клиентов, TLSClientAuth запрещает подключения без
+	// сертификата клиента. Файлы перечитываются при изменении каждые
+	// TLSReloadInterval секунд.
+	TLSCert           string `env:"TLS_CERT" json:"tls_cert"`
+	TLSKey            string `env:"TLS_KEY" json:"tls_key"`
+	TLSClientCA       string `env:"TLS_CLIENT_CA" json:"tls_client_ca"`
+	TLSClientAuth     bool   `env:"TLS_CLIENT_AUTH" json:"tls_client_auth"`
+	TLSReloadInterval int    `env:"TLS_RELOAD_INTERVAL" json:"tls_reload_interval"`
+	// ReplayWindow - допустимое расхождение в секундах времени отправки
+	// подписанного запроса и времени сервера, 0 отключает защиту от
+	// повтора. ReplayCacheSize - число запоминаемых nonce.
+	ReplayWindow    int `env:"REPLAY_WINDOW" json:"replay_window"`
+	ReplayCacheSize int `env:"REPLAY_CACHE_SIZE" json:"replay_cache_size"`
+	// BatchIDTTL - время в секундах, в течение которого сервер помнит
+	// идентификаторы примененных пакетов и не применяет их повторно
+	BatchIDTTL int `env:"BATCH_ID_TTL" json:"batch_id_ttl"`
+	// Migrate - режим миграции схемы базы данных: auto, verify, only, down
+	Migrate string `env:"MIGRATE" json:"migrate"`
 }
 
 // Функция NewServerConfig возвращает экземпляр конфигурации сервера
@@ -54,8 +154,35 @@ func NewServerConfig() (*ServerConfig, error) {
 	flag.BoolVar(&cfg.Restore, "r", defaultRestoreVal, "restore")
 	flag.StringVar(&cfg.DatabaseDSN, "d", "", "Database DSN")
 	flag.StringVar(&cfg.HashKey, "k", "", "hash key")
-	flag.StringVar(&cfg.CryptoKey, "s", "", "path to crypto key")
+	flag.StringVar(&cfg.CryptoKey, "s", "", "crypto keys directory")
+	flag.IntVar(&cfg.CryptoKeyReload, "crypto-key-reload", defaultCryptoKeyReload, "crypto keys directory check interval in seconds")
 	flag.StringVar(&cfg.TrustedSubnet, "t", "", "trusted subnet (CIDR)")
+	flag.StringVar(&cfg.Retention, "retention", defaultRetention, "retention tiers, e.g. raw:1h,1m:7d,1h:90d (empty keeps samples forever)")
+	flag.IntVar(&cfg.CompactInterval, "compact-interval", defaultCompactInterval, "retention compaction interval in seconds")
+	flag.StringVar(&cfg.StatsdAddress, "statsd", "", "statsd udp address (empty disables statsd)")
+	flag.IntVar(&cfg.StatsdFlushInterval, "statsd-flush", defaultStatsdFlushInterval, "statsd flush interval in seconds")
+	flag.StringVar(&cfg.GraphiteAddress, "graphite", "", "graphite tcp address (empty disables graphite)")
+	flag.StringVar(&cfg.GraphiteRules, "graphite-rules", "", "graphite label extraction rules, e.g. servers.{host}.cpu.*;apps.{app}.*")
+	flag.IntVar(&cfg.GraphiteMaxConns, "graphite-max-conns", defaultGraphiteMaxConns, "max concurrent graphite connections")
+	flag.StringVar(&cfg.AlertRules, "alert-rules", "", "path to alerting rules file (empty disables alerting)")
+	flag.StringVar(&cfg.AlertWebhooks, "alert-webhooks", "", "comma-separated alert webhook urls")
+	flag.IntVar(&cfg.AlertInterval, "alert-interval", defaultAlertInterval, "alerting rules evaluation interval in seconds")
+	flag.StringVar(&cfg.AdminToken, "admin-token", "", "admin api bearer token (empty disables admin api)")
+	flag.IntVar(&cfg.StaleTTL, "stale-ttl", 0, "seconds without updates before a metric is flagged stale (0 disables)")
+	flag.IntVar(&cfg.PurgeTTL, "purge-ttl", 0, "seconds without updates before a metric is purged (0 disables)")
+	flag.IntVar(&cfg.JanitorInterval, "janitor-interval", defaultJanitorInterval, "stale metrics purge interval in seconds")
+	flag.StringVar(&cfg.TenantTokens, "tenant-tokens", "", "tenant tokens, e.g. team-a:token1,team-b:token2")
+	flag.IntVar(&cfg.TenantMaxSeries, "tenant-max-series", 0, "max series per tenant (0 is unlimited)")
+	flag.StringVar(&cfg.TLSCert, "tls-cert", "", "path to tls certificate (empty disables tls)")
+	flag.StringVar(&cfg.TLSKey, "tls-key", "", "path to tls private key")
+	flag.StringVar(&cfg.TLSClientCA, "tls-client-ca", "", "path to CA certificates for client certificate verification")
+	flag.BoolVar(&cfg.TLSClientAuth, "tls-client-auth", false, "require client certificates")
+	flag.IntVar(&cfg.TLSReloadInterval, "tls-reload-interval", defaultTLSReloadInterval, "tls files check interval in seconds")
+	flag.StringVar(&cfg.AuthTokens, "auth-tokens", "", "api tokens with scopes, e.g. agent:write,ops:read+admin (empty disables auth)")
+	flag.IntVar(&cfg.ReplayWindow, "replay-window", defaultReplayWindow, "allowed clock skew of signed requests in seconds (0 disables replay protection)")
+	flag.IntVar(&cfg.ReplayCacheSize, "replay-cache-size", defaultReplayCacheSize, "max remembered request nonces")
+	flag.IntVar(&cfg.BatchIDTTL, "batch-id-ttl", defaultBatchIDTTL, "seconds to remember applied batch ids")
+	flag.StringVar(&cfg.Migrate, "migrate", MigrateAuto, "database schema migration mode: auto (migrate and serve), verify (check schema version and serve), only (migrate and exit), down (revert last migration and exit)")
 	flag.StringVar(&cfgPathFl, "c", "", "path to cfg")
 
 	flag.Parse()
@@ -77,6 +204,103 @@ func NewServerConfig() (*ServerConfig, error) {
 	if err != nil {
 		return nil, e.WrapError(errPointNewServerConfig, err)
 	}
+	if _, err = cfg.Tenants(); err != nil {
+		return nil, e.WrapError(errPointNewServerConfig, err)
+	}
+	if _, err = cfg.AccessTokens(); err != nil {
+		return nil, e.WrapError(errPointNewServerConfig, err)
+	}
+	if err = cfg.validateTLS(); err != nil {
+		return nil, e.WrapError(errPointNewServerConfig, err)
+	}
+	if cfg.ReplayWindow < 0 || cfg.ReplayWindow > 0 && cfg.ReplayCacheSize <= 0 {
+		return nil, e.WrapError(errPointNewServerConfig, ErrInvalidReplay)
+	}
+	if err = cfg.validateMigrate(); err != nil {
+		return nil, e.WrapError(errPointNewServerConfig, err)
+	}
+	if err = cfg.validateIntervals(); err != nil {
+		return nil, e.WrapError(errPointNewServerConfig, err)
+	}
 
 	return cfg, nil
 }
+
+// Метод Tenants возвращает токены арендаторов
+func (c *ServerConfig) Tenants() (tenant.Tokens, error) {
+	return tenant.ParseTokens(c.TenantTokens)
+}
+
+// Метод AccessTokens возвращает токены доступа к API
+func (c *ServerConfig) AccessTokens() (auth.Tokens, error) {
+	return auth.ParseTokens(c.AuthTokens)
+}
+
+// Метод ReplayGuard возвращает защиту подписанных запросов от повтора
+// или nil, если она отключена
+func (c *ServerConfig) ReplayGuard() *replay.Guard {
+	if c.ReplayWindow == 0 {
+		return nil
+	}
+	return replay.New(time.Duration(c.ReplayWindow)*time.Second, c.ReplayCacheSize)
+}
+
+// Метод MigrateOnly сообщает, что сервер только изменяет схему базы
+// данных и завершает работу
+func (c *ServerConfig) MigrateOnly() bool {
+	return c.Migrate == MigrateOnly || c.Migrate == MigrateDown
+}
+
+// Метод validateMigrate проверяет режим миграции схемы
+func (c *ServerConfig) validateMigrate() error {
+	switch c.Migrate {
+	case MigrateAuto, MigrateVerify:
+		return nil
+	case MigrateOnly, MigrateDown:
+		if c.DatabaseDSN == "" {
+			return fmt.Errorf("%w: %s requires database dsn", ErrInvalidMigrate, c.Migrate)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidMigrate, c.Migrate)
+}
+
+// Метод validateIntervals проверяет, что периоды фоновых задач
+// положительны: нулевой период не допускает time.NewTicker
+func (c *ServerConfig) validateIntervals() error {
+	intervals := []struct {
+		name  string
+		value int
+	}{
+		{"compact-interval", c.CompactInterval},
+		{"statsd-flush", c.StatsdFlushInterval},
+		{"alert-interval", c.AlertInterval},
+		{"janitor-interval", c.JanitorInterval},
+		{"tls-reload-interval", c.TLSReloadInterval},
+		{"crypto-key-reload", c.CryptoKeyReload},
+	}
+	for _, i := range intervals {
+		if i.value <= 0 {
+			return fmt.Errorf("%w: %s must be positive, got %d", ErrInvalidInterval, i.name, i.value)
+		}
+	}
+	return nil
+}
+
+// Метод TLSEnabled сообщает, включен ли TLS
+func (c *ServerConfig) TLSEnabled() bool {
+	return c.TLSCert != ""
+}
+
+// Метод validateTLS проверяет согласованность настроек TLS
+func (c *ServerConfig) validateTLS() error {
+	switch {
+	case (c.TLSCert == "") != (c.TLSKey == ""):
+		return fmt.Errorf("%w: certificate and key must be set together", ErrInvalidTLS)
+	case c.TLSClientCA != "" && !c.TLSEnabled():
+		return fmt.Errorf("%w: client CA requires server certificate", ErrInvalidTLS)
+	case c.TLSClientAuth && c.TLSClientCA == "":
+		return fmt.Errorf("%w: client auth requires client CA", ErrInvalidTLS)
+	}
+	return nil
+}
@@ -13,3 +13,37 @@ func TestNewServerConfig(t *testing.T) {
 
 	require.NotNil(t, c)
 }
+
+func TestValidateIntervals(t *testing.T) {
+	valid := func() *ServerConfig {
+		return &ServerConfig{
+			CompactInterval:     defaultCompactInterval,
+			StatsdFlushInterval: defaultStatsdFlushInterval,
+			AlertInterval:       defaultAlertInterval,
+			JanitorInterval:     defaultJanitorInterval,
+			TLSReloadInterval:   defaultTLSReloadInterval,
+			CryptoKeyReload:     defaultCryptoKeyReload,
+		}
+	}
+	require.NoError(t, valid().validateIntervals())
+
+	tests := []struct {
+		name   string
+		modify func(*ServerConfig)
+	}{
+		{"zero compact interval", func(c *ServerConfig) { c.CompactInterval = 0 }},
+		{"negative compact interval", func(c *ServerConfig) { c.CompactInterval = -1 }},
+		{"zero statsd flush interval", func(c *ServerConfig) { c.StatsdFlushInterval = 0 }},
+		{"zero alert interval", func(c *ServerConfig) { c.AlertInterval = 0 }},
+		{"zero janitor interval", func(c *ServerConfig) { c.JanitorInterval = 0 }},
+		{"negative tls reload interval", func(c *ServerConfig) { c.TLSReloadInterval = -1 }},
+		{"zero crypto key reload", func(c *ServerConfig) { c.CryptoKeyReload = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+			require.ErrorIs(t, cfg.validateIntervals(), ErrInvalidInterval)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Пакет graphite предоставляет TCP-сервер, принимающий метрики в
+// текстовом протоколе Graphite. Каждая строка сохраняется как gauge.
+package graphite
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"go.uber.org/zap"
+)
+
+const (
+	// Время ожидания данных от клиента, после которого соединение закрывается
+	readTimeout = 5 * time.Minute
+	// Число соединений, используемое при неположительном ограничении
+	defaultMaxConns = 100
+)
+
+//go:generate moq -out metricProvider_moq_test.go . MetricProvider
+type MetricProvider interface {
+	SetMetric(context.Context, metric.Metrics) error
+}
+
+type Server struct {
+	logger   *zap.SugaredLogger
+	storage  MetricProvider
+	host     string
+	rules    []Rule
+	maxConns int
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	// Семафор активных соединений
+	sem chan struct{}
+}
+
+func New(
+	logger *zap.SugaredLogger,
+	storage MetricProvider,
+	host string,
+	rules []Rule,
+	maxConns int,
+) *Server {
+	if maxConns <= 0 {
+		maxConns = defaultMaxConns
+	}
+	return &Server{
+		logger:   logger.Named("graphite-server"),
+		storage:  storage,
+		host:     host,
+		rules:    rules,
+		maxConns: maxConns,
+		conns:    make(map[net.Conn]struct{}),
+		sem:      make(chan struct{}, maxConns),
+	}
+}
+
+func (s *Server) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	l, err := net.Listen("tcp", s.host)
+	if err != nil {
+		s.logger.Errorw("Failed to listen", "host", s.host, "error", err)
+		return
+	}
+	s.logger.Infof("Graphite server was started. Listening on: %s", s.host)
+
+	var handlers sync.WaitGroup
+	go func() {
+		<-ctx.Done()
+		l.Close()
+		// Открытые соединения закрываются, чтобы обработчики завершились
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.conns = nil
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				break
+			}
+			s.logger.Errorw("Failed to accept connection", "error", err)
+			continue
+		}
+		select {
+		case s.sem <- struct{}{}:
+		default:
+			s.logger.Errorw("Connection limit reached", "remote", conn.RemoteAddr(), "limit", s.maxConns)
+			conn.Close()
+			continue
+		}
+		if !s.track(conn) {
+			conn.Close()
+			<-s.sem
+			continue
+		}
+		handlers.Add(1)
+		go func() {
+			defer handlers.Done()
+			defer func() { <-s.sem }()
+			defer s.untrack(conn)
+			s.Serve(ctx, conn)
+		}()
+	}
+	handlers.Wait()
+	s.logger.Info("Graphite server was stopped")
+}
+
+// Метод track добавляет соединение в перечень открытых. Соединение не
+// принимается, если сервер уже останавливается.
+func (s *Server) track(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns == nil {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	conn.Close()
+}
+
+// Метод Serve читает строки из соединения до его закрытия.
+// Некорректные строки пропускаются.
+func (s *Server) Serve(ctx context.Context, conn net.Conn) {
+	scanner := bufio.NewScanner(conn)
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(readTimeout))
+		if !scanner.Scan() {
+			break
+		}
+		raw := scanner.Text()
+		if raw == "" {
+			continue
+		}
+		m, err := ParseLine(raw, s.rules, time.Now())
+		if err != nil {
+			s.logger.Errorw("Failed to parse line", "line", raw, "error", err)
+			continue
+		}
+		if err = s.storage.SetMetric(ctx, m); err != nil {
+			s.logger.Errorw("Failed to store metric", "metric", m.ID, "error", err)
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.logger.Errorw("Failed to read connection", "remote", conn.RemoteAddr(), "error", err)
+	}
+}
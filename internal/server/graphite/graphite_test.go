@@ -0,0 +1,44 @@
+package graphite
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestServer_Serve(t *testing.T) {
+	logger := zaptest.NewLogger(t).Sugar()
+	var mu sync.Mutex
+	var stored []metric.Metrics
+	mock := &MetricProviderMock{
+		SetMetricFunc: func(_ context.Context, m metric.Metrics) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stored = append(stored, m)
+			return nil
+		},
+	}
+	s := New(logger, mock, "", nil, 1)
+
+	client, server := net.Pipe()
+	done := make(chan struct{})
+	go func() {
+		s.Serve(context.Background(), server)
+		close(done)
+	}()
+
+	_, err := client.Write([]byte("a.b 1 1700000000\nbroken\n\nc.d 2\n"))
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+	<-done
+
+	require.Len(t, stored, 2)
+	require.Equal(t, "a_b", stored[0].ID)
+	require.Equal(t, "c_d", stored[1].ID)
+	require.Equal(t, 2.0, *stored[1].Value)
+}
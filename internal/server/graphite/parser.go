@@ -0,0 +1,148 @@
+package graphite
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+var (
+	ErrInvalidLine = errors.New("invalid graphite line")
+	ErrInvalidRule = errors.New("invalid graphite rule")
+)
+
+// Тип Rule - правило извлечения меток из пути. Шаблон состоит из
+// сегментов, разделенных точкой: литерал, * или {метка}. Сегменты {метка}
+// становятся метками и не входят в имя метрики.
+// Например, правило servers.{host}.cpu.* переводит путь
+// servers.web1.cpu.load в метрику servers_cpu_load{host="web1"}.
+type Rule struct {
+	segments []string
+}
+
+// Функция ParseRules разбирает правила, разделенные точкой с запятой
+func ParseRules(s string) ([]Rule, error) {
+	var rules []Rule
+	for _, pattern := range strings.Split(s, ";") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		segments := strings.Split(pattern, ".")
+		labels := make(map[string]string)
+		for _, seg := range segments {
+			if seg == "" {
+				return nil, ErrInvalidRule
+			}
+			if name, ok := labelSegment(seg); ok {
+				labels[name] = ""
+			}
+		}
+		// Хотя бы один сегмент должен остаться в имени метрики
+		if len(labels) == len(segments) {
+			return nil, ErrInvalidRule
+		}
+		if err := metric.ValidateLabels(labels); err != nil {
+			return nil, errors.Join(ErrInvalidRule, err)
+		}
+		rules = append(rules, Rule{segments: segments})
+	}
+	return rules, nil
+}
+
+// Метод apply возвращает имя и метки, если путь соответствует правилу
+func (r Rule) apply(path []string) (string, map[string]string, bool) {
+	if len(path) != len(r.segments) {
+		return "", nil, false
+	}
+	var name []string
+	var labels map[string]string
+	for i, seg := range r.segments {
+		if label, ok := labelSegment(seg); ok {
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[label] = path[i]
+			continue
+		}
+		if seg != "*" && seg != path[i] {
+			return "", nil, false
+		}
+		name = append(name, path[i])
+	}
+	return strings.Join(name, "_"), labels, true
+}
+
+// Функция labelSegment проверяет, задает ли сегмент шаблона метку
+func labelSegment(seg string) (string, bool) {
+	if len(seg) > 2 && seg[0] == '{' && seg[len(seg)-1] == '}' {
+		return seg[1 : len(seg)-1], true
+	}
+	return "", false
+}
+
+// Функция ParseLine разбирает строку вида path value [timestamp].
+// Путь может содержать теги в формате Graphite: path;tag=value;...
+// Точки в имени заменяются на подчеркивание, если путь не подошел
+// ни под одно правило. Отсутствующий или отрицательный timestamp
+// заменяется на now.
+func ParseLine(s string, rules []Rule, now time.Time) (metric.Metrics, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 2 || len(fields) > 3 {
+		return metric.Metrics{}, ErrInvalidLine
+	}
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return metric.Metrics{}, errors.Join(ErrInvalidLine, err)
+	}
+	ts := now
+	if len(fields) == 3 {
+		sec, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return metric.Metrics{}, errors.Join(ErrInvalidLine, err)
+		}
+		if sec >= 0 {
+			ts = time.Unix(0, int64(sec*float64(time.Second)))
+		}
+	}
+	ts = ts.UTC()
+
+	path, tags, _ := strings.Cut(fields[0], ";")
+	if path == "" {
+		return metric.Metrics{}, ErrInvalidLine
+	}
+	segments := strings.Split(path, ".")
+	name := strings.Join(segments, "_")
+	var labels map[string]string
+	for _, rule := range rules {
+		if n, l, ok := rule.apply(segments); ok {
+			name, labels = n, l
+			break
+		}
+	}
+	if tags != "" {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		for _, tag := range strings.Split(tags, ";") {
+			k, v, ok := strings.Cut(tag, "=")
+			if !ok || k == "" {
+				return metric.Metrics{}, ErrInvalidLine
+			}
+			labels[k] = v
+		}
+	}
+	if err := metric.ValidateLabels(labels); err != nil {
+		return metric.Metrics{}, err
+	}
+	return metric.Metrics{
+		ID:        name,
+		MType:     metric.TypeGauge.String(),
+		Value:     &value,
+		Labels:    labels,
+		Timestamp: &ts,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package graphite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("servers.{host}.cpu.*; apps.{app}.*")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	for _, s := range []string{"a..b", "{a}.{b}", "a.{x=y}"} {
+		_, err = ParseRules(s)
+		require.ErrorIs(t, err, ErrInvalidRule, s)
+	}
+}
+
+func TestParseLine(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rules, err := ParseRules("servers.{host}.cpu.*;apps.{app}.requests")
+	require.NoError(t, err)
+
+	type testCase struct {
+		name       string
+		line       string
+		wantID     string
+		wantLabels map[string]string
+		wantValue  float64
+		wantTime   time.Time
+		wantErr    error
+	}
+
+	tests := []testCase{
+		{
+			name:      "plain_path",
+			line:      "stats.api.latency 12.5 1700000000",
+			wantID:    "stats_api_latency",
+			wantValue: 12.5,
+			wantTime:  time.Unix(1700000000, 0).UTC(),
+		},
+		{
+			name:       "rule_labels",
+			line:       "servers.web1.cpu.load 0.7 1700000000",
+			wantID:     "servers_cpu_load",
+			wantLabels: map[string]string{"host": "web1"},
+			wantValue:  0.7,
+			wantTime:   time.Unix(1700000000, 0).UTC(),
+		},
+		{
+			name:      "rule_not_matched",
+			line:      "apps.shop.errors 3 -1",
+			wantID:    "apps_shop_errors",
+			wantValue: 3,
+			wantTime:  now,
+		},
+		{
+			name:       "graphite_tags",
+			line:       "apps.shop.requests;dc=eu 42",
+			wantID:     "apps_requests",
+			wantLabels: map[string]string{"app": "shop", "dc": "eu"},
+			wantValue:  42,
+			wantTime:   now,
+		},
+		{
+			name:    "no_value",
+			line:    "stats.api.latency",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "bad_value",
+			line:    "stats.api.latency abc 1700000000",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "bad_timestamp",
+			line:    "stats.api.latency 1 now",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "bad_tag",
+			line:    "stats.api.latency;dc 1",
+			wantErr: ErrInvalidLine,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := ParseLine(test.line, rules, now)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.wantID, m.ID)
+			require.Equal(t, metric.TypeGauge.String(), m.MType)
+			require.Equal(t, test.wantLabels, m.Labels)
+			require.Equal(t, test.wantValue, *m.Value)
+			require.Equal(t, test.wantTime, *m.Timestamp)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/Eqke/metric-collector/pkg/storeapi"
+	pb "github.com/eqkez0r/metric-collector-grpc-api/grpc/metric_collector"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Имя административного сервиса
+var adminServiceName = pb.MetricCollectorAdmin_ServiceDesc.ServiceName
+
+// Функция AdminTokenInterceptor проверяет токен в метаданных authorization
+// для методов административного сервиса, токен доступа области admin
+// также дает доступ к ним. Если токен администратора не задан,
+// административные методы доступны только по токену доступа.
+func AdminTokenInterceptor(
+	logger *zap.SugaredLogger,
+	token string,
+	tokens auth.Tokens,
+) grpc.UnaryServerInterceptor {
+	prefix := "/" + adminServiceName + "/"
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		if tokens.IsAdmin(apiToken(ctx)) {
+			return handler(ctx, req)
+		}
+		if token == "" {
+			logger.Errorf("admin method %s called, but admin token is not configured", info.FullMethod)
+			return nil, status.Error(codes.PermissionDenied, "admin api is disabled")
+		}
+		var got string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(storeapi.AuthorizationMetadataKey); len(values) > 0 {
+				got, _ = strings.CutPrefix(values[0], "Bearer ")
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			logger.Errorf("invalid admin token for %s", info.FullMethod)
+			return nil, status.Error(codes.Unauthenticated, "invalid admin token")
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,72 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/Eqke/metric-collector/pkg/storeapi"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Функция AuthInterceptor проверяет токен доступа унарного вызова
+func AuthInterceptor(
+	logger *zap.SugaredLogger,
+	tokens auth.Tokens,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := authorize(ctx, logger, tokens, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// Функция AuthStreamInterceptor проверяет токен доступа потокового вызова
+func AuthStreamInterceptor(
+	logger *zap.SugaredLogger,
+	tokens auth.Tokens,
+) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), logger, tokens, info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// Функция authorize проверяет, что токен в метаданных x-api-key или
+// authorization дает доступ к области, необходимой для метода
+func authorize(ctx context.Context, logger *zap.SugaredLogger, tokens auth.Tokens, method string) error {
+	err := tokens.Authorize(apiToken(ctx), auth.MethodScope(method, adminServiceName))
+	if err == nil {
+		return nil
+	}
+	logger.Errorf("auth of %s: %v", method, err)
+	if errors.Is(err, auth.ErrForbidden) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Unauthenticated, err.Error())
+}
+
+// Функция apiToken возвращает токен доступа из метаданных x-api-key или
+// authorization
+func apiToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(auth.MetadataKey); len(values) > 0 {
+		return values[0]
+	}
+	if values := md.Get(storeapi.AuthorizationMetadataKey); len(values) > 0 {
+		token, _ := strings.CutPrefix(values[0], "Bearer ")
+		return token
+	}
+	return ""
+}
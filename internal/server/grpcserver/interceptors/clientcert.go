@@ -0,0 +1,25 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/Eqke/metric-collector/internal/certs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Функция ClientCertInterceptor сохраняет в контексте идентификатор
+// агента, определенный по сертификату клиента TLS
+func ClientCertInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if p, ok := peer.FromContext(ctx); ok {
+			if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				if id := certs.AgentID(&tlsInfo.State); id != "" {
+					ctx = certs.WithAgent(ctx, id)
+				}
+			}
+		}
+		return handler(ctx, req)
+	}
+}
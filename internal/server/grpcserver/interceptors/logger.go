@@ -2,6 +2,7 @@ package interceptors
 
 import (
 	"context"
+	"github.com/Eqke/metric-collector/internal/certs"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"time"
@@ -12,9 +13,9 @@ func LoggerInterceptor(
 ) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		now := time.Now()
-		var resp interface{}
-		defer logger.Infoln("Method", info.FullMethod, "Request", req, "Response", resp, "Duration", time.Since(now))
 		resp, err := handler(ctx, req)
+		logger.Infoln("Method", info.FullMethod, "Agent", certs.AgentFromContext(ctx),
+			"Request", req, "Response", resp, "Duration", time.Since(now))
 		if err != nil {
 			return nil, err
 		}
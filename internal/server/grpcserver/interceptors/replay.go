@@ -0,0 +1,82 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/Eqke/metric-collector/internal/replay"
+	"github.com/Eqke/metric-collector/pkg/storeapi"
+	"github.com/Eqke/metric-collector/utils/hash"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Функция ReplayInterceptor проверяет подпись изменяющих вызовов ключом
+// hashKey и отклоняет вызовы вне окна guard и повторы. Подписываются имя
+// метода, сообщение запроса, время отправки и nonce из метаданных
+// x-timestamp и x-nonce, подпись передается в метаданных hashsha256.
+// Пустой ключ или guard отключают проверку.
+func ReplayInterceptor(
+	logger *zap.SugaredLogger,
+	hashKey string,
+	guard *replay.Guard,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if hashKey == "" || guard == nil ||
+			auth.MethodScope(info.FullMethod, adminServiceName) == auth.ScopeRead {
+			return handler(ctx, req)
+		}
+		nonce, err := checkReplay(ctx, hashKey, guard, info.FullMethod, req)
+		if err != nil {
+			logger.Errorf("replay check of %s: %v", info.FullMethod, err)
+			switch {
+			case errors.Is(err, replay.ErrReplayed):
+				return nil, status.Error(codes.AlreadyExists, err.Error())
+			case errors.Is(err, replay.ErrStale), errors.Is(err, replay.ErrTimestamp):
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		resp, err := handler(ctx, req)
+		if code := status.Code(err); code == codes.Internal || code == codes.Unavailable {
+			guard.Release(nonce)
+		}
+		return resp, err
+	}
+}
+
+// Ошибка неверной подписи вызова
+var errSignature = errors.New("invalid request signature")
+
+// Функция checkReplay проверяет подпись, время отправки и nonce вызова
+// и возвращает nonce
+func checkReplay(ctx context.Context, hashKey string, guard *replay.Guard, method string, req any) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(strings.ToLower(key)); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	timestamp, nonce, sign := get(hash.HeaderTimestamp), get(hash.HeaderNonce), get(hash.HeaderSignature)
+	if timestamp == "" || nonce == "" || sign == "" {
+		return "", replay.ErrMissing
+	}
+	payload, err := storeapi.SignedPayload(method, req)
+	if err != nil {
+		return "", err
+	}
+	if !hash.Equal(hash.SignRequest(payload, timestamp, nonce, hashKey), sign) {
+		return "", errSignature
+	}
+	ts, err := replay.ParseTimestamp(timestamp)
+	if err != nil {
+		return "", err
+	}
+	return nonce, guard.Check(ts, nonce)
+}
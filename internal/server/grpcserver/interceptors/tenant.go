@@ -0,0 +1,48 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/storeapi"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Функция TenantInterceptor определяет арендатора вызова по метаданным
+// x-tenant-id и токену арендатора в метаданных x-tenant-token и
+// сохраняет его в контексте
+func TenantInterceptor(
+	logger *zap.SugaredLogger,
+	tokens tenant.Tokens,
+) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var requested, token string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(storeapi.TenantMetadataKey); len(values) > 0 {
+				requested = values[0]
+			}
+			if values := md.Get(storeapi.TenantTokenMetadataKey); len(values) > 0 {
+				token = values[0]
+			}
+		}
+		id, err := tokens.Resolve(requested, token)
+		if err != nil {
+			logger.Errorf("tenant of %s: %v", info.FullMethod, err)
+			switch {
+			case errors.Is(err, tenant.ErrInvalidTenant):
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			case errors.Is(err, tenant.ErrTokenMismatch):
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			case errors.Is(err, tenant.ErrTokenRequired), errors.Is(err, tenant.ErrInvalidToken):
+				return nil, status.Error(codes.Unauthenticated, err.Error())
+			}
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		return handler(tenant.WithTenant(ctx, id), req)
+	}
+}
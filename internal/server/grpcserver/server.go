@@ -2,23 +2,44 @@ package grpcserver
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"github.com/Eqke/metric-collector/internal/audit"
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/Eqke/metric-collector/internal/replay"
 	"github.com/Eqke/metric-collector/internal/server/grpcserver/interceptors"
 	store "github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/internal/tenant"
 	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/pkg/storeapi"
 	pb "github.com/eqkez0r/metric-collector-grpc-api/grpc/metric_collector"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"net"
 	"strconv"
 	"sync"
+	"time"
 )
 
+// Период истории по умолчанию, если не указано время начала
+const defaultRangeWindow = time.Hour
+
 type StoreProvider interface {
 	SetMetric(context.Context, metric.Metrics) error
 	SetMetrics(context.Context, []metric.Metrics) error
 	GetMetric(context.Context, metric.Metrics) (metric.Metrics, error)
 	GetMetrics(context.Context) (map[string][]store.Metric, error)
+	GetMetricRange(context.Context, metric.Metrics, time.Time, time.Time, time.Duration) ([]metric.Sample, error)
+	FindMetrics(context.Context, metric.Metrics, []*metric.LabelMatcher) ([]metric.Metrics, error)
+	DeleteMetric(context.Context, metric.Metrics, []*metric.LabelMatcher) (int, error)
+	DeleteByPrefix(context.Context, string, string) (int, error)
+	ResetCounter(context.Context, metric.Metrics) error
 }
 
 type GRPCServer struct {
@@ -26,26 +47,49 @@ type GRPCServer struct {
 	store      StoreProvider
 	grpcServer *grpc.Server
 	host       string
+	audit      *audit.Logger
 
 	pb.UnimplementedMetricCollectorServer
+	pb.UnimplementedMetricCollectorAdminServer
 }
 
 func New(
 	logger *zap.SugaredLogger,
 	store StoreProvider,
 	host string,
+	adminToken string,
+	tenants tenant.Tokens,
+	tokens auth.Tokens,
+	hashKey string,
+	guard *replay.Guard,
+	tlsConfig *tls.Config,
 ) *GRPCServer {
-	grpcserver := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
+			interceptors.ClientCertInterceptor(),
 			interceptors.LoggerInterceptor(logger),
-		))
+			interceptors.AuthInterceptor(logger, tokens),
+			interceptors.ReplayInterceptor(logger, hashKey, guard),
+			interceptors.TenantInterceptor(logger, tenants),
+			interceptors.AdminTokenInterceptor(logger, adminToken, tokens),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptors.AuthStreamInterceptor(logger, tokens),
+		),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcserver := grpc.NewServer(opts...)
 	server := &GRPCServer{
 		logger:     logger.Named("grpc-server"),
 		store:      store,
 		host:       host,
 		grpcServer: grpcserver,
+		audit:      audit.New(logger),
 	}
 	pb.RegisterMetricCollectorServer(grpcserver, server)
+	pb.RegisterMetricCollectorAdminServer(grpcserver, server)
 	return server
 }
 
@@ -77,17 +121,12 @@ func (g *GRPCServer) ReceiveMetric(ctx context.Context, req *pb.ReceiveMetricReq
 	const op = "grpcServer.ReceiveMetric"
 
 	g.logger.Info("Receive metric request")
-	m := metric.Metrics{
-		ID:    req.Metric.MetricName,
-		MType: req.Metric.MetricType,
-		Value: req.Metric.Value,
-		Delta: req.Metric.Delta,
-	}
+	m := storeapi.UpdateFromProto(req.Metric)
 	g.logger.Infof("Receive metric: %v", m)
 	err := g.store.SetMetric(ctx, m)
 	if err != nil {
 		g.logger.Error(op, err)
-		return nil, err
+		return nil, writeError(err)
 	}
 	g.logger.Info("Metric stored successfully")
 	return &pb.ReceiveMetricResponse{}, nil
@@ -98,18 +137,13 @@ func (g *GRPCServer) ReceiveMetricBatch(ctx context.Context, req *pb.ReceiveMetr
 	g.logger.Info("Receive metric batch request")
 	ms := make([]metric.Metrics, 0, len(req.Metrics))
 	for _, m := range req.Metrics {
-		ms = append(ms, metric.Metrics{
-			ID:    m.MetricName,
-			MType: m.MetricType,
-			Value: m.Value,
-			Delta: m.Delta,
-		})
+		ms = append(ms, storeapi.UpdateFromProto(m))
 	}
 	g.logger.Infof("Receive metric batch: %v", ms)
-	err := g.store.SetMetrics(ctx, ms)
+	err := g.setMetrics(ctx, ms)
 	if err != nil {
 		g.logger.Error(op, err)
-		return nil, err
+		return nil, writeError(err)
 	}
 	g.logger.Info("Metric stored successfully")
 	return &pb.ReceiveMetricResponse{}, nil
@@ -119,8 +153,9 @@ func (g *GRPCServer) ReadMetric(ctx context.Context, req *pb.ReadMetricRequest)
 	const op = "grpcServer.ReadMetric"
 	g.logger.Infof("Read metric request")
 	m := metric.Metrics{
-		ID:    req.MetricName,
-		MType: req.MetricType,
+		ID:     req.MetricName,
+		MType:  req.MetricType,
+		Labels: req.Labels,
 	}
 	newM, err := g.store.GetMetric(ctx, m)
 	if err != nil {
@@ -129,12 +164,7 @@ func (g *GRPCServer) ReadMetric(ctx context.Context, req *pb.ReadMetricRequest)
 	}
 	g.logger.Info("Metric read success")
 	return &pb.ReadMetricResponse{
-		Metric: &pb.Metric{
-			MetricName: newM.ID,
-			MetricType: newM.MType,
-			Delta:      newM.Delta,
-			Value:      newM.Value,
-		},
+		Metric: storeapi.ToProto(newM),
 	}, nil
 }
 
@@ -148,9 +178,12 @@ func (g *GRPCServer) ReadAllMetric(ctx context.Context, _ *pb.ReadAllMetricReque
 	metrics := make([]*pb.Metric, 0, len(mp))
 	for k, m := range mp {
 		for _, mm := range m {
-			metric := pb.Metric{
-				MetricName: mm.Name,
-				MetricType: k,
+			series := metric.Metrics{
+				ID:        mm.Name,
+				MType:     k,
+				Labels:    mm.Labels,
+				UpdatedAt: mm.UpdatedAt,
+				Stale:     mm.Stale,
 			}
 			switch k {
 			case "gauge":
@@ -159,18 +192,171 @@ func (g *GRPCServer) ReadAllMetric(ctx context.Context, _ *pb.ReadAllMetricReque
 					g.logger.Error(op, err)
 					continue
 				}
-				metric.Value = &value
+				series.Value = &value
 			case "counter":
 				counter, err := strconv.ParseInt(mm.Value, 10, 64)
 				if err != nil {
 					g.logger.Error(op, err)
 					continue
 				}
-				metric.Delta = &counter
+				series.Delta = &counter
+			case "histogram":
+				h, err := metric.ParseHistogram(mm.Value)
+				if err != nil {
+					g.logger.Error(op, err)
+					continue
+				}
+				series.Histogram = h
+			default:
+				continue
 			}
-			metrics = append(metrics, &metric)
+			metrics = append(metrics, storeapi.ToProto(series))
 		}
 	}
 	g.logger.Infof("Read all metrics: %v", metrics)
 	return &pb.ReadAllMetricResponse{Metrics: metrics}, nil
 }
+
+func (g *GRPCServer) ReadMetricRange(ctx context.Context, req *pb.ReadMetricRangeRequest) (*pb.ReadMetricRangeResponse, error) {
+	const op = "grpcServer.ReadMetricRange"
+	g.logger.Infof("Read metric range request")
+	m := metric.Metrics{
+		ID:     req.MetricName,
+		MType:  req.MetricType,
+		Labels: req.Labels,
+	}
+	// Как и в HTTP API, по умолчанию читается последний час
+	to := time.Now()
+	if req.To != nil {
+		to = req.To.AsTime()
+	}
+	from := to.Add(-defaultRangeWindow)
+	if req.From != nil {
+		from = req.From.AsTime()
+	}
+	samples, err := g.store.GetMetricRange(ctx, m, from, to, req.Step.AsDuration())
+	if err != nil {
+		g.logger.Error(op, err)
+		if errors.Is(err, store.ErrInvalidRange) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, err
+	}
+	g.logger.Infof("Metric range read success, samples: %d", len(samples))
+	res := &pb.ReadMetricRangeResponse{Samples: make([]*pb.Sample, 0, len(samples))}
+	for _, s := range samples {
+		res.Samples = append(res.Samples, storeapi.SampleToProto(s))
+	}
+	return res, nil
+}
+
+func (g *GRPCServer) FindMetrics(ctx context.Context, req *pb.FindMetricsRequest) (*pb.FindMetricsResponse, error) {
+	const op = "grpcServer.FindMetrics"
+	g.logger.Infof("Find metrics request")
+	matchers, err := metric.ParseLabelMatchers(req.Matchers)
+	if err != nil {
+		g.logger.Error(op, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ms, err := g.store.FindMetrics(ctx, metric.Metrics{ID: req.MetricName, MType: req.MetricType}, matchers)
+	if err != nil {
+		g.logger.Error(op, err)
+		return nil, err
+	}
+	g.logger.Infof("Find metrics success, series: %d", len(ms))
+	res := &pb.FindMetricsResponse{Metrics: make([]*pb.Metric, 0, len(ms))}
+	for _, m := range ms {
+		res.Metrics = append(res.Metrics, storeapi.ToProto(m))
+	}
+	return res, nil
+}
+
+func (g *GRPCServer) DeleteMetric(ctx context.Context, req *pb.DeleteMetricRequest) (*pb.DeleteResponse, error) {
+	const op = "grpcServer.DeleteMetric"
+	g.logger.Infof("Delete metric request")
+	matchers, err := metric.ParseLabelMatchers(req.Matchers)
+	if err != nil {
+		g.logger.Error(op, err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	deleted, err := g.store.DeleteMetric(ctx, metric.Metrics{ID: req.MetricName, MType: req.MetricType}, matchers)
+	g.audit.Log("DeleteMetric", peerAddr(ctx), err,
+		"tenant", tenant.FromContext(ctx), "type", req.MetricType, "name", req.MetricName,
+		"match", req.Matchers, "deleted", deleted)
+	if err != nil {
+		g.logger.Error(op, err)
+		return nil, err
+	}
+	return &pb.DeleteResponse{Deleted: int64(deleted)}, nil
+}
+
+func (g *GRPCServer) DeleteByPrefix(ctx context.Context, req *pb.DeleteByPrefixRequest) (*pb.DeleteResponse, error) {
+	const op = "grpcServer.DeleteByPrefix"
+	g.logger.Infof("Delete by prefix request")
+	if req.Prefix == "" {
+		g.logger.Error(op, store.ErrPrefixIsEmpty)
+		return nil, status.Error(codes.InvalidArgument, store.ErrPrefixIsEmpty.Error())
+	}
+	deleted, err := g.store.DeleteByPrefix(ctx, req.MetricType, req.Prefix)
+	g.audit.Log("DeleteByPrefix", peerAddr(ctx), err,
+		"tenant", tenant.FromContext(ctx), "type", req.MetricType, "prefix", req.Prefix, "deleted", deleted)
+	if err != nil {
+		g.logger.Error(op, err)
+		return nil, err
+	}
+	return &pb.DeleteResponse{Deleted: int64(deleted)}, nil
+}
+
+func (g *GRPCServer) ResetCounter(ctx context.Context, req *pb.ResetCounterRequest) (*pb.ResetCounterResponse, error) {
+	const op = "grpcServer.ResetCounter"
+	g.logger.Infof("Reset counter request")
+	m := metric.Metrics{
+		ID:     req.MetricName,
+		MType:  metric.TypeCounter.String(),
+		Labels: req.Labels,
+	}
+	err := g.store.ResetCounter(ctx, m)
+	g.audit.Log("ResetCounter", peerAddr(ctx), err,
+		"tenant", tenant.FromContext(ctx), "name", req.MetricName, "labels", req.Labels)
+	if err != nil {
+		g.logger.Error(op, err)
+		return nil, err
+	}
+	return &pb.ResetCounterResponse{}, nil
+}
+
+// Функция writeError переводит ошибку записи метрик в статус gRPC
+func writeError(err error) error {
+	if errors.Is(err, store.ErrSeriesLimitExceeded) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return err
+}
+
+// Метод setMetrics сохраняет пакет метрик. Пакет с идентификатором из
+// метаданных idempotency-key, который уже был применен, повторно не
+// применяется, а вызов считается успешным.
+func (g *GRPCServer) setMetrics(ctx context.Context, ms []metric.Metrics) error {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(storeapi.BatchIDMetadataKey); len(values) > 0 {
+			if err := store.ValidateBatchID(values[0]); err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			ctx = store.WithBatchID(ctx, values[0])
+		}
+	}
+	err := g.store.SetMetrics(ctx, ms)
+	if errors.Is(err, store.ErrBatchApplied) {
+		g.logger.Infof("metrics batch %s was already applied", store.BatchIDFromContext(ctx))
+		return nil
+	}
+	return err
+}
+
+// Функция peerAddr возвращает адрес клиента запроса
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
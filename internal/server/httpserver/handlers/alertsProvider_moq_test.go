@@ -0,0 +1,69 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"sync"
+
+	"github.com/Eqke/metric-collector/internal/alerting"
+)
+
+// Ensure, that AlertsProviderMock does implement AlertsProvider.
+// If this is not the case, regenerate this file with moq.
+var _ AlertsProvider = &AlertsProviderMock{}
+
+// AlertsProviderMock is a mock implementation of AlertsProvider.
+//
+//	func TestSomethingThatUsesAlertsProvider(t *testing.T) {
+//
+//		// make and configure a mocked AlertsProvider
+//		mockedAlertsProvider := &AlertsProviderMock{
+//			AlertsFunc: func() []alerting.Alert {
+//				panic("mock out the Alerts method")
+//			},
+//		}
+//
+//		// use mockedAlertsProvider in code that requires AlertsProvider
+//		// and then make assertions.
+//
+//	}
+type AlertsProviderMock struct {
+	// AlertsFunc mocks the Alerts method.
+	AlertsFunc func() []alerting.Alert
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Alerts holds details about calls to the Alerts method.
+		Alerts []struct {
+		}
+	}
+	lockAlerts sync.RWMutex
+}
+
+// Alerts calls AlertsFunc.
+func (mock *AlertsProviderMock) Alerts() []alerting.Alert {
+	if mock.AlertsFunc == nil {
+		panic("AlertsProviderMock.AlertsFunc: method is nil but AlertsProvider.Alerts was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockAlerts.Lock()
+	mock.calls.Alerts = append(mock.calls.Alerts, callInfo)
+	mock.lockAlerts.Unlock()
+	return mock.AlertsFunc()
+}
+
+// AlertsCalls gets all the calls that were made to Alerts.
+// Check the length with:
+//
+//	len(mockedAlertsProvider.AlertsCalls())
+func (mock *AlertsProviderMock) AlertsCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockAlerts.RLock()
+	calls = mock.calls.Alerts
+	mock.lockAlerts.RUnlock()
+	return calls
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	store "github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetMetricPage = "error in GET /view/:type/:name"
+
+	// Период автообновления страниц по умолчанию в секундах
+	defaultRefresh = 10
+
+	// Шаг истории на странице метрики
+	pageHistoryStep = time.Minute
+
+	// Размеры графика истории на странице метрики
+	sparklineWidth  = 600
+	sparklineHeight = 80
+)
+
+// Шаблоны страниц встроены в бинарный файл, страницы не используют
+// внешних ресурсов
+//
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+// Каждая страница разбирается вместе с общим оформлением layout.html
+var pageTemplates = map[string]*template.Template{
+	"root.html":   parsePage("root.html"),
+	"metric.html": parsePage("metric.html"),
+}
+
+func parsePage(name string) *template.Template {
+	return template.Must(template.New(name).ParseFS(templatesFS, "templates/layout.html", "templates/"+name))
+}
+
+//go:generate moq -out metricPageProvider_moq_test.go . MetricPageProvider
+type MetricPageProvider interface {
+	FindMetrics(context.Context, metric.Metrics, []*metric.LabelMatcher) ([]metric.Metrics, error)
+	GetMetricRange(context.Context, metric.Metrics, time.Time, time.Time, time.Duration) ([]metric.Sample, error)
+}
+
+// Тип pageRow - строка таблицы метрик
+type pageRow struct {
+	Name   string
+	Labels string
+	Value  string
+	Link   string
+	Stale  bool
+}
+
+// Тип pageSection - таблица метрик одного типа
+type pageSection struct {
+	Type string
+	Rows []pageRow
+}
+
+// Тип rootPage - данные главной страницы
+type rootPage struct {
+	Refresh  int
+	Sections []pageSection
+}
+
+// Тип pageSample - значение истории серии
+type pageSample struct {
+	Timestamp string
+	Value     string
+}
+
+// Тип pageBucket - корзина гистограммы
+type pageBucket struct {
+	Bound string
+	Count uint64
+}
+
+// Тип pageQuantile - квантиль гистограммы
+type pageQuantile struct {
+	Quantile string
+	Value    string
+}
+
+// Тип pageSeries - серия метрики на странице метрики
+type pageSeries struct {
+	Labels    string
+	Value     string
+	Sparkline string
+	Stale     bool
+	History   []pageSample
+	Buckets   []pageBucket
+	Quantiles []pageQuantile
+}
+
+// Тип metricPage - данные страницы метрики
+type metricPage struct {
+	Refresh int
+	Type    string
+	Name    string
+	Window  string
+	Series  []pageSeries
+}
+
+// Функция renderRootPage выводит таблицы метрик по типам
+func renderRootPage(c *gin.Context, data map[string][]store.Metric) error {
+	page := rootPage{Refresh: parseRefresh(c)}
+	for _, mType := range []string{
+		metric.TypeGauge.String(),
+		metric.TypeCounter.String(),
+		metric.TypeHistogram.String(),
+	} {
+		section := pageSection{Type: mType, Rows: make([]pageRow, 0, len(data[mType]))}
+		for _, m := range data[mType] {
+			section.Rows = append(section.Rows, pageRow{
+				Name:   m.Name,
+				Labels: formatLabels(m.Labels),
+				Value:  m.Value,
+				Link:   metricPageLink(mType, m.Name),
+				Stale:  m.Stale,
+			})
+		}
+		sort.Slice(section.Rows, func(i, j int) bool {
+			if section.Rows[i].Name != section.Rows[j].Name {
+				return section.Rows[i].Name < section.Rows[j].Name
+			}
+			return section.Rows[i].Labels < section.Rows[j].Labels
+		})
+		page.Sections = append(page.Sections, section)
+	}
+	return renderPage(c, "root.html", page)
+}
+
+// Функция GetMetricPageHandler выводит страницу метрики: серии с
+// текущими значениями и историей за последний час. Для гистограмм
+// выводятся корзины и квантили.
+func GetMetricPageHandler(
+	logger *zap.SugaredLogger,
+	p MetricPageProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/view/:type/:name get metric page")
+		m := metric.Metrics{
+			ID:    c.Param("name"),
+			MType: c.Param("type"),
+		}
+		var series []metric.Metrics
+		var err error
+		err = retry.Retry(logger, 3, func() error {
+			series, err = p.FindMetrics(c, m, nil)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetMetricPage, err)
+			if errors.Is(err, store.ErrIsUnknownType) {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		if len(series) == 0 {
+			logger.Errorf("%s: %v", errPointGetMetricPage, store.ErrIsMetricDoesntExist)
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		page := metricPage{
+			Refresh: parseRefresh(c),
+			Type:    m.MType,
+			Name:    m.ID,
+			Window:  defaultRangeWindow.String(),
+		}
+		to := time.Now()
+		for _, s := range series {
+			ps := pageSeries{Labels: formatLabels(s.Labels), Stale: s.Stale}
+			switch {
+			case s.Histogram != nil:
+				ps.Value = fmt.Sprintf("count %d, sum %g", s.Histogram.Count, s.Histogram.Sum)
+				ps.Buckets, ps.Quantiles = histogramRows(s.Histogram)
+			default:
+				ps.Value = formatValue(s)
+				var samples []metric.Sample
+				err = retry.Retry(logger, 3, func() error {
+					samples, err = p.GetMetricRange(c, s, to.Add(-defaultRangeWindow), to, pageHistoryStep)
+					return err
+				})
+				if err != nil {
+					// Страница выводится и без истории
+					logger.Errorf("%s: %v", errPointGetMetricPage, err)
+				}
+				ps.History, ps.Sparkline = historyRows(samples)
+			}
+			page.Series = append(page.Series, ps)
+		}
+		if err := renderPage(c, "metric.html", page); err != nil {
+			logger.Errorf("%s: %v", errPointGetMetricPage, err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		logger.Infof("metric page get success with type: %s, name: %s, series: %d",
+			m.MType, m.ID, len(series))
+	}
+}
+
+// Функция renderPage выполняет шаблон в буфер, чтобы при ошибке
+// не отправлять клиенту часть страницы
+func renderPage(c *gin.Context, name string, data any) error {
+	var b strings.Builder
+	if err := pageTemplates[name].Execute(&b, data); err != nil {
+		return err
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(b.String()))
+	return nil
+}
+
+// Функция parseRefresh возвращает период автообновления из параметра
+// refresh в секундах, 0 отключает автообновление
+func parseRefresh(c *gin.Context) int {
+	refresh, err := strconv.Atoi(c.Query("refresh"))
+	if err != nil || refresh < 0 {
+		return defaultRefresh
+	}
+	return refresh
+}
+
+// Функция metricPageLink возвращает адрес страницы метрики
+func metricPageLink(mType, name string) string {
+	return "/view/" + url.PathEscape(mType) + "/" + url.PathEscape(name) + "/"
+}
+
+// Функция formatLabels возвращает метки в виде name="value" через запятую
+func formatLabels(labels map[string]string) string {
+	key := metric.SeriesKey("", labels)
+	return strings.TrimSuffix(strings.TrimPrefix(key, "{"), "}")
+}
+
+// Функция formatValue возвращает значение gauge или counter строкой
+func formatValue(m metric.Metrics) string {
+	switch {
+	case m.Value != nil:
+		return strconv.FormatFloat(*m.Value, 'f', -1, 64)
+	case m.Delta != nil:
+		return strconv.FormatInt(*m.Delta, 10)
+	}
+	return ""
+}
+
+// Функция historyRows возвращает строки истории, начиная с последнего
+// значения, и точки графика для элемента polyline
+func historyRows(samples []metric.Sample) ([]pageSample, string) {
+	rows := make([]pageSample, 0, len(samples))
+	values := make([]float64, 0, len(samples))
+	for i := len(samples) - 1; i >= 0; i-- {
+		s := samples[i]
+		m := metric.Metrics{Value: s.Value, Delta: s.Delta}
+		rows = append(rows, pageSample{
+			Timestamp: s.Timestamp.UTC().Format(time.RFC3339),
+			Value:     formatValue(m),
+		})
+	}
+	for _, s := range samples {
+		switch {
+		case s.Value != nil:
+			values = append(values, *s.Value)
+		case s.Delta != nil:
+			values = append(values, float64(*s.Delta))
+		}
+	}
+	return rows, sparkline(values)
+}
+
+// Функция sparkline масштабирует значения в координаты графика
+// sparklineWidth x sparklineHeight
+func sparkline(values []float64) string {
+	if len(values) < 2 {
+		return ""
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	points := make([]string, 0, len(values))
+	for i, v := range values {
+		x := float64(i) * sparklineWidth / float64(len(values)-1)
+		y := float64(sparklineHeight) / 2
+		if hi > lo {
+			y = sparklineHeight - (v-lo)/(hi-lo)*sparklineHeight
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	return strings.Join(points, " ")
+}
+
+// Функция histogramRows возвращает корзины и квантили гистограммы
+func histogramRows(h *metric.Histogram) ([]pageBucket, []pageQuantile) {
+	buckets := make([]pageBucket, 0, len(h.Counts))
+	for i, count := range h.Counts {
+		bound := "+Inf"
+		if i < len(h.Bounds) {
+			bound = strconv.FormatFloat(h.Bounds[i], 'f', -1, 64)
+		}
+		buckets = append(buckets, pageBucket{Bound: bound, Count: count})
+	}
+	quantiles := make([]pageQuantile, 0, len(defaultQuantiles))
+	for _, q := range defaultQuantiles {
+		quantiles = append(quantiles, pageQuantile{
+			Quantile: strconv.FormatFloat(q, 'f', -1, 64),
+			Value:    strconv.FormatFloat(h.Quantile(q), 'g', 6, 64),
+		})
+	}
+	return buckets, quantiles
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetMetricPage(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	web1, first, second := float64(12.5), float64(10), float64(20)
+	ts := time.Unix(1700000000, 0).UTC()
+	h := metric.NewHistogram([]float64{0.1, 1})
+	h.Observe(0.5)
+	provider := &MetricPageProviderMock{
+		FindMetricsFunc: func(_ context.Context, m metric.Metrics, _ []*metric.LabelMatcher) ([]metric.Metrics, error) {
+			switch {
+			case m.MType == "histogram" && m.ID == "latency":
+				return []metric.Metrics{{ID: m.ID, MType: m.MType, Histogram: h}}, nil
+			case m.MType == "histogram" || m.MType == "gauge" && m.ID != "Alloc":
+				return nil, nil
+			case m.MType != "gauge":
+				return nil, storage.ErrIsUnknownType
+			}
+			return []metric.Metrics{{ID: m.ID, MType: m.MType, Value: &web1, Labels: map[string]string{"host": "web1"}}}, nil
+		},
+		GetMetricRangeFunc: func(_ context.Context, _ metric.Metrics, _, _ time.Time, _ time.Duration) ([]metric.Sample, error) {
+			return []metric.Sample{{Timestamp: ts, Value: &first}, {Timestamp: ts.Add(time.Minute), Value: &second}}, nil
+		},
+	}
+	engine.GET("/view/:type/:name/", GetMetricPageHandler(l, provider))
+
+	tests := []struct {
+		name     string
+		url      string
+		code     int
+		contains []string
+	}{
+		{
+			name:     "gauge",
+			url:      "/view/gauge/Alloc/",
+			code:     http.StatusOK,
+			contains: []string{"12.5", "<polyline", "2023-11-14T22:14:20Z", `http-equiv="refresh" content="10"`},
+		},
+		{
+			name:     "histogram",
+			url:      "/view/histogram/latency/?refresh=30",
+			code:     http.StatusOK,
+			contains: []string{"&#43;Inf", "count 1, sum 0.5", "p0.99", `content="30"`},
+		},
+		{name: "not_found", url: "/view/gauge/Unknown/", code: http.StatusNotFound},
+		{name: "unknown_type", url: "/view/summary/Alloc/", code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			for _, s := range tt.contains {
+				require.Contains(t, w.Body.String(), s)
+			}
+		})
+	}
+}
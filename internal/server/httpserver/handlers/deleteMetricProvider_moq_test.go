@@ -0,0 +1,89 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Ensure, that DeleteMetricProviderMock does implement DeleteMetricProvider.
+// If this is not the case, regenerate this file with moq.
+var _ DeleteMetricProvider = &DeleteMetricProviderMock{}
+
+// DeleteMetricProviderMock is a mock implementation of DeleteMetricProvider.
+//
+//	func TestSomethingThatUsesDeleteMetricProvider(t *testing.T) {
+//
+//		// make and configure a mocked DeleteMetricProvider
+//		mockedDeleteMetricProvider := &DeleteMetricProviderMock{
+//			DeleteMetricFunc: func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) (int, error) {
+//				panic("mock out the DeleteMetric method")
+//			},
+//		}
+//
+//		// use mockedDeleteMetricProvider in code that requires DeleteMetricProvider
+//		// and then make assertions.
+//
+//	}
+type DeleteMetricProviderMock struct {
+	// DeleteMetricFunc mocks the DeleteMetric method.
+	DeleteMetricFunc func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) (int, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// DeleteMetric holds details about calls to the DeleteMetric method.
+		DeleteMetric []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+			// LabelMatchers is the labelMatchers argument value.
+			LabelMatchers []*metric.LabelMatcher
+		}
+	}
+	lockDeleteMetric sync.RWMutex
+}
+
+// DeleteMetric calls DeleteMetricFunc.
+func (mock *DeleteMetricProviderMock) DeleteMetric(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) (int, error) {
+	if mock.DeleteMetricFunc == nil {
+		panic("DeleteMetricProviderMock.DeleteMetricFunc: method is nil but DeleteMetricProvider.DeleteMetric was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+		LabelMatchers:   labelMatchers,
+	}
+	mock.lockDeleteMetric.Lock()
+	mock.calls.DeleteMetric = append(mock.calls.DeleteMetric, callInfo)
+	mock.lockDeleteMetric.Unlock()
+	return mock.DeleteMetricFunc(contextMoqParam, metrics, labelMatchers)
+}
+
+// DeleteMetricCalls gets all the calls that were made to DeleteMetric.
+// Check the length with:
+//
+//	len(mockedDeleteMetricProvider.DeleteMetricCalls())
+func (mock *DeleteMetricProviderMock) DeleteMetricCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+	LabelMatchers   []*metric.LabelMatcher
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}
+	mock.lockDeleteMetric.RLock()
+	calls = mock.calls.DeleteMetric
+	mock.lockDeleteMetric.RUnlock()
+	return calls
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Eqke/metric-collector/internal/audit"
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointDeleteMetric = "error in DELETE /value/:type/:name"
+)
+
+//go:generate moq -out deleteMetricProvider_moq_test.go . DeleteMetricProvider
+type DeleteMetricProvider interface {
+	DeleteMetric(context.Context, metric.Metrics, []*metric.LabelMatcher) (int, error)
+}
+
+// Функция DeleteMetricHandler удаляет серии метрики вместе с историей.
+// Без параметра match удаляются все серии метрики, иначе - серии,
+// метки которых удовлетворяют условиям. Каждый вызов записывается
+// в журнал аудита. Удаление не повторяется при ошибке.
+func DeleteMetricHandler(
+	logger *zap.SugaredLogger,
+	p DeleteMetricProvider,
+	a *audit.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/value/:type/:name delete metric")
+		matchers, err := metric.ParseLabelMatchers(c.QueryArray("match"))
+		if err != nil {
+			logger.Errorf("%s: %v", errPointDeleteMetric, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		m := metric.Metrics{
+			ID:    c.Param("name"),
+			MType: c.Param("type"),
+		}
+		switch m.MType {
+		case metric.TypeGauge.String(), metric.TypeCounter.String(), metric.TypeHistogram.String():
+		default:
+			logger.Errorf("%s: unknown type %s", errPointDeleteMetric, m.MType)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		deleted, err := p.DeleteMetric(c, m, matchers)
+		a.Log("DeleteMetric", c.ClientIP(), err,
+			"tenant", tenant.FromContext(c), "type", m.MType, "name", m.ID, "match", c.QueryArray("match"), "deleted", deleted)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointDeleteMetric, err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		if deleted == 0 {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/audit"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDeleteMetric(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	provider := &DeleteMetricProviderMock{
+		DeleteMetricFunc: func(_ context.Context, m metric.Metrics, matchers []*metric.LabelMatcher) (int, error) {
+			switch m.ID {
+			case "CPUutilization7":
+				if len(matchers) > 0 {
+					return 1, nil
+				}
+				return 3, nil
+			case "Broken":
+				return 0, errors.New("database is unavailable")
+			}
+			return 0, nil
+		},
+	}
+	engine.DELETE("/value/:type/:name/", DeleteMetricHandler(l, provider, audit.New(zap.New(core).Sugar())))
+
+	tests := []struct {
+		name    string
+		url     string
+		code    int
+		deleted int
+	}{
+		{name: "all_series", url: "/value/gauge/CPUutilization7/", code: http.StatusOK, deleted: 3},
+		{name: "matched_series", url: "/value/gauge/CPUutilization7/?match=host%3Dweb1", code: http.StatusOK, deleted: 1},
+		{name: "not_found", url: "/value/gauge/Unknown/", code: http.StatusNotFound},
+		{name: "storage_error", url: "/value/gauge/Broken/", code: http.StatusInternalServerError},
+		{name: "unknown_type", url: "/value/summary/CPUutilization7/", code: http.StatusBadRequest},
+		{name: "invalid_matcher", url: "/value/gauge/CPUutilization7/?match=host", code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", tt.url, nil)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			if tt.code != http.StatusOK {
+				return
+			}
+			var res struct {
+				Deleted int `json:"deleted"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			require.Equal(t, tt.deleted, res.Deleted)
+		})
+	}
+	// Аудит записывается для каждого вызова хранилища
+	require.Equal(t, 4, logs.FilterMessage("audit").Len())
+}
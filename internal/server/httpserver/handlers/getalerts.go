@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Eqke/metric-collector/internal/alerting"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetAlerts = "error in GET /alerts"
+)
+
+//go:generate moq -out alertsProvider_moq_test.go . AlertsProvider
+type AlertsProvider interface {
+	Alerts() []alerting.Alert
+}
+
+// Функция GetAlertsHandler возвращает активные оповещения.
+// Параметр state (pending или firing) ограничивает состояние.
+func GetAlertsHandler(
+	logger *zap.SugaredLogger,
+	p AlertsProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/alerts get active alerts")
+		state := alerting.State(c.Query("state"))
+		if state != "" && state != alerting.StatePending && state != alerting.StateFiring {
+			logger.Errorf("%s: unknown state %s", errPointGetAlerts, state)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		alerts := make([]alerting.Alert, 0)
+		for _, a := range p.Alerts() {
+			if state == "" || a.State == state {
+				alerts = append(alerts, a)
+			}
+		}
+		c.JSON(http.StatusOK, alerts)
+	}
+}
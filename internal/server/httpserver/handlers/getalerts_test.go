@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/alerting"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetAlerts(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	provider := &AlertsProviderMock{
+		AlertsFunc: func() []alerting.Alert {
+			return []alerting.Alert{
+				{Rule: "LowFreeMemory", Metric: "FreeMemory", State: alerting.StateFiring},
+				{Rule: "LowFreeMemory", Metric: "FreeMemory", State: alerting.StatePending},
+			}
+		},
+	}
+	engine.GET("/alerts", GetAlertsHandler(l, provider))
+
+	tests := []struct {
+		name  string
+		url   string
+		code  int
+		count int
+	}{
+		{name: "all", url: "/alerts", code: http.StatusOK, count: 2},
+		{name: "firing", url: "/alerts?state=firing", code: http.StatusOK, count: 1},
+		{name: "unknown_state", url: "/alerts?state=resolved", code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("GET", tt.url, nil)
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			if tt.code != http.StatusOK {
+				return
+			}
+			var alerts []alerting.Alert
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &alerts))
+			require.Len(t, alerts, tt.count)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetCounterRate = "error in GET /rate/:name"
+
+	// Окно вычисления скорости по умолчанию
+	defaultRateWindow = 5 * time.Minute
+
+	// Максимальное число точек в ответе
+	maxRatePoints = 11000
+)
+
+var errTooManyPoints = errors.New("too many points")
+
+// Функция GetCounterRateHandler возвращает прирост счетчика за окно
+// window и скорость прироста в секунду. Без параметра step возвращается
+// одно значение для момента to, иначе - значения для моментов from,
+// from+step, ..., to. Уменьшение счетчика учитывается как отрицательный
+// прирост.
+func GetCounterRateHandler(
+	logger *zap.SugaredLogger,
+	p MetricRangeProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/rate/:name get counter rate")
+		labels, err := parseLabels(c)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetCounterRate, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		m := metric.Metrics{
+			ID:     c.Param("name"),
+			MType:  metric.TypeCounter.String(),
+			Labels: labels,
+		}
+		window, step, from, to, err := parseRateParams(c)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetCounterRate, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		logger.Infof("counter rate was requested with name: %s, from: %v, to: %v, step: %v, window: %v",
+			m.ID, from, to, step, window)
+
+		// Значения за дополнительное окно служат базой для первого окна
+		var samples []metric.Sample
+		err = retry.Retry(logger, 3, func() error {
+			samples, err = p.GetMetricRange(c, m, from.Add(-2*window), to, 0)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetCounterRate, err)
+			switch {
+			case errors.Is(err, storage.ErrIsMetricDoesntExist):
+				c.Status(http.StatusNotFound)
+			case errors.Is(err, storage.ErrInvalidRange):
+				c.Status(http.StatusBadRequest)
+			default:
+				c.Status(http.StatusInternalServerError)
+			}
+			return
+		}
+		c.JSON(http.StatusOK, storage.CounterRates(samples, from, to, step, window))
+	}
+}
+
+// Функция parseRateParams разбирает параметры window, step, from и to.
+// Без step параметр from совпадает с to.
+func parseRateParams(c *gin.Context) (window, step time.Duration, from, to time.Time, err error) {
+	window, err = parseStep(c.Query("window"))
+	if err != nil {
+		return
+	}
+	if window == 0 {
+		window = defaultRateWindow
+	}
+	if window < 0 {
+		err = storage.ErrInvalidRange
+		return
+	}
+	if step, err = parseStep(c.Query("step")); err != nil {
+		return
+	}
+	if to, err = parseTime(c.Query("to"), time.Now()); err != nil {
+		return
+	}
+	if step <= 0 {
+		return window, 0, to, to, nil
+	}
+	if from, err = parseTime(c.Query("from"), to.Add(-defaultRangeWindow)); err != nil {
+		return
+	}
+	switch {
+	case from.After(to):
+		err = storage.ErrInvalidRange
+	case to.Sub(from)/step >= maxRatePoints:
+		err = errTooManyPoints
+	}
+	return
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetCounterRate(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	base := time.Unix(1700000000, 0).UTC()
+	// Счетчик уменьшается после значения 160
+	values := []int64{100, 130, 160, 20, 50}
+	samples := make([]metric.Sample, 0, len(values))
+	for i := range values {
+		samples = append(samples, metric.Sample{Timestamp: base.Add(time.Duration(i) * time.Minute), Delta: &values[i]})
+	}
+	provider := &MetricRangeProviderMock{
+		GetMetricRangeFunc: func(_ context.Context, m metric.Metrics, from, to time.Time, _ time.Duration) ([]metric.Sample, error) {
+			if m.ID != "PollCount" {
+				return nil, storage.ErrIsMetricDoesntExist
+			}
+			res := make([]metric.Sample, 0)
+			for _, s := range samples {
+				if !s.Timestamp.Before(from) && !s.Timestamp.After(to) {
+					res = append(res, s)
+				}
+			}
+			return res, nil
+		},
+	}
+	engine.GET("/rate/:name/", GetCounterRateHandler(l, provider))
+
+	tests := []struct {
+		name string
+		url  string
+		code int
+		want []storage.CounterRate
+	}{
+		{
+			name: "window_with_decrease",
+			url:  "/rate/PollCount/?window=3m&to=1700000240",
+			code: http.StatusOK,
+			want: []storage.CounterRate{
+				{Timestamp: base.Add(4 * time.Minute), Increase: -80, Rate: -80.0 / 180},
+			},
+		},
+		{
+			name: "range",
+			url:  "/rate/PollCount/?window=60&from=1700000060&to=1700000240&step=2m",
+			code: http.StatusOK,
+			want: []storage.CounterRate{
+				{Timestamp: base.Add(time.Minute), Increase: 30, Rate: 0.5},
+				{Timestamp: base.Add(3 * time.Minute), Increase: -140, Rate: -140.0 / 60},
+			},
+		},
+		{name: "not_found", url: "/rate/Unknown/", code: http.StatusNotFound},
+		{name: "invalid_window", url: "/rate/PollCount/?window=soon", code: http.StatusBadRequest},
+		{name: "invalid_range", url: "/rate/PollCount/?from=1700000240&to=1700000060&step=1m", code: http.StatusBadRequest},
+		{name: "too_many_points", url: "/rate/PollCount/?from=0&to=1700000060&step=1s", code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			rr := httptest.NewRecorder()
+			engine.ServeHTTP(rr, req)
+
+			require.Equal(t, tt.code, rr.Code)
+			if tt.code != http.StatusOK {
+				return
+			}
+			var got []storage.CounterRate
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Len(t, got, len(tt.want))
+			for i := range tt.want {
+				require.True(t, tt.want[i].Timestamp.Equal(got[i].Timestamp))
+				require.Equal(t, tt.want[i].Increase, got[i].Increase)
+				require.InDelta(t, tt.want[i].Rate, got[i].Rate, 1e-9)
+			}
+		})
+	}
+}
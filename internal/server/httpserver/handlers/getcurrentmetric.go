@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/Eqke/metric-collector/pkg/metric"
 	"github.com/Eqke/metric-collector/utils/retry"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -24,10 +25,15 @@ func GETMetricHandler(
 	return func(c *gin.Context) {
 		logger.Info("/value/:type/:name get metric")
 		metricType := c.Param("type")
-		metricName := c.Param("name")
+		labels, err := parseLabels(c)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetMetric, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		metricName := metric.SeriesKey(c.Param("name"), labels)
 		logger.Infof("metric was requested with type: %s, name: %s", metricType, metricName)
 		var value string
-		var err error
 		err = retry.Retry(logger, 3, func() error {
 			value, err = p.GetValue(c, metricType, metricName)
 			return err
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetMetricRange = "error in GET /history/:type/:name"
+
+	// Период истории по умолчанию, если не указан параметр from
+	defaultRangeWindow = time.Hour
+)
+
+//go:generate moq -out metricRangeProvider_moq_test.go . MetricRangeProvider
+type MetricRangeProvider interface {
+	GetMetricRange(context.Context, metric.Metrics, time.Time, time.Time, time.Duration) ([]metric.Sample, error)
+}
+
+// Функция GetMetricRangeHandler возвращает историю значений метрики.
+// Параметры запроса from и to принимают время в формате RFC3339 или
+// unix-время в секундах, step - длительность (например, 1m) или секунды.
+func GetMetricRangeHandler(
+	logger *zap.SugaredLogger,
+	p MetricRangeProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/history/:type/:name get metric range")
+		labels, err := parseLabels(c)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetMetricRange, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		m := metric.Metrics{
+			ID:     c.Param("name"),
+			MType:  c.Param("type"),
+			Labels: labels,
+		}
+		to, err := parseTime(c.Query("to"), time.Now())
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetMetricRange, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		from, err := parseTime(c.Query("from"), to.Add(-defaultRangeWindow))
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetMetricRange, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		step, err := parseStep(c.Query("step"))
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetMetricRange, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		logger.Infof("metric range was requested with type: %s, name: %s, from: %v, to: %v, step: %v",
+			m.MType, m.ID, from, to, step)
+
+		var samples []metric.Sample
+		err = retry.Retry(logger, 3, func() error {
+			samples, err = p.GetMetricRange(c, m, from, to, step)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetMetricRange, err)
+			switch {
+			case errors.Is(err, storage.ErrIsMetricDoesntExist):
+				c.Status(http.StatusNotFound)
+			case errors.Is(err, storage.ErrIsUnknownType),
+				errors.Is(err, storage.ErrInvalidRange):
+				c.Status(http.StatusBadRequest)
+			default:
+				c.Status(http.StatusInternalServerError)
+			}
+			return
+		}
+		logger.Infof("metric range get success with type: %s, name: %s, samples: %d",
+			m.MType, m.ID, len(samples))
+		c.JSON(http.StatusOK, samples)
+	}
+}
+
+// Функция parseTime разбирает время в формате RFC3339 или unix-время
+// в секундах. Для пустой строки возвращается def.
+func parseTime(s string, def time.Time) (time.Time, error) {
+	if s == "" {
+		return def, nil
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(sec, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// Функция parseStep разбирает длительность в формате time.Duration
+// или в секундах
+func parseStep(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if sec, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(sec) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
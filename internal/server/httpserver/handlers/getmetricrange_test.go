@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetMetricRange(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	gauge := float64(12.5)
+	ts := time.Unix(1700000000, 0).UTC()
+
+	provider := &MetricRangeProviderMock{
+		GetMetricRangeFunc: func(contextMoqParam context.Context, m metric.Metrics, from, to time.Time, step time.Duration) ([]metric.Sample, error) {
+			if m.MType != "gauge" {
+				return nil, storage.ErrIsUnknownType
+			}
+			if m.ID != "random" {
+				return nil, storage.ErrIsMetricDoesntExist
+			}
+			return []metric.Sample{{Timestamp: ts, Value: &gauge}}, nil
+		},
+	}
+	engine.GET("/history/:type/:name/", GetMetricRangeHandler(l, provider))
+
+	t.Run("get_range_success", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/history/gauge/random/?from=1699990000&to=2023-11-14T22:13:20Z&step=1m", nil)
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusOK, rr.Code)
+		var samples []metric.Sample
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &samples))
+		require.Len(t, samples, 1)
+		require.Equal(t, gauge, *samples[0].Value)
+
+		calls := provider.GetMetricRangeCalls()
+		last := calls[len(calls)-1]
+		require.Equal(t, time.Unix(1699990000, 0), last.TimeMoqParam1)
+		require.True(t, ts.Equal(last.TimeMoqParam2))
+		require.Equal(t, time.Minute, last.Duration)
+	})
+
+	t.Run("get_range_invalid_step", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/history/gauge/random/?step=abc", nil)
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("get_range_not_found", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/history/gauge/notexist/", nil)
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+
+		require.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	store "github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetPrometheusMetrics = "error in GET /metrics"
+
+	// Тип содержимого текстового формата Prometheus
+	prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+//go:generate moq -out prometheusMetricsProvider_moq_test.go . PrometheusMetricsProvider
+type PrometheusMetricsProvider interface {
+	GetMetrics(context.Context) (map[string][]store.Metric, error)
+}
+
+// Функция GetPrometheusMetricsHandler возвращает все метрики в текстовом
+// формате Prometheus. Имена метрик и меток приводятся к допустимым
+// в Prometheus, гистограммы представляются сериями _bucket, _sum и _count.
+func GetPrometheusMetricsHandler(
+	logger *zap.SugaredLogger,
+	p PrometheusMetricsProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/metrics get metrics in prometheus format")
+		var data map[string][]store.Metric
+		var err error
+		err = retry.Retry(logger, 3, func() error {
+			data, err = p.GetMetrics(c)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetPrometheusMetrics, err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Data(http.StatusOK, prometheusContentType, formatPrometheus(logger, data))
+	}
+}
+
+// Тип promFamily - семейство серий с одним именем и типом
+type promFamily struct {
+	mtype  string
+	series []promSeries
+}
+
+// Тип promSeries - строки одной серии, key - ее метки для сортировки
+type promSeries struct {
+	key   string
+	lines []string
+}
+
+// Функция formatPrometheus формирует текстовое представление метрик.
+// Семейства и серии сортируются, чтобы вывод не зависел от порядка хранения.
+func formatPrometheus(logger *zap.SugaredLogger, data map[string][]store.Metric) []byte {
+	families := make(map[string]*promFamily)
+	for mtype, metrics := range data {
+		for _, m := range metrics {
+			name := promMetricName(m.Name)
+			f, ok := families[name]
+			if !ok {
+				f = &promFamily{mtype: mtype}
+				families[name] = f
+			}
+			if f.mtype != mtype {
+				logger.Errorf("%s: metric %s has conflicting types %s and %s",
+					errPointGetPrometheusMetrics, name, f.mtype, mtype)
+				continue
+			}
+			lines, err := promLines(name, mtype, m)
+			if err != nil {
+				logger.Errorf("%s: metric %s: %v", errPointGetPrometheusMetrics, name, err)
+				continue
+			}
+			f.series = append(f.series, promSeries{key: promLabels(m.Labels, "", ""), lines: lines})
+		}
+	}
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		f := families[name]
+		if len(f.series) == 0 {
+			continue
+		}
+		sort.Slice(f.series, func(i, j int) bool {
+			return f.series[i].key < f.series[j].key
+		})
+		buf.WriteString("# TYPE " + name + " " + f.mtype + "\n")
+		for _, s := range f.series {
+			for _, line := range s.lines {
+				buf.WriteString(line)
+				buf.WriteByte('\n')
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// Функция promLines возвращает строки серий одной метрики
+func promLines(name, mtype string, m store.Metric) ([]string, error) {
+	switch mtype {
+	case metric.TypeGauge.String(), metric.TypeCounter.String():
+		v, err := strconv.ParseFloat(m.Value, 64)
+		if err != nil {
+			return nil, err
+		}
+		return []string{name + promLabels(m.Labels, "", "") + " " + promValue(v)}, nil
+	case metric.TypeHistogram.String():
+		h, err := metric.ParseHistogram(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		lines := make([]string, 0, len(h.Counts)+2)
+		var cumulative uint64
+		for i, c := range h.Counts {
+			cumulative += c
+			le := "+Inf"
+			if i < len(h.Bounds) {
+				le = promValue(h.Bounds[i])
+			}
+			lines = append(lines, name+"_bucket"+promLabels(m.Labels, "le", le)+" "+
+				strconv.FormatUint(cumulative, 10))
+		}
+		lines = append(lines,
+			name+"_sum"+promLabels(m.Labels, "", "")+" "+promValue(h.Sum),
+			name+"_count"+promLabels(m.Labels, "", "")+" "+strconv.FormatUint(h.Count, 10))
+		return lines, nil
+	default:
+		return nil, store.ErrIsUnknownType
+	}
+}
+
+// Функция promLabels формирует набор меток серии. extraName и extraValue
+// задают дополнительную метку, например le для корзин гистограммы.
+func promLabels(labels map[string]string, extraName, extraValue string) string {
+	pairs := make([]string, 0, len(labels)+1)
+	for name, value := range labels {
+		pairs = append(pairs, promLabelName(name)+`="`+promEscape(value)+`"`)
+	}
+	sort.Strings(pairs)
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+promEscape(extraValue)+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Функция promValue форматирует значение, включая NaN и бесконечности
+func promValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Функция promMetricName заменяет недопустимые символы имени метрики
+// на подчеркивание, имя не может начинаться с цифры
+func promMetricName(name string) string {
+	return promSanitize(name, func(r rune) bool { return r == ':' })
+}
+
+// Функция promLabelName заменяет недопустимые символы имени метки
+func promLabelName(name string) string {
+	return promSanitize(name, func(rune) bool { return false })
+}
+
+// Функция promSanitize заменяет символы, отличные от латинских букв,
+// цифр, подчеркивания и разрешенных extra, на подчеркивание
+func promSanitize(name string, extra func(rune) bool) string {
+	var b strings.Builder
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', extra(r):
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+// Функция promEscape экранирует значение метки
+func promEscape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	store "github.com/Eqke/metric-collector/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetPrometheusMetrics(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+
+	tests := []struct {
+		name    string
+		metrics map[string][]store.Metric
+		err     error
+		code    int
+		want    string
+	}{
+		{
+			name: "gauges_and_counters",
+			metrics: map[string][]store.Metric{
+				"gauge": {
+					{Name: "Alloc", Value: "342.42", Labels: map[string]string{"host": "web2"}},
+					{Name: "Alloc", Value: "12", Labels: map[string]string{"host": "web1", "zone-id": "e\"u"}},
+					{Name: "1go.gc", Value: "1"},
+				},
+				"counter": {
+					{Name: "PollCount", Value: "34543"},
+				},
+			},
+			code: http.StatusOK,
+			want: "# TYPE Alloc gauge\n" +
+				"Alloc{host=\"web1\",zone_id=\"e\\\"u\"} 12\n" +
+				"Alloc{host=\"web2\"} 342.42\n" +
+				"# TYPE PollCount counter\n" +
+				"PollCount 34543\n" +
+				"# TYPE _1go_gc gauge\n" +
+				"_1go_gc 1\n",
+		},
+		{
+			name: "histogram",
+			metrics: map[string][]store.Metric{
+				"histogram": {
+					{Name: "GCPauseNs", Value: `{"bounds":[1,2],"counts":[1,2,1],"sum":6.5,"count":4}`},
+				},
+			},
+			code: http.StatusOK,
+			want: "# TYPE GCPauseNs histogram\n" +
+				"GCPauseNs_bucket{le=\"1\"} 1\n" +
+				"GCPauseNs_bucket{le=\"2\"} 3\n" +
+				"GCPauseNs_bucket{le=\"+Inf\"} 4\n" +
+				"GCPauseNs_sum 6.5\n" +
+				"GCPauseNs_count 4\n",
+		},
+		{
+			name: "storage_error",
+			err:  errors.New("storage is unavailable"),
+			code: http.StatusInternalServerError,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			engine := gin.New()
+			provider := &PrometheusMetricsProviderMock{
+				GetMetricsFunc: func(contextMoqParam context.Context) (map[string][]store.Metric, error) {
+					return test.metrics, test.err
+				},
+			}
+			engine.GET("/metrics", GetPrometheusMetricsHandler(l, provider))
+
+			req := httptest.NewRequest("GET", "/metrics", nil)
+			rr := httptest.NewRecorder()
+			engine.ServeHTTP(rr, req)
+
+			require.Equal(t, test.code, rr.Code)
+			if test.code != http.StatusOK {
+				return
+			}
+			require.Equal(t, prometheusContentType, rr.Header().Get("Content-Type"))
+			require.Equal(t, test.want, rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetQuantiles = "error in GET /quantiles/:name"
+)
+
+// Квантили, возвращаемые при отсутствии параметра q
+var defaultQuantiles = []float64{0.5, 0.95, 0.99}
+
+//go:generate moq -out quantileProvider_moq_test.go . QuantileProvider
+type QuantileProvider interface {
+	GetMetric(context.Context, metric.Metrics) (metric.Metrics, error)
+}
+
+// Функция GetQuantilesHandler возвращает оценку квантилей гистограммы.
+// Квантили задаются повторяющимся параметром q в диапазоне [0, 1],
+// по умолчанию возвращаются p50, p95 и p99. Параметр label выбирает
+// серию гистограммы с метками.
+func GetQuantilesHandler(
+	logger *zap.SugaredLogger,
+	p QuantileProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/quantiles/:name get histogram quantiles")
+		qs := defaultQuantiles
+		if params := c.QueryArray("q"); len(params) > 0 {
+			qs = make([]float64, 0, len(params))
+			for _, param := range params {
+				q, err := strconv.ParseFloat(param, 64)
+				if err != nil || q < 0 || q > 1 {
+					logger.Errorf("%s: invalid quantile %s", errPointGetQuantiles, param)
+					c.Status(http.StatusBadRequest)
+					return
+				}
+				qs = append(qs, q)
+			}
+		}
+
+		labels, err := parseLabels(c)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetQuantiles, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		m := metric.Metrics{
+			ID:     c.Param("name"),
+			MType:  metric.TypeHistogram.String(),
+			Labels: labels,
+		}
+		err = retry.Retry(logger, 3, func() error {
+			m, err = p.GetMetric(c, m)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetQuantiles, err)
+			if errors.Is(err, storage.ErrIsMetricDoesntExist) {
+				c.Status(http.StatusNotFound)
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		if m.Histogram == nil || m.Histogram.Count == 0 {
+			logger.Errorf("%s: histogram %s is empty", errPointGetQuantiles, m.ID)
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		// Квантиль, который нельзя оценить по границам корзин, не
+		// возвращается: NaN не кодируется в JSON
+		quantiles := make(map[string]float64, len(qs))
+		for _, q := range qs {
+			v := m.Histogram.Quantile(q)
+			if math.IsNaN(v) {
+				logger.Infof("%s: quantile %v of %s is undefined", errPointGetQuantiles, q, m.ID)
+				continue
+			}
+			quantiles[strconv.FormatFloat(q, 'f', -1, 64)] = v
+		}
+		logger.Infof("quantiles get success with name: %s", m.ID)
+		c.JSON(http.StatusOK, gin.H{
+			"id":        m.ID,
+			"count":     m.Histogram.Count,
+			"sum":       m.Histogram.Sum,
+			"quantiles": quantiles,
+		})
+	}
+}
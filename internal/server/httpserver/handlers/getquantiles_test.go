@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetQuantiles(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	provider := &QuantileProviderMock{
+		GetMetricFunc: func(contextMoqParam context.Context, m metric.Metrics) (metric.Metrics, error) {
+			if m.ID != "latency" || m.Labels["host"] == "unknown" {
+				return m, storage.ErrIsMetricDoesntExist
+			}
+			if m.Labels["host"] == "unbounded" {
+				// Все значения в корзине +Inf: квантили не определены
+				m.Histogram = &metric.Histogram{Counts: []uint64{10}, Sum: 15, Count: 10}
+				return m, nil
+			}
+			m.Histogram = &metric.Histogram{
+				Bounds: []float64{1, 2, 4},
+				Counts: []uint64{0, 10, 0, 0},
+				Sum:    15,
+				Count:  10,
+			}
+			return m, nil
+		},
+	}
+	engine.GET("/quantiles/:name/", GetQuantilesHandler(l, provider))
+
+	type response struct {
+		ID        string             `json:"id"`
+		Count     uint64             `json:"count"`
+		Quantiles map[string]float64 `json:"quantiles"`
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		code      int
+		quantiles map[string]float64
+	}{
+		{
+			name:      "default_quantiles",
+			url:       "/quantiles/latency/",
+			code:      http.StatusOK,
+			quantiles: map[string]float64{"0.5": 1.5, "0.95": 1.95, "0.99": 1.99},
+		},
+		{
+			name:      "custom_quantiles",
+			url:       "/quantiles/latency/?q=0.1&q=1",
+			code:      http.StatusOK,
+			quantiles: map[string]float64{"0.1": 1.1, "1": 2},
+		},
+		{
+			name:      "labels",
+			url:       "/quantiles/latency/?label=host=web1&q=0.5",
+			code:      http.StatusOK,
+			quantiles: map[string]float64{"0.5": 1.5},
+		},
+		{
+			name:      "undefined_quantiles",
+			url:       "/quantiles/latency/?label=host=unbounded",
+			code:      http.StatusOK,
+			quantiles: map[string]float64{},
+		},
+		{
+			name: "unknown_labels",
+			url:  "/quantiles/latency/?label=host=unknown",
+			code: http.StatusNotFound,
+		},
+		{
+			name: "invalid_label",
+			url:  "/quantiles/latency/?label=host",
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_quantile",
+			url:  "/quantiles/latency/?q=1.5",
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "not_found",
+			url:  "/quantiles/unknown/",
+			code: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", test.url, nil)
+			rr := httptest.NewRecorder()
+			engine.ServeHTTP(rr, req)
+
+			require.Equal(t, test.code, rr.Code)
+			if test.code != http.StatusOK {
+				return
+			}
+			var resp response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, "latency", resp.ID)
+			require.Equal(t, uint64(10), resp.Count)
+			require.Len(t, resp.Quantiles, len(test.quantiles))
+			for q, v := range test.quantiles {
+				require.InDelta(t, v, resp.Quantiles[q], 1e-9)
+			}
+		})
+	}
+}
@@ -20,6 +20,9 @@ type RootMetricsProvider interface {
 	GetMetrics(context.Context) (map[string][]store.Metric, error)
 }
 
+// Функция GetRootMetricsHandler возвращает все метрики. Клиентам,
+// принимающим text/html (браузерам), выводится HTML-страница, остальным -
+// JSON.
 func GetRootMetricsHandler(
 	logger *zap.SugaredLogger,
 	p RootMetricsProvider) gin.HandlerFunc {
@@ -36,6 +39,15 @@ func GetRootMetricsHandler(
 			c.Status(http.StatusInternalServerError)
 			return
 		}
+		if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) == gin.MIMEHTML {
+			if err = renderRootPage(c, data); err != nil {
+				logger.Errorf("%s: %v", errPointGetRootMetrics, err)
+				c.Status(http.StatusInternalServerError)
+				return
+			}
+			logger.Info("get metrics page was success")
+			return
+		}
 		bytes, err := json.MarshalIndent(data, "", " ")
 		if err != nil {
 			logger.Errorf("%s: %v", errPointGetRootMetrics, err)
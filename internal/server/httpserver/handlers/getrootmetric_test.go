@@ -52,4 +52,50 @@ func TestRootMetric(t *testing.T) {
 		require.Equal(t, http.StatusOK, rr.Code)
 
 	})
+
+	t.Run("get_root_metric_html", func(t *testing.T) {
+		l := zaptest.NewLogger(t).Sugar()
+
+		gin.DisableConsoleColor()
+		gin.SetMode(gin.ReleaseMode)
+		engine := gin.New()
+		engine.RedirectFixedPath = true
+
+		provider := &RootMetricsProviderMock{
+			GetMetricsFunc: func(contextMoqParam context.Context) (map[string][]store.Metric, error) {
+				return map[string][]store.Metric{
+					"gauge": {{Name: "some_gauge", Value: "342.42", Labels: map[string]string{"host": "web1"}}},
+				}, nil
+			},
+		}
+		engine.GET("/", GetRootMetricsHandler(l, provider))
+
+		tests := []struct {
+			name        string
+			accept      string
+			contentType string
+		}{
+			{name: "browser", accept: "text/html,application/xhtml+xml,*/*;q=0.8", contentType: "text/html; charset=utf-8"},
+			{name: "any", accept: "*/*", contentType: "html/text"},
+			{name: "json", accept: "application/json", contentType: "html/text"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest("GET", "/?refresh=0", nil)
+				req.Header.Set("Accept", tt.accept)
+				w := httptest.NewRecorder()
+				engine.ServeHTTP(w, req)
+
+				require.Equal(t, http.StatusOK, w.Code)
+				require.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+				if tt.contentType == "html/text" {
+					return
+				}
+				body := w.Body.String()
+				require.Contains(t, body, `<a href="/view/gauge/some_gauge/">some_gauge</a>`)
+				require.Contains(t, body, "host=&#34;web1&#34;")
+				require.NotContains(t, body, `http-equiv="refresh"`)
+			})
+		}
+	})
 }
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetSeries = "error in GET /series/:type/:name"
+)
+
+//go:generate moq -out seriesProvider_moq_test.go . SeriesProvider
+type SeriesProvider interface {
+	FindMetrics(context.Context, metric.Metrics, []*metric.LabelMatcher) ([]metric.Metrics, error)
+}
+
+// Функция GetSeriesHandler возвращает все серии метрики, метки которых
+// удовлетворяют условиям. Условия задаются повторяющимся параметром
+// match в виде name=value, name!=value, name=~regexp или name!~regexp.
+func GetSeriesHandler(
+	logger *zap.SugaredLogger,
+	p SeriesProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/series/:type/:name get metric series")
+		matchers, err := metric.ParseLabelMatchers(c.QueryArray("match"))
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetSeries, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		m := metric.Metrics{
+			ID:    c.Param("name"),
+			MType: c.Param("type"),
+		}
+		var series []metric.Metrics
+		err = retry.Retry(logger, 3, func() error {
+			series, err = p.FindMetrics(c, m, matchers)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointGetSeries, err)
+			if errors.Is(err, storage.ErrIsUnknownType) {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		logger.Infof("metric series get success with type: %s, name: %s, series: %d",
+			m.MType, m.ID, len(series))
+		c.JSON(http.StatusOK, series)
+	}
+}
+
+// Функция parseLabels разбирает метки из повторяющегося параметра
+// запроса label вида name=value
+func parseLabels(c *gin.Context) (map[string]string, error) {
+	return metric.ParseLabels(c.QueryArray("label"))
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetSeries(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	web1, web2 := float64(1), float64(2)
+	series := []metric.Metrics{
+		{ID: "Alloc", MType: "gauge", Value: &web1, Labels: map[string]string{"host": "web1"}},
+		{ID: "Alloc", MType: "gauge", Value: &web2, Labels: map[string]string{"host": "web2"}},
+	}
+	provider := &SeriesProviderMock{
+		FindMetricsFunc: func(contextMoqParam context.Context, m metric.Metrics, matchers []*metric.LabelMatcher) ([]metric.Metrics, error) {
+			if m.MType != "gauge" {
+				return nil, storage.ErrIsUnknownType
+			}
+			res := make([]metric.Metrics, 0)
+			for _, s := range series {
+				if s.ID == m.ID && metric.MatchLabels(s.Labels, matchers) {
+					res = append(res, s)
+				}
+			}
+			return res, nil
+		},
+	}
+	engine.GET("/series/:type/:name/", GetSeriesHandler(l, provider))
+
+	tests := []struct {
+		name  string
+		url   string
+		code  int
+		hosts []string
+	}{
+		{
+			name:  "all_series",
+			url:   "/series/gauge/Alloc/",
+			code:  http.StatusOK,
+			hosts: []string{"web1", "web2"},
+		},
+		{
+			name:  "regexp_matcher",
+			url:   "/series/gauge/Alloc/?match=host=~web2|db1",
+			code:  http.StatusOK,
+			hosts: []string{"web2"},
+		},
+		{
+			name:  "no_series",
+			url:   "/series/gauge/Alloc/?match=host!~web.*",
+			code:  http.StatusOK,
+			hosts: []string{},
+		},
+		{
+			name: "invalid_matcher",
+			url:  "/series/gauge/Alloc/?match=host",
+			code: http.StatusBadRequest,
+		},
+		{
+			name: "unknown_type",
+			url:  "/series/unknown/Alloc/",
+			code: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", test.url, nil)
+			rr := httptest.NewRecorder()
+			engine.ServeHTTP(rr, req)
+
+			require.Equal(t, test.code, rr.Code)
+			if test.code != http.StatusOK {
+				return
+			}
+			var got []metric.Metrics
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			hosts := make([]string, 0, len(got))
+			for _, m := range got {
+				hosts = append(hosts, m.Labels["host"])
+			}
+			require.Equal(t, test.hosts, hosts)
+		})
+	}
+}
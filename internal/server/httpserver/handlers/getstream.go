@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/hub"
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointGetStream = "error in GET /stream"
+
+	// Период отправки комментария, поддерживающего соединение
+	streamKeepAlive = 15 * time.Second
+)
+
+//go:generate moq -out streamProvider_moq_test.go . StreamProvider
+type StreamProvider interface {
+	Subscribe(hub.Filter) *hub.Subscription
+	Unsubscribe(*hub.Subscription)
+}
+
+// Функция GetStreamHandler передает принятые метрики как Server-Sent
+// Events с именем события metric и метрикой в формате JSON.
+// Передаются только метрики арендатора запроса. Параметры type
+// (повторяющийся) и prefix ограничивают типы и имена метрик.
+// Соединение закрывается клиентом или при остановке сервера.
+func GetStreamHandler(
+	logger *zap.SugaredLogger,
+	p StreamProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/stream subscribe to metric updates")
+		filter := hub.Filter{
+			Tenant: tenant.FromContext(c),
+			Types:  c.QueryArray("type"),
+			Prefix: c.Query("prefix"),
+		}
+		for _, t := range filter.Types {
+			if t != metric.TypeGauge.String() &&
+				t != metric.TypeCounter.String() &&
+				t != metric.TypeHistogram.String() {
+				logger.Errorf("%s: unknown metric type %s", errPointGetStream, t)
+				c.Status(http.StatusBadRequest)
+				return
+			}
+		}
+
+		sub := p.Subscribe(filter)
+		defer p.Unsubscribe(sub)
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				logger.Info("/stream client disconnected")
+				return
+			case m, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				c.SSEvent("metric", m)
+			case <-keepAlive.C:
+				if _, err := c.Writer.WriteString(": keep-alive\n\n"); err != nil {
+					logger.Errorf("%s: %v", errPointGetStream, err)
+					return
+				}
+			}
+			c.Writer.Flush()
+		}
+	}
+}
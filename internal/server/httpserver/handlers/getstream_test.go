@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/hub"
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestGetStreamHandler(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	h := hub.New(l)
+	subscribed := make(chan struct{}, 1)
+	provider := &StreamProviderMock{
+		SubscribeFunc: func(filter hub.Filter) *hub.Subscription {
+			defer func() { subscribed <- struct{}{} }()
+			return h.Subscribe(filter)
+		},
+		UnsubscribeFunc: h.Unsubscribe,
+	}
+	engine.GET("/stream", GetStreamHandler(l, provider))
+
+	t.Run("stream_filtered_updates", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/stream?type=gauge&prefix=Heap", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		done := make(chan struct{})
+		go func() {
+			engine.ServeHTTP(w, req)
+			close(done)
+		}()
+		<-subscribed
+
+		heap, alloc := 1.5, 2.5
+		delta := int64(1)
+		h.Publish("team-a", metric.Metrics{ID: "HeapAlloc", MType: "gauge", Value: &heap})
+		h.Publish(tenant.Default,
+			metric.Metrics{ID: "HeapAlloc", MType: "gauge", Value: &heap},
+			metric.Metrics{ID: "Alloc", MType: "gauge", Value: &alloc},
+			metric.Metrics{ID: "HeapObjects", MType: "counter", Delta: &delta},
+		)
+		// Закрытие Hub завершает поток после отправки накопленных событий
+		h.Close()
+		<-done
+
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		require.Equal(t, 1, strings.Count(body, "event:metric"))
+		require.Contains(t, body, `"id":"HeapAlloc"`)
+		require.Len(t, provider.UnsubscribeCalls(), 1)
+	})
+
+	t.Run("unknown_type", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "/stream?type=unknown", nil)
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -0,0 +1,158 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Ensure, that MetricPageProviderMock does implement MetricPageProvider.
+// If this is not the case, regenerate this file with moq.
+var _ MetricPageProvider = &MetricPageProviderMock{}
+
+// MetricPageProviderMock is a mock implementation of MetricPageProvider.
+//
+//	func TestSomethingThatUsesMetricPageProvider(t *testing.T) {
+//
+//		// make and configure a mocked MetricPageProvider
+//		mockedMetricPageProvider := &MetricPageProviderMock{
+//			FindMetricsFunc: func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error) {
+//				panic("mock out the FindMetrics method")
+//			},
+//			GetMetricRangeFunc: func(contextMoqParam context.Context, metrics metric.Metrics, timeMoqParam1 time.Time, timeMoqParam2 time.Time, duration time.Duration) ([]metric.Sample, error) {
+//				panic("mock out the GetMetricRange method")
+//			},
+//		}
+//
+//		// use mockedMetricPageProvider in code that requires MetricPageProvider
+//		// and then make assertions.
+//
+//	}
+type MetricPageProviderMock struct {
+	// FindMetricsFunc mocks the FindMetrics method.
+	FindMetricsFunc func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error)
+
+	// GetMetricRangeFunc mocks the GetMetricRange method.
+	GetMetricRangeFunc func(contextMoqParam context.Context, metrics metric.Metrics, timeMoqParam1 time.Time, timeMoqParam2 time.Time, duration time.Duration) ([]metric.Sample, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// FindMetrics holds details about calls to the FindMetrics method.
+		FindMetrics []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+			// LabelMatchers is the labelMatchers argument value.
+			LabelMatchers []*metric.LabelMatcher
+		}
+		// GetMetricRange holds details about calls to the GetMetricRange method.
+		GetMetricRange []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+			// TimeMoqParam1 is the timeMoqParam1 argument value.
+			TimeMoqParam1 time.Time
+			// TimeMoqParam2 is the timeMoqParam2 argument value.
+			TimeMoqParam2 time.Time
+			// Duration is the duration argument value.
+			Duration time.Duration
+		}
+	}
+	lockFindMetrics    sync.RWMutex
+	lockGetMetricRange sync.RWMutex
+}
+
+// FindMetrics calls FindMetricsFunc.
+func (mock *MetricPageProviderMock) FindMetrics(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error) {
+	if mock.FindMetricsFunc == nil {
+		panic("MetricPageProviderMock.FindMetricsFunc: method is nil but MetricPageProvider.FindMetrics was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+		LabelMatchers:   labelMatchers,
+	}
+	mock.lockFindMetrics.Lock()
+	mock.calls.FindMetrics = append(mock.calls.FindMetrics, callInfo)
+	mock.lockFindMetrics.Unlock()
+	return mock.FindMetricsFunc(contextMoqParam, metrics, labelMatchers)
+}
+
+// FindMetricsCalls gets all the calls that were made to FindMetrics.
+// Check the length with:
+//
+//	len(mockedMetricPageProvider.FindMetricsCalls())
+func (mock *MetricPageProviderMock) FindMetricsCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+	LabelMatchers   []*metric.LabelMatcher
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}
+	mock.lockFindMetrics.RLock()
+	calls = mock.calls.FindMetrics
+	mock.lockFindMetrics.RUnlock()
+	return calls
+}
+
+// GetMetricRange calls GetMetricRangeFunc.
+func (mock *MetricPageProviderMock) GetMetricRange(contextMoqParam context.Context, metrics metric.Metrics, timeMoqParam1 time.Time, timeMoqParam2 time.Time, duration time.Duration) ([]metric.Sample, error) {
+	if mock.GetMetricRangeFunc == nil {
+		panic("MetricPageProviderMock.GetMetricRangeFunc: method is nil but MetricPageProvider.GetMetricRange was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		TimeMoqParam1   time.Time
+		TimeMoqParam2   time.Time
+		Duration        time.Duration
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+		TimeMoqParam1:   timeMoqParam1,
+		TimeMoqParam2:   timeMoqParam2,
+		Duration:        duration,
+	}
+	mock.lockGetMetricRange.Lock()
+	mock.calls.GetMetricRange = append(mock.calls.GetMetricRange, callInfo)
+	mock.lockGetMetricRange.Unlock()
+	return mock.GetMetricRangeFunc(contextMoqParam, metrics, timeMoqParam1, timeMoqParam2, duration)
+}
+
+// GetMetricRangeCalls gets all the calls that were made to GetMetricRange.
+// Check the length with:
+//
+//	len(mockedMetricPageProvider.GetMetricRangeCalls())
+func (mock *MetricPageProviderMock) GetMetricRangeCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+	TimeMoqParam1   time.Time
+	TimeMoqParam2   time.Time
+	Duration        time.Duration
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		TimeMoqParam1   time.Time
+		TimeMoqParam2   time.Time
+		Duration        time.Duration
+	}
+	mock.lockGetMetricRange.RLock()
+	calls = mock.calls.GetMetricRange
+	mock.lockGetMetricRange.RUnlock()
+	return calls
+}
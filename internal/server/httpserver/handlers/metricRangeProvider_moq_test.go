@@ -0,0 +1,102 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Ensure, that MetricRangeProviderMock does implement MetricRangeProvider.
+// If this is not the case, regenerate this file with moq.
+var _ MetricRangeProvider = &MetricRangeProviderMock{}
+
+// MetricRangeProviderMock is a mock implementation of MetricRangeProvider.
+//
+//	func TestSomethingThatUsesMetricRangeProvider(t *testing.T) {
+//
+//		// make and configure a mocked MetricRangeProvider
+//		mockedMetricRangeProvider := &MetricRangeProviderMock{
+//			GetMetricRangeFunc: func(contextMoqParam context.Context, metrics metric.Metrics, timeMoqParam1 time.Time, timeMoqParam2 time.Time, duration time.Duration) ([]metric.Sample, error) {
+//				panic("mock out the GetMetricRange method")
+//			},
+//		}
+//
+//		// use mockedMetricRangeProvider in code that requires MetricRangeProvider
+//		// and then make assertions.
+//
+//	}
+type MetricRangeProviderMock struct {
+	// GetMetricRangeFunc mocks the GetMetricRange method.
+	GetMetricRangeFunc func(contextMoqParam context.Context, metrics metric.Metrics, timeMoqParam1 time.Time, timeMoqParam2 time.Time, duration time.Duration) ([]metric.Sample, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetMetricRange holds details about calls to the GetMetricRange method.
+		GetMetricRange []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+			// TimeMoqParam1 is the timeMoqParam1 argument value.
+			TimeMoqParam1 time.Time
+			// TimeMoqParam2 is the timeMoqParam2 argument value.
+			TimeMoqParam2 time.Time
+			// Duration is the duration argument value.
+			Duration time.Duration
+		}
+	}
+	lockGetMetricRange sync.RWMutex
+}
+
+// GetMetricRange calls GetMetricRangeFunc.
+func (mock *MetricRangeProviderMock) GetMetricRange(contextMoqParam context.Context, metrics metric.Metrics, timeMoqParam1 time.Time, timeMoqParam2 time.Time, duration time.Duration) ([]metric.Sample, error) {
+	if mock.GetMetricRangeFunc == nil {
+		panic("MetricRangeProviderMock.GetMetricRangeFunc: method is nil but MetricRangeProvider.GetMetricRange was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		TimeMoqParam1   time.Time
+		TimeMoqParam2   time.Time
+		Duration        time.Duration
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+		TimeMoqParam1:   timeMoqParam1,
+		TimeMoqParam2:   timeMoqParam2,
+		Duration:        duration,
+	}
+	mock.lockGetMetricRange.Lock()
+	mock.calls.GetMetricRange = append(mock.calls.GetMetricRange, callInfo)
+	mock.lockGetMetricRange.Unlock()
+	return mock.GetMetricRangeFunc(contextMoqParam, metrics, timeMoqParam1, timeMoqParam2, duration)
+}
+
+// GetMetricRangeCalls gets all the calls that were made to GetMetricRange.
+// Check the length with:
+//
+//	len(mockedMetricRangeProvider.GetMetricRangeCalls())
+func (mock *MetricRangeProviderMock) GetMetricRangeCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+	TimeMoqParam1   time.Time
+	TimeMoqParam2   time.Time
+	Duration        time.Duration
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		TimeMoqParam1   time.Time
+		TimeMoqParam2   time.Time
+		Duration        time.Duration
+	}
+	mock.lockGetMetricRange.RLock()
+	calls = mock.calls.GetMetricRange
+	mock.lockGetMetricRange.RUnlock()
+	return calls
+}
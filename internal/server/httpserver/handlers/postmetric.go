@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
+	"github.com/Eqke/metric-collector/internal/storage"
 	"github.com/Eqke/metric-collector/pkg/metric"
 	"github.com/Eqke/metric-collector/utils/retry"
 	"github.com/gin-gonic/gin"
@@ -38,14 +40,25 @@ func POSTMetricHandler(
 			c.Status(http.StatusNotFound)
 			return
 		}
+		labels, err := parseLabels(c)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointPostMetric, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		metricName = metric.SeriesKey(metricName, labels)
 		metricValue := c.Param("value")
 		logger.Infof("metric was received with type: %s, name: %s, value: %s",
 			metricType, metricName, metricValue)
-		err := retry.Retry(logger, 3, func() error {
+		err = retry.Retry(logger, 3, func() error {
 			return p.SetValue(c, metricType, metricName, metricValue)
 		})
 		if err != nil {
 			logger.Errorf("%s: %v", errPointPostMetric, err)
+			if errors.Is(err, storage.ErrSeriesLimitExceeded) {
+				c.Status(http.StatusTooManyRequests)
+				return
+			}
 			c.Status(http.StatusBadRequest)
 			return
 		}
@@ -99,4 +99,21 @@ func TestPostMetric(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, w.Code)
 	})
 
+	t.Run("post_with_labels", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/update/gauge/random/1/?label=zone=eu&label=host=web1", nil)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		calls := provider.SetValueCalls()
+		require.Equal(t, `random{host="web1",zone="eu"}`, calls[len(calls)-1].S2)
+	})
+
+	t.Run("post_with_invalid_label", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/update/gauge/random/1/?label=host", nil)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
 }
@@ -44,7 +44,14 @@ func POSTMetricJSONHandler(
 			return p.SetMetric(c, m)
 		}); err != nil {
 			logger.Errorf("%s: %v", errPointPostMetricJSON, err)
-			if errors.Is(err, storage.ErrIsUnknownType) {
+			if errors.Is(err, storage.ErrSeriesLimitExceeded) {
+				c.Status(http.StatusTooManyRequests)
+				return
+			}
+			if errors.Is(err, storage.ErrIsUnknownType) ||
+				errors.Is(err, metric.ErrInvalidHistogram) ||
+				errors.Is(err, metric.ErrBucketsMismatch) ||
+				errors.Is(err, metric.ErrInvalidLabel) {
 				c.Status(http.StatusBadRequest)
 				return
 			}
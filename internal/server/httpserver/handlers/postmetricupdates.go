@@ -38,12 +38,36 @@ func PostMetricUpdates(
 			return
 		}
 
+		// Пакет с идентификатором применяется не более одного раза, даже
+		// если агент повторил запрос, ответ на который не получил
+		var ctx context.Context = c
+		if batchID := c.GetHeader(storage.BatchIDHeader); batchID != "" {
+			if err := storage.ValidateBatchID(batchID); err != nil {
+				logger.Errorf("%s: %v", errPostMetricUpdates, err)
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			ctx = storage.WithBatchID(c, batchID)
+		}
+
 		logger.Info("metrics batch was recieved")
 		if err := retry.Retry(logger, 3, func() error {
-			return p.SetMetrics(c, arr)
+			err := p.SetMetrics(ctx, arr)
+			if errors.Is(err, storage.ErrBatchApplied) {
+				logger.Infof("metrics batch %s was already applied", storage.BatchIDFromContext(ctx))
+				return nil
+			}
+			return err
 		}); err != nil {
 			logger.Errorf("%s: %v", err, storage.ErrIsUnknownType)
-			if errors.Is(err, storage.ErrIsUnknownType) {
+			if errors.Is(err, storage.ErrSeriesLimitExceeded) {
+				c.Status(http.StatusTooManyRequests)
+				return
+			}
+			if errors.Is(err, storage.ErrIsUnknownType) ||
+				errors.Is(err, metric.ErrInvalidHistogram) ||
+				errors.Is(err, metric.ErrBucketsMismatch) ||
+				errors.Is(err, metric.ErrInvalidLabel) {
 				c.Status(http.StatusBadRequest)
 				return
 			}
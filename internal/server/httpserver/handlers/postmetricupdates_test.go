@@ -23,12 +23,23 @@ func TestPostMetricUpdates(t *testing.T) {
 	engine := gin.New()
 	engine.RedirectFixedPath = true
 
+	// <BatchID, число применений>
+	applied := make(map[string]int)
 	provider := &BatchMetricProviderMock{
 		SetMetricsFunc: func(contextMoqParam context.Context, ms []metric.Metrics) error {
+			if id := store.BatchIDFromContext(contextMoqParam); id != "" {
+				if applied[id] > 0 {
+					return store.ErrBatchApplied
+				}
+				applied[id]++
+			}
 			for _, m := range ms {
 				if m.ID == "" {
 					return store.ErrIDIsEmpty
 				}
+				if m.ID == "limited" {
+					return store.ErrSeriesLimitExceeded
+				}
 				switch m.MType {
 				case "gauge":
 					{
@@ -89,8 +100,40 @@ func TestPostMetricUpdates(t *testing.T) {
 		require.Equal(t, http.StatusOK, w.Code)
 	})
 
+	t.Run("batch_id", func(t *testing.T) {
+		b, err := json.Marshal([]metric.Metrics{{ID: "counter", MType: "counter", Delta: &counter}})
+		require.NoError(t, err)
+		// Повтор пакета подтверждается, но не применяется
+		for range 2 {
+			req, err := http.NewRequest("POST", "/updates/", bytes.NewBuffer(b))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(store.BatchIDHeader, "agent-1:42")
+			w := httptest.NewRecorder()
+
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+		}
+		require.Equal(t, 1, applied["agent-1:42"])
+	})
+
 	t.Run("update_error", func(t *testing.T) {
 
+		t.Run("invalid_batch_id", func(t *testing.T) {
+			b, err := json.Marshal([]metric.Metrics{{ID: "gauge", MType: "gauge", Value: &gauge}})
+			require.NoError(t, err)
+			req, err := http.NewRequest("POST", "/updates/", bytes.NewBuffer(b))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(store.BatchIDHeader, "bad id")
+			w := httptest.NewRecorder()
+
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+		})
+
 		t.Run("bad_content_type", func(t *testing.T) {
 
 			batch := []metric.Metrics{
@@ -123,6 +166,31 @@ func TestPostMetricUpdates(t *testing.T) {
 			require.Equal(t, http.StatusBadRequest, w.Code)
 		})
 
+		t.Run("series_limit", func(t *testing.T) {
+			batch := []metric.Metrics{
+				{
+					ID:    "limited",
+					MType: "gauge",
+					Value: &gauge,
+				},
+			}
+			b, err := json.Marshal(batch)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := http.NewRequest("POST", "/updates/", bytes.NewBuffer(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusTooManyRequests, w.Code)
+		})
+
 		t.Run("gauge_error", func(t *testing.T) {
 			batch := []metric.Metrics{
 				{
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/pkg/otlp"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointPostOTLPMetrics = "error in POST /v1/metrics"
+
+	// Типы содержимого OTLP/HTTP
+	otlpProtobufContentType = "application/x-protobuf"
+	otlpJSONContentType     = "application/json"
+)
+
+// Функция PostOTLPMetricsHandler принимает метрики OpenTelemetry по
+// OTLP/HTTP в кодировке protobuf или JSON. Правила преобразования
+// описаны в otlp.Converter. Ответ - пустой ExportMetricsServiceResponse
+// в кодировке запроса.
+func PostOTLPMetricsHandler(
+	logger *zap.SugaredLogger,
+	p BatchMetricProvider) gin.HandlerFunc {
+	converter := otlp.NewConverter()
+	return func(c *gin.Context) {
+		logger.Info("/v1/metrics: recieving otlp metrics")
+		contentType := c.ContentType()
+		if contentType != otlpProtobufContentType && contentType != otlpJSONContentType {
+			logger.Errorf("%s: unknown content type %s", errPointPostOTLPMetrics, contentType)
+			c.Status(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointPostOTLPMetrics, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+
+		var req *otlp.Request
+		if contentType == otlpProtobufContentType {
+			req, err = otlp.Unmarshal(body)
+		} else {
+			req, err = otlp.UnmarshalJSON(body)
+		}
+		if err != nil {
+			logger.Errorf("%s: %v", errPointPostOTLPMetrics, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+
+		n, err := converter.Write(tenant.FromContext(c), req, func(metrics []metric.Metrics) error {
+			return retry.Retry(logger, 3, func() error {
+				return p.SetMetrics(c, metrics)
+			})
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointPostOTLPMetrics, err)
+			if errors.Is(err, storage.ErrSeriesLimitExceeded) {
+				c.Status(http.StatusTooManyRequests)
+				return
+			}
+			if errors.Is(err, metric.ErrInvalidHistogram) ||
+				errors.Is(err, metric.ErrBucketsMismatch) ||
+				errors.Is(err, metric.ErrInvalidLabel) ||
+				errors.Is(err, storage.ErrIDIsEmpty) {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		logger.Infof("otlp metrics were saved: %d", n)
+		if contentType == otlpProtobufContentType {
+			c.Data(http.StatusOK, otlpProtobufContentType, nil)
+			return
+		}
+		c.Data(http.StatusOK, otlpJSONContentType, []byte("{}"))
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestPostOTLPMetricsHandler(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	var stored []metric.Metrics
+	provider := &BatchMetricProviderMock{
+		SetMetricsFunc: func(contextMoqParam context.Context, ms []metric.Metrics) error {
+			stored = ms
+			return nil
+		},
+	}
+	engine.POST("/v1/metrics", PostOTLPMetricsHandler(l, provider))
+
+	// Gauge с одной точкой в protobuf-кодировке
+	var point []byte
+	point = protowire.AppendTag(point, 4, protowire.Fixed64Type)
+	point = protowire.AppendFixed64(point, 0x4035800000000000) // 21.5
+	var gauge []byte
+	gauge = protowire.AppendTag(gauge, 1, protowire.BytesType)
+	gauge = protowire.AppendBytes(gauge, point)
+	var m []byte
+	m = protowire.AppendTag(m, 1, protowire.BytesType)
+	m = protowire.AppendString(m, "temperature")
+	m = protowire.AppendTag(m, 5, protowire.BytesType)
+	m = protowire.AppendBytes(m, gauge)
+	var scope []byte
+	scope = protowire.AppendTag(scope, 2, protowire.BytesType)
+	scope = protowire.AppendBytes(scope, m)
+	var rm []byte
+	rm = protowire.AppendTag(rm, 2, protowire.BytesType)
+	rm = protowire.AppendBytes(rm, scope)
+	var protoBody []byte
+	protoBody = protowire.AppendTag(protoBody, 1, protowire.BytesType)
+	protoBody = protowire.AppendBytes(protoBody, rm)
+
+	jsonBody := `{"resourceMetrics":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"shop"}}]},
+		"scopeMetrics":[{"metrics":[{"name":"requests","sum":{"aggregationTemporality":1,"isMonotonic":true,
+		"dataPoints":[{"asInt":"5"}]}}]}]}]}`
+
+	type testCase struct {
+		name        string
+		contentType string
+		body        []byte
+		wantStatus  int
+		wantMetric  metric.Metrics
+	}
+
+	delta := int64(5)
+	value := 21.5
+	tests := []testCase{
+		{
+			name:        "protobuf",
+			contentType: "application/x-protobuf",
+			body:        protoBody,
+			wantStatus:  http.StatusOK,
+			wantMetric:  metric.Metrics{ID: "temperature", MType: "gauge", Value: &value},
+		},
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        []byte(jsonBody),
+			wantStatus:  http.StatusOK,
+			wantMetric: metric.Metrics{ID: "requests", MType: "counter", Delta: &delta,
+				Labels: map[string]string{"service.name": "shop"}},
+		},
+		{
+			name:        "invalid_protobuf",
+			contentType: "application/x-protobuf",
+			body:        []byte{0x0a, 0x05, 0x01},
+			wantStatus:  http.StatusBadRequest,
+		},
+		{
+			name:        "unsupported_content_type",
+			contentType: "text/plain",
+			body:        []byte(jsonBody),
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stored = nil
+			req, err := http.NewRequest("POST", "/v1/metrics", bytes.NewReader(test.body))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", test.contentType)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, test.wantStatus, w.Code)
+			if test.wantStatus != http.StatusOK {
+				require.Empty(t, stored)
+				return
+			}
+			require.Equal(t, []metric.Metrics{test.wantMetric}, stored)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointPostQuery = "error in POST /query"
+)
+
+//go:generate moq -out queryProvider_moq_test.go . QueryProvider
+type QueryProvider interface {
+	Query(context.Context, storage.Query) ([]storage.QueryResult, error)
+}
+
+// Тип queryRequest - тело запроса POST /query
+type queryRequest struct {
+	MType       string              `json:"type"`
+	Name        string              `json:"name"`
+	Regexp      bool                `json:"regexp,omitempty"`
+	Match       []string            `json:"match,omitempty"`
+	Aggregation storage.Aggregation `json:"aggregation"`
+	K           int                 `json:"k,omitempty"`
+}
+
+// Функция PostQueryHandler вычисляет агрегацию (sum, avg, min, max,
+// count или topk) по сериям gauge или counter. Имя задается шаблоном
+// glob или регулярным выражением, если regexp равен true, метки -
+// условиями match вида name=value, name!=value, name=~regexp или
+// name!~regexp.
+func PostQueryHandler(
+	logger *zap.SugaredLogger,
+	p QueryProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/query post metrics query")
+		var req queryRequest
+		if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+			logger.Errorf("%s: %v", errPointPostQuery, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		matchers, err := metric.ParseLabelMatchers(req.Match)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointPostQuery, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		q := storage.Query{
+			MType:       req.MType,
+			Name:        req.Name,
+			Regexp:      req.Regexp,
+			Matchers:    matchers,
+			Aggregation: req.Aggregation,
+			K:           req.K,
+		}
+		if err = q.Validate(); err != nil {
+			logger.Errorf("%s: %v", errPointPostQuery, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		var res []storage.QueryResult
+		err = retry.Retry(logger, 3, func() error {
+			res, err = p.Query(c, q)
+			return err
+		})
+		if err != nil {
+			logger.Errorf("%s: %v", errPointPostQuery, err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		logger.Infof("metrics query success with type: %s, name: %s, aggregation: %s, results: %d",
+			q.MType, q.Name, q.Aggregation, len(res))
+		c.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPostQuery(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	var got storage.Query
+	provider := &QueryProviderMock{
+		QueryFunc: func(_ context.Context, q storage.Query) ([]storage.QueryResult, error) {
+			got = q
+			return []storage.QueryResult{{Value: 3}}, nil
+		},
+	}
+	engine.POST("/query", PostQueryHandler(l, provider))
+
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{
+			name: "sum_glob",
+			body: `{"type": "gauge", "name": "Heap*", "match": ["host=~web.*"], "aggregation": "sum"}`,
+			code: http.StatusOK,
+		},
+		{
+			name: "topk_regexp",
+			body: `{"type": "counter", "name": "Poll(Count|Total)", "regexp": true, "aggregation": "topk", "k": 2}`,
+			code: http.StatusOK,
+		},
+		{name: "invalid_json", body: `{"type":`, code: http.StatusBadRequest},
+		{name: "invalid_matcher", body: `{"type": "gauge", "name": "a", "match": ["host"], "aggregation": "sum"}`, code: http.StatusBadRequest},
+		{name: "histogram", body: `{"type": "histogram", "name": "a", "aggregation": "sum"}`, code: http.StatusBadRequest},
+		{name: "unknown_aggregation", body: `{"type": "gauge", "name": "a", "aggregation": "median"}`, code: http.StatusBadRequest},
+		{name: "topk_without_k", body: `{"type": "gauge", "name": "a", "aggregation": "topk"}`, code: http.StatusBadRequest},
+		{name: "invalid_regexp", body: `{"type": "gauge", "name": "(", "regexp": true, "aggregation": "max"}`, code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", "/query", bytes.NewBufferString(tt.body))
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+			if tt.code != http.StatusOK {
+				return
+			}
+			var res []storage.QueryResult
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			require.Equal(t, []storage.QueryResult{{Value: 3}}, res)
+		})
+	}
+	require.Equal(t, storage.AggregationTopK, got.Aggregation)
+	require.True(t, got.Regexp)
+	require.Equal(t, 2, got.K)
+}
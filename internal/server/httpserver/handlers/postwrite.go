@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointPostWrite = "error in POST /write"
+)
+
+var (
+	ErrInvalidLineProtocol = errors.New("invalid line protocol")
+	ErrInvalidPrecision    = errors.New("invalid timestamp precision")
+	ErrNoNumericFields     = errors.New("line has no numeric fields")
+)
+
+// Допустимые значения параметра precision и соответствующие им единицы
+var linePrecisions = map[string]time.Duration{
+	"":   time.Nanosecond,
+	"n":  time.Nanosecond,
+	"ns": time.Nanosecond,
+	"u":  time.Microsecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+}
+
+// Тип lineError описывает ошибку разбора строки, line - номер строки с 1
+type lineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// Функция PostWriteHandler принимает метрики в формате InfluxDB line
+// protocol. Метрика получает имя measurement_field, теги становятся
+// метками. Целочисленные поля сохраняются как counter, вещественные - как
+// gauge, строковые и логические поля пропускаются. Корректные строки
+// сохраняются, даже если в запросе есть ошибочные: в этом случае
+// возвращается 400 с перечнем ошибок по строкам.
+func PostWriteHandler(
+	logger *zap.SugaredLogger,
+	p BatchMetricProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logger.Info("/write: recieving line protocol")
+		unit, ok := linePrecisions[c.Query("precision")]
+		if !ok {
+			logger.Errorf("%s: %v: %s", errPointPostWrite, ErrInvalidPrecision, c.Query("precision"))
+			c.JSON(http.StatusBadRequest, gin.H{"error": ErrInvalidPrecision.Error()})
+			return
+		}
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			logger.Errorf("%s: %v", errPointPostWrite, err)
+			c.Status(http.StatusBadRequest)
+			return
+		}
+
+		metrics, lineErrs := parseLineProtocol(body, unit, time.Now())
+		if len(metrics) > 0 {
+			if err = retry.Retry(logger, 3, func() error {
+				return p.SetMetrics(c, metrics)
+			}); err != nil {
+				logger.Errorf("%s: %v", errPointPostWrite, err)
+				if errors.Is(err, storage.ErrSeriesLimitExceeded) {
+					c.Status(http.StatusTooManyRequests)
+					return
+				}
+				if errors.Is(err, metric.ErrInvalidLabel) ||
+					errors.Is(err, storage.ErrIDIsEmpty) {
+					c.Status(http.StatusBadRequest)
+					return
+				}
+				c.Status(http.StatusInternalServerError)
+				return
+			}
+		}
+		if len(lineErrs) > 0 {
+			logger.Errorf("%s: %d lines were rejected", errPointPostWrite, len(lineErrs))
+			c.JSON(http.StatusBadRequest, gin.H{
+				"written": len(metrics),
+				"errors":  lineErrs,
+			})
+			return
+		}
+		logger.Infof("line protocol was saved: %d metrics", len(metrics))
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Функция parseLineProtocol разбирает строки вида
+// measurement[,tag=value...] field=value[,field=value...] [timestamp].
+// Пустые строки и комментарии пропускаются. Время без timestamp - now.
+func parseLineProtocol(body []byte, unit time.Duration, now time.Time) ([]metric.Metrics, []lineError) {
+	var metrics []metric.Metrics
+	var lineErrs []lineError
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parsed, err := parseLine(line, unit, now)
+		if err != nil {
+			lineErrs = append(lineErrs, lineError{Line: n, Error: err.Error()})
+			continue
+		}
+		metrics = append(metrics, parsed...)
+	}
+	if err := scanner.Err(); err != nil {
+		lineErrs = append(lineErrs, lineError{Line: n + 1, Error: err.Error()})
+	}
+	return metrics, lineErrs
+}
+
+// Функция parseLine разбирает одну строку line protocol
+func parseLine(line string, unit time.Duration, now time.Time) ([]metric.Metrics, error) {
+	sections := splitUnescaped(line, ' ', true)
+	if len(sections) < 2 || len(sections) > 3 {
+		return nil, ErrInvalidLineProtocol
+	}
+
+	keys := splitUnescaped(sections[0], ',', false)
+	measurement := unescapeLine(keys[0])
+	if measurement == "" {
+		return nil, ErrInvalidLineProtocol
+	}
+	var labels map[string]string
+	for _, tag := range keys[1:] {
+		name, value, ok := cutUnescaped(tag, '=')
+		if !ok || name == "" || value == "" {
+			return nil, ErrInvalidLineProtocol
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[unescapeLine(name)] = unescapeLine(value)
+	}
+	if err := metric.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+
+	ts := now
+	if len(sections) == 3 {
+		v, err := strconv.ParseInt(sections[2], 10, 64)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidLineProtocol, err)
+		}
+		ts = time.Unix(0, 0).Add(time.Duration(v) * unit)
+	}
+	ts = ts.UTC()
+
+	var metrics []metric.Metrics
+	for _, field := range splitUnescaped(sections[1], ',', true) {
+		name, raw, ok := cutUnescaped(field, '=')
+		if !ok || name == "" || raw == "" {
+			return nil, ErrInvalidLineProtocol
+		}
+		m := metric.Metrics{
+			ID:        measurement + "_" + unescapeLine(name),
+			Labels:    labels,
+			Timestamp: &ts,
+		}
+		switch last := raw[len(raw)-1]; {
+		case raw[0] == '"':
+			// Строковые поля не имеют числового представления
+			continue
+		case isLineBool(raw):
+			continue
+		case last == 'i' || last == 'u':
+			delta, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+			if err != nil || (last == 'u' && delta < 0) {
+				return nil, errors.Join(ErrInvalidLineProtocol, err)
+			}
+			m.MType = metric.TypeCounter.String()
+			m.Delta = &delta
+		default:
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, errors.Join(ErrInvalidLineProtocol, err)
+			}
+			m.MType = metric.TypeGauge.String()
+			m.Value = &value
+		}
+		metrics = append(metrics, m)
+	}
+	if len(metrics) == 0 {
+		return nil, ErrNoNumericFields
+	}
+	return metrics, nil
+}
+
+// Функция splitUnescaped делит строку по неэкранированному разделителю.
+// При quoted разделители внутри двойных кавычек игнорируются.
+func splitUnescaped(s string, sep byte, quoted bool) []string {
+	var parts []string
+	inQuotes := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case quoted && s[i] == '"':
+			inQuotes = !inQuotes
+		case s[i] == sep && !inQuotes:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// Функция cutUnescaped делит строку по первому неэкранированному разделителю
+func cutUnescaped(s string, sep byte) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// Функция unescapeLine удаляет экранирование запятых, пробелов и знаков
+// равенства в именах и значениях тегов
+func unescapeLine(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	return strings.NewReplacer(`\,`, ",", `\ `, " ", `\=`, "=", `\\`, `\`).Replace(s)
+}
+
+// Функция isLineBool проверяет, является ли значение поля логическим
+func isLineBool(s string) bool {
+	switch s {
+	case "t", "T", "true", "True", "TRUE", "f", "F", "false", "False", "FALSE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestPostWriteHandler(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+
+	gin.DisableConsoleColor()
+	gin.SetMode(gin.ReleaseMode)
+	engine := gin.New()
+	engine.RedirectFixedPath = true
+
+	var stored []metric.Metrics
+	provider := &BatchMetricProviderMock{
+		SetMetricsFunc: func(contextMoqParam context.Context, ms []metric.Metrics) error {
+			stored = ms
+			return nil
+		},
+	}
+	engine.POST("/write", PostWriteHandler(l, provider))
+
+	type response struct {
+		Written int         `json:"written"`
+		Errors  []lineError `json:"errors"`
+	}
+
+	type testCase struct {
+		name       string
+		query      string
+		body       string
+		wantStatus int
+		wantIDs    []string
+		wantErrors []int
+	}
+
+	tests := []testCase{
+		{
+			name:       "success",
+			body:       "cpu,host=web1 usage_idle=98.5,requests=12i 1700000000000000000\n\n# comment\nmem free=1024u",
+			wantStatus: http.StatusNoContent,
+			wantIDs:    []string{"cpu_usage_idle", "cpu_requests", "mem_free"},
+		},
+		{
+			name:       "partial",
+			body:       "cpu usage=1\ncpu\ncpu usage=abc\ncpu state=\"ok\"",
+			wantStatus: http.StatusBadRequest,
+			wantIDs:    []string{"cpu_usage"},
+			wantErrors: []int{2, 3, 4},
+		},
+		{
+			name:       "bad_precision",
+			query:      "?precision=h",
+			body:       "cpu usage=1",
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stored = nil
+			req, err := http.NewRequest("POST", "/write"+test.query, bytes.NewBufferString(test.body))
+			require.NoError(t, err)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			require.Equal(t, test.wantStatus, w.Code)
+			ids := make([]string, 0, len(stored))
+			for _, m := range stored {
+				ids = append(ids, m.ID)
+			}
+			require.ElementsMatch(t, test.wantIDs, ids)
+			if len(test.wantErrors) > 0 {
+				var resp response
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				lines := make([]int, 0, len(resp.Errors))
+				for _, e := range resp.Errors {
+					lines = append(lines, e.Line)
+				}
+				require.Equal(t, test.wantErrors, lines)
+				require.Equal(t, len(test.wantIDs), resp.Written)
+			}
+		})
+	}
+}
+
+func TestParseLineProtocol(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	body := `weather,city=New\ York,station=a\,b temp=21.5,rain=3i,note="a b, c=d" 1700000000` + "\n" +
+		`disk\ io,dev=sda bytes=10i`
+	metrics, errs := parseLineProtocol([]byte(body), time.Second, now)
+	require.Empty(t, errs)
+	require.Len(t, metrics, 3)
+
+	require.Equal(t, "weather_temp", metrics[0].ID)
+	require.Equal(t, metric.TypeGauge.String(), metrics[0].MType)
+	require.Equal(t, 21.5, *metrics[0].Value)
+	require.Equal(t, map[string]string{"city": "New York", "station": "a,b"}, metrics[0].Labels)
+	require.Equal(t, time.Unix(1700000000, 0).UTC(), *metrics[0].Timestamp)
+
+	require.Equal(t, "weather_rain", metrics[1].ID)
+	require.Equal(t, metric.TypeCounter.String(), metrics[1].MType)
+	require.Equal(t, int64(3), *metrics[1].Delta)
+
+	require.Equal(t, "disk io_bytes", metrics[2].ID)
+	require.Equal(t, now, *metrics[2].Timestamp)
+}
@@ -0,0 +1,77 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	store "github.com/Eqke/metric-collector/internal/storage"
+)
+
+// Ensure, that PrometheusMetricsProviderMock does implement PrometheusMetricsProvider.
+// If this is not the case, regenerate this file with moq.
+var _ PrometheusMetricsProvider = &PrometheusMetricsProviderMock{}
+
+// PrometheusMetricsProviderMock is a mock implementation of PrometheusMetricsProvider.
+//
+//	func TestSomethingThatUsesPrometheusMetricsProvider(t *testing.T) {
+//
+//		// make and configure a mocked PrometheusMetricsProvider
+//		mockedPrometheusMetricsProvider := &PrometheusMetricsProviderMock{
+//			GetMetricsFunc: func(contextMoqParam context.Context) (map[string][]store.Metric, error) {
+//				panic("mock out the GetMetrics method")
+//			},
+//		}
+//
+//		// use mockedPrometheusMetricsProvider in code that requires PrometheusMetricsProvider
+//		// and then make assertions.
+//
+//	}
+type PrometheusMetricsProviderMock struct {
+	// GetMetricsFunc mocks the GetMetrics method.
+	GetMetricsFunc func(contextMoqParam context.Context) (map[string][]store.Metric, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetMetrics holds details about calls to the GetMetrics method.
+		GetMetrics []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+		}
+	}
+	lockGetMetrics sync.RWMutex
+}
+
+// GetMetrics calls GetMetricsFunc.
+func (mock *PrometheusMetricsProviderMock) GetMetrics(contextMoqParam context.Context) (map[string][]store.Metric, error) {
+	if mock.GetMetricsFunc == nil {
+		panic("PrometheusMetricsProviderMock.GetMetricsFunc: method is nil but PrometheusMetricsProvider.GetMetrics was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+	}{
+		ContextMoqParam: contextMoqParam,
+	}
+	mock.lockGetMetrics.Lock()
+	mock.calls.GetMetrics = append(mock.calls.GetMetrics, callInfo)
+	mock.lockGetMetrics.Unlock()
+	return mock.GetMetricsFunc(contextMoqParam)
+}
+
+// GetMetricsCalls gets all the calls that were made to GetMetrics.
+// Check the length with:
+//
+//	len(mockedPrometheusMetricsProvider.GetMetricsCalls())
+func (mock *PrometheusMetricsProviderMock) GetMetricsCalls() []struct {
+	ContextMoqParam context.Context
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+	}
+	mock.lockGetMetrics.RLock()
+	calls = mock.calls.GetMetrics
+	mock.lockGetMetrics.RUnlock()
+	return calls
+}
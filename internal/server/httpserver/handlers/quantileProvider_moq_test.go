@@ -0,0 +1,83 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Ensure, that QuantileProviderMock does implement QuantileProvider.
+// If this is not the case, regenerate this file with moq.
+var _ QuantileProvider = &QuantileProviderMock{}
+
+// QuantileProviderMock is a mock implementation of QuantileProvider.
+//
+//	func TestSomethingThatUsesQuantileProvider(t *testing.T) {
+//
+//		// make and configure a mocked QuantileProvider
+//		mockedQuantileProvider := &QuantileProviderMock{
+//			GetMetricFunc: func(contextMoqParam context.Context, metrics metric.Metrics) (metric.Metrics, error) {
+//				panic("mock out the GetMetric method")
+//			},
+//		}
+//
+//		// use mockedQuantileProvider in code that requires QuantileProvider
+//		// and then make assertions.
+//
+//	}
+type QuantileProviderMock struct {
+	// GetMetricFunc mocks the GetMetric method.
+	GetMetricFunc func(contextMoqParam context.Context, metrics metric.Metrics) (metric.Metrics, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetMetric holds details about calls to the GetMetric method.
+		GetMetric []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+		}
+	}
+	lockGetMetric sync.RWMutex
+}
+
+// GetMetric calls GetMetricFunc.
+func (mock *QuantileProviderMock) GetMetric(contextMoqParam context.Context, metrics metric.Metrics) (metric.Metrics, error) {
+	if mock.GetMetricFunc == nil {
+		panic("QuantileProviderMock.GetMetricFunc: method is nil but QuantileProvider.GetMetric was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+	}
+	mock.lockGetMetric.Lock()
+	mock.calls.GetMetric = append(mock.calls.GetMetric, callInfo)
+	mock.lockGetMetric.Unlock()
+	return mock.GetMetricFunc(contextMoqParam, metrics)
+}
+
+// GetMetricCalls gets all the calls that were made to GetMetric.
+// Check the length with:
+//
+//	len(mockedQuantileProvider.GetMetricCalls())
+func (mock *QuantileProviderMock) GetMetricCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+	}
+	mock.lockGetMetric.RLock()
+	calls = mock.calls.GetMetric
+	mock.lockGetMetric.RUnlock()
+	return calls
+}
@@ -0,0 +1,83 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+)
+
+// Ensure, that QueryProviderMock does implement QueryProvider.
+// If this is not the case, regenerate this file with moq.
+var _ QueryProvider = &QueryProviderMock{}
+
+// QueryProviderMock is a mock implementation of QueryProvider.
+//
+//	func TestSomethingThatUsesQueryProvider(t *testing.T) {
+//
+//		// make and configure a mocked QueryProvider
+//		mockedQueryProvider := &QueryProviderMock{
+//			QueryFunc: func(contextMoqParam context.Context, query storage.Query) ([]storage.QueryResult, error) {
+//				panic("mock out the Query method")
+//			},
+//		}
+//
+//		// use mockedQueryProvider in code that requires QueryProvider
+//		// and then make assertions.
+//
+//	}
+type QueryProviderMock struct {
+	// QueryFunc mocks the Query method.
+	QueryFunc func(contextMoqParam context.Context, query storage.Query) ([]storage.QueryResult, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Query holds details about calls to the Query method.
+		Query []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Query is the query argument value.
+			Query storage.Query
+		}
+	}
+	lockQuery sync.RWMutex
+}
+
+// Query calls QueryFunc.
+func (mock *QueryProviderMock) Query(contextMoqParam context.Context, query storage.Query) ([]storage.QueryResult, error) {
+	if mock.QueryFunc == nil {
+		panic("QueryProviderMock.QueryFunc: method is nil but QueryProvider.Query was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Query           storage.Query
+	}{
+		ContextMoqParam: contextMoqParam,
+		Query:           query,
+	}
+	mock.lockQuery.Lock()
+	mock.calls.Query = append(mock.calls.Query, callInfo)
+	mock.lockQuery.Unlock()
+	return mock.QueryFunc(contextMoqParam, query)
+}
+
+// QueryCalls gets all the calls that were made to Query.
+// Check the length with:
+//
+//	len(mockedQueryProvider.QueryCalls())
+func (mock *QueryProviderMock) QueryCalls() []struct {
+	ContextMoqParam context.Context
+	Query           storage.Query
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Query           storage.Query
+	}
+	mock.lockQuery.RLock()
+	calls = mock.calls.Query
+	mock.lockQuery.RUnlock()
+	return calls
+}
@@ -0,0 +1,89 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Ensure, that SeriesProviderMock does implement SeriesProvider.
+// If this is not the case, regenerate this file with moq.
+var _ SeriesProvider = &SeriesProviderMock{}
+
+// SeriesProviderMock is a mock implementation of SeriesProvider.
+//
+//	func TestSomethingThatUsesSeriesProvider(t *testing.T) {
+//
+//		// make and configure a mocked SeriesProvider
+//		mockedSeriesProvider := &SeriesProviderMock{
+//			FindMetricsFunc: func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error) {
+//				panic("mock out the FindMetrics method")
+//			},
+//		}
+//
+//		// use mockedSeriesProvider in code that requires SeriesProvider
+//		// and then make assertions.
+//
+//	}
+type SeriesProviderMock struct {
+	// FindMetricsFunc mocks the FindMetrics method.
+	FindMetricsFunc func(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// FindMetrics holds details about calls to the FindMetrics method.
+		FindMetrics []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+			// LabelMatchers is the labelMatchers argument value.
+			LabelMatchers []*metric.LabelMatcher
+		}
+	}
+	lockFindMetrics sync.RWMutex
+}
+
+// FindMetrics calls FindMetricsFunc.
+func (mock *SeriesProviderMock) FindMetrics(contextMoqParam context.Context, metrics metric.Metrics, labelMatchers []*metric.LabelMatcher) ([]metric.Metrics, error) {
+	if mock.FindMetricsFunc == nil {
+		panic("SeriesProviderMock.FindMetricsFunc: method is nil but SeriesProvider.FindMetrics was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+		LabelMatchers:   labelMatchers,
+	}
+	mock.lockFindMetrics.Lock()
+	mock.calls.FindMetrics = append(mock.calls.FindMetrics, callInfo)
+	mock.lockFindMetrics.Unlock()
+	return mock.FindMetricsFunc(contextMoqParam, metrics, labelMatchers)
+}
+
+// FindMetricsCalls gets all the calls that were made to FindMetrics.
+// Check the length with:
+//
+//	len(mockedSeriesProvider.FindMetricsCalls())
+func (mock *SeriesProviderMock) FindMetricsCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+	LabelMatchers   []*metric.LabelMatcher
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+		LabelMatchers   []*metric.LabelMatcher
+	}
+	mock.lockFindMetrics.RLock()
+	calls = mock.calls.FindMetrics
+	mock.lockFindMetrics.RUnlock()
+	return calls
+}
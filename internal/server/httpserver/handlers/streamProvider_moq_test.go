@@ -0,0 +1,120 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package handlers
+
+import (
+	"sync"
+
+	"github.com/Eqke/metric-collector/internal/hub"
+)
+
+// Ensure, that StreamProviderMock does implement StreamProvider.
+// If this is not the case, regenerate this file with moq.
+var _ StreamProvider = &StreamProviderMock{}
+
+// StreamProviderMock is a mock implementation of StreamProvider.
+//
+//	func TestSomethingThatUsesStreamProvider(t *testing.T) {
+//
+//		// make and configure a mocked StreamProvider
+//		mockedStreamProvider := &StreamProviderMock{
+//			SubscribeFunc: func(filter hub.Filter) *hub.Subscription {
+//				panic("mock out the Subscribe method")
+//			},
+//			UnsubscribeFunc: func(subscription *hub.Subscription) {
+//				panic("mock out the Unsubscribe method")
+//			},
+//		}
+//
+//		// use mockedStreamProvider in code that requires StreamProvider
+//		// and then make assertions.
+//
+//	}
+type StreamProviderMock struct {
+	// SubscribeFunc mocks the Subscribe method.
+	SubscribeFunc func(filter hub.Filter) *hub.Subscription
+
+	// UnsubscribeFunc mocks the Unsubscribe method.
+	UnsubscribeFunc func(subscription *hub.Subscription)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Subscribe holds details about calls to the Subscribe method.
+		Subscribe []struct {
+			// Filter is the filter argument value.
+			Filter hub.Filter
+		}
+		// Unsubscribe holds details about calls to the Unsubscribe method.
+		Unsubscribe []struct {
+			// Subscription is the subscription argument value.
+			Subscription *hub.Subscription
+		}
+	}
+	lockSubscribe   sync.RWMutex
+	lockUnsubscribe sync.RWMutex
+}
+
+// Subscribe calls SubscribeFunc.
+func (mock *StreamProviderMock) Subscribe(filter hub.Filter) *hub.Subscription {
+	if mock.SubscribeFunc == nil {
+		panic("StreamProviderMock.SubscribeFunc: method is nil but StreamProvider.Subscribe was just called")
+	}
+	callInfo := struct {
+		Filter hub.Filter
+	}{
+		Filter: filter,
+	}
+	mock.lockSubscribe.Lock()
+	mock.calls.Subscribe = append(mock.calls.Subscribe, callInfo)
+	mock.lockSubscribe.Unlock()
+	return mock.SubscribeFunc(filter)
+}
+
+// SubscribeCalls gets all the calls that were made to Subscribe.
+// Check the length with:
+//
+//	len(mockedStreamProvider.SubscribeCalls())
+func (mock *StreamProviderMock) SubscribeCalls() []struct {
+	Filter hub.Filter
+} {
+	var calls []struct {
+		Filter hub.Filter
+	}
+	mock.lockSubscribe.RLock()
+	calls = mock.calls.Subscribe
+	mock.lockSubscribe.RUnlock()
+	return calls
+}
+
+// Unsubscribe calls UnsubscribeFunc.
+func (mock *StreamProviderMock) Unsubscribe(subscription *hub.Subscription) {
+	if mock.UnsubscribeFunc == nil {
+		panic("StreamProviderMock.UnsubscribeFunc: method is nil but StreamProvider.Unsubscribe was just called")
+	}
+	callInfo := struct {
+		Subscription *hub.Subscription
+	}{
+		Subscription: subscription,
+	}
+	mock.lockUnsubscribe.Lock()
+	mock.calls.Unsubscribe = append(mock.calls.Unsubscribe, callInfo)
+	mock.lockUnsubscribe.Unlock()
+	mock.UnsubscribeFunc(subscription)
+}
+
+// UnsubscribeCalls gets all the calls that were made to Unsubscribe.
+// Check the length with:
+//
+//	len(mockedStreamProvider.UnsubscribeCalls())
+func (mock *StreamProviderMock) UnsubscribeCalls() []struct {
+	Subscription *hub.Subscription
+} {
+	var calls []struct {
+		Subscription *hub.Subscription
+	}
+	mock.lockUnsubscribe.RLock()
+	calls = mock.calls.Unsubscribe
+	mock.lockUnsubscribe.RUnlock()
+	return calls
+}
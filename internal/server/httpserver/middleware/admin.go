@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointAdminToken = "error in admin token middleware: "
+)
+
+// Функция AdminToken пропускает запрос только с заголовком
+// Authorization: Bearer <token> или с токеном доступа области admin.
+// Если токен администратора не задан, административные методы доступны
+// только по токену доступа.
+func AdminToken(
+	logger *zap.SugaredLogger,
+	token string,
+	tokens auth.Tokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if tokens.IsAdmin(apiToken(c)) {
+			c.Next()
+			return
+		}
+		if token == "" {
+			logger.Errorf("%s%s", errPointAdminToken, "admin token is not configured")
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			logger.Errorf("%sinvalid token from %s", errPointAdminToken, c.ClientIP())
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointAuth = "error in auth middleware: "
+)
+
+// Функция Auth пропускает запрос только с токеном доступа к области scope
+// в заголовке X-API-Key или Authorization: Bearer <token>. Если токены
+// не заданы, проверка отключена.
+func Auth(
+	logger *zap.SugaredLogger,
+	tokens auth.Tokens,
+	scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := tokens.Authorize(apiToken(c), scope); err != nil {
+			logger.Errorf("%s%v, client: %s", errPointAuth, err, c.ClientIP())
+			if errors.Is(err, auth.ErrForbidden) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Функция apiToken возвращает токен доступа из заголовка X-API-Key или
+// Authorization: Bearer <token>
+func apiToken(c *gin.Context) string {
+	if token := c.GetHeader(auth.Header); token != "" {
+		return token
+	}
+	token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	return token
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"github.com/Eqke/metric-collector/internal/certs"
+	"github.com/gin-gonic/gin"
+)
+
+// Функция ClientCert сохраняет в контексте запроса идентификатор агента,
+// определенный по сертификату клиента TLS
+func ClientCert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if id := certs.AgentID(c.Request.TLS); id != "" {
+			c.Request = c.Request.WithContext(certs.WithAgent(c.Request.Context(), id))
+		}
+		c.Next()
+	}
+}
@@ -2,7 +2,7 @@ package middleware
 
 import (
 	"bytes"
-	"crypto/rsa"
+	"errors"
 	"github.com/Eqke/metric-collector/internal/encrypting"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -10,9 +10,12 @@ import (
 	"net/http"
 )
 
+// Функция Decrypt расшифровывает тело запроса ключом из keys, выбранным
+// по идентификатору ключа конверта. Данные без заголовка конверта
+// расшифровываются устаревшим поблочным RSA PKCS#1 v1.5.
 func Decrypt(
 	logger *zap.SugaredLogger,
-	cryptoKey *rsa.PrivateKey,
+	keys *encrypting.Keyring,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		body, err := io.ReadAll(c.Request.Body)
@@ -21,9 +24,15 @@ func Decrypt(
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
-		decryptedBody, err := encrypting.Decrypt(cryptoKey, body)
+		decryptedBody, err := keys.Open(body)
 		if err != nil {
 			logger.Errorw("Error decrypting body", "error", err)
+			if errors.Is(err, encrypting.ErrInvalidEnvelope) ||
+				errors.Is(err, encrypting.ErrUnsupportedVersion) ||
+				errors.Is(err, encrypting.ErrUnknownKey) {
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
 			c.AbortWithStatus(http.StatusInternalServerError)
 			return
 		}
@@ -18,6 +18,7 @@ var availableTypes = map[string]bool{
 	"text/html":        true,
 	"application/json": true,
 	"html/text":        true,
+	"text/plain":       true,
 }
 
 func Gzip(
@@ -30,7 +31,7 @@ func Gzip(
 		ct := c.GetHeader("Content-Type")
 		ac := c.GetHeader("Accept")
 		if (strings.Contains(ae, "gzip")) &&
-			(availableTypes[ct] || availableTypes[ac]) {
+			(availableTypes[ct] || acceptsAvailableType(ac)) {
 			w := c.Writer
 			gzipWriter := gzip.NewWriter(w)
 			defer gzipWriter.Close()
@@ -58,3 +59,16 @@ func Gzip(
 		c.Next()
 	}
 }
+
+// Функция acceptsAvailableType проверяет, содержит ли заголовок Accept
+// сжимаемый тип. Заголовок может перечислять несколько типов с параметрами,
+// например text/plain;version=0.0.4;q=0.5,*/*;q=0.1
+func acceptsAvailableType(accept string) bool {
+	for _, mediaType := range strings.Split(accept, ",") {
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+		if availableTypes[strings.TrimSpace(mediaType)] {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"bytes"
+	"github.com/Eqke/metric-collector/internal/replay"
 	"github.com/Eqke/metric-collector/internal/server/httpserver/writers"
 	"io"
 	"net/http"
@@ -15,11 +16,18 @@ const (
 	errPointHash = "error in hash middleware: "
 )
 
+// Функция Hash проверяет подпись запроса ключом hashKey. Подпись со
+// временем отправки и nonce покрывает метод, путь, параметры и тело
+// запроса, устаревшая подпись - только тело. Если задан guard,
+// подписанный запрос должен содержать время отправки и nonce:
+// запросы вне окна guard и повторы отклоняются. Nonce запроса,
+// завершившегося ошибкой сервера, освобождается для повторной отправки.
 func Hash(
 	logger *zap.SugaredLogger,
-	hashKey string) gin.HandlerFunc {
+	hashKey string,
+	guard *replay.Guard) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		hashHeader := c.GetHeader("HashSHA256")
+		hashHeader := c.GetHeader(h.HeaderSignature)
 		logger.Infof("%s: %s", "hash header", hashHeader)
 
 		if hashHeader != "" && hashKey != "" {
@@ -27,18 +35,46 @@ func Hash(
 			body, err := io.ReadAll(c.Request.Body)
 			if err != nil {
 				logger.Errorf("%s: %v", errPointHash, err)
-				c.Status(http.StatusInternalServerError)
+				c.AbortWithStatus(http.StatusInternalServerError)
 				return
 			}
 
 			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
 
+			timestamp, nonce := c.GetHeader(h.HeaderTimestamp), c.GetHeader(h.HeaderNonce)
+			if guard != nil && (timestamp == "" || nonce == "") {
+				logger.Errorf("%s: %v", errPointHash, replay.ErrMissing)
+				c.AbortWithStatus(http.StatusBadRequest)
+				return
+			}
 			sign := h.Sign(body, hashKey)
-			if sign != hashHeader {
+			if timestamp != "" || nonce != "" {
+				payload := h.RequestPayload(c.Request.Method, c.Request.URL.Path, c.Request.URL.Query(), body)
+				sign = h.SignRequest(payload, timestamp, nonce, hashKey)
+			}
+			if !h.Equal(sign, hashHeader) {
 				logger.Errorf("%s: %v", errPointHash, "hash not equal")
-				c.Status(http.StatusBadRequest)
+				c.AbortWithStatus(http.StatusBadRequest)
 				return
 			}
+			// Nonce запоминается только после проверки подписи, чтобы
+			// неподписанные запросы не заполняли кэш
+			if guard != nil {
+				ts, err := replay.ParseTimestamp(timestamp)
+				if err == nil {
+					err = guard.Check(ts, nonce)
+				}
+				if err != nil {
+					logger.Errorf("%s: %v", errPointHash, err)
+					c.AbortWithStatus(http.StatusBadRequest)
+					return
+				}
+				defer func() {
+					if c.Writer.Status() >= http.StatusInternalServerError {
+						guard.Release(nonce)
+					}
+				}()
+			}
 			logger.Infof("%s: %s", "hash checked successfully", hashHeader)
 			w := writers.NewHashWriter(c.Writer, logger, hashKey)
 			c.Writer = w
@@ -47,3 +83,19 @@ func Hash(
 		c.Next()
 	}
 }
+
+// Функция RequireSignature отклоняет запрос без подписи, если задан ключ
+// hashKey. Подпись проверяет Hash, поэтому без RequireSignature запрос
+// без заголовка HashSHA256 пропускается и может быть повторен.
+func RequireSignature(
+	logger *zap.SugaredLogger,
+	hashKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if hashKey != "" && c.GetHeader(h.HeaderSignature) == "" {
+			logger.Errorf("%sunsigned request from %s", errPointHash, c.ClientIP())
+			c.AbortWithStatus(http.StatusBadRequest)
+			return
+		}
+		c.Next()
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"time"
 
+	"github.com/Eqke/metric-collector/internal/certs"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
@@ -35,6 +36,7 @@ func Logger(
 		logger.Infoln(
 			"URL", c.Request.URL,
 			"METHOD", c.Request.Method,
+			"AGENT", certs.AgentFromContext(c.Request.Context()),
 			"STATUS", data.status,
 			"SIZE", data.size,
 			"DURATION", duration,
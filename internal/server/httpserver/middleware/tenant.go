@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointTenant = "error in tenant middleware: "
+)
+
+// Функция Tenant определяет арендатора запроса по заголовку X-Tenant-ID
+// и токену арендатора из заголовка X-Tenant-Token и сохраняет его в
+// контексте запроса
+func Tenant(
+	logger *zap.SugaredLogger,
+	tokens tenant.Tokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := tokens.Resolve(c.GetHeader(tenant.Header), c.GetHeader(tenant.TokenHeader))
+		if err != nil {
+			logger.Errorf("%s%v, client: %s", errPointTenant, err, c.ClientIP())
+			switch {
+			case errors.Is(err, tenant.ErrInvalidTenant):
+				c.AbortWithStatus(http.StatusBadRequest)
+			case errors.Is(err, tenant.ErrTokenMismatch):
+				c.AbortWithStatus(http.StatusForbidden)
+			case errors.Is(err, tenant.ErrTokenRequired), errors.Is(err, tenant.ErrInvalidToken):
+				c.AbortWithStatus(http.StatusUnauthorized)
+			default:
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+			return
+		}
+		c.Request = c.Request.WithContext(tenant.WithTenant(c.Request.Context(), id))
+		c.Next()
+	}
+}
@@ -2,7 +2,13 @@ package httpserver
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto/tls"
+	"github.com/Eqke/metric-collector/internal/alerting"
+	"github.com/Eqke/metric-collector/internal/audit"
+	"github.com/Eqke/metric-collector/internal/auth"
+	"github.com/Eqke/metric-collector/internal/encrypting"
+	"github.com/Eqke/metric-collector/internal/hub"
+	"github.com/Eqke/metric-collector/internal/replay"
 	"github.com/Eqke/metric-collector/internal/server/config"
 	"github.com/Eqke/metric-collector/internal/server/httpserver/handlers"
 	middleware2 "github.com/Eqke/metric-collector/internal/server/httpserver/middleware"
@@ -26,35 +32,82 @@ func New(
 	set *config.ServerConfig,
 	storage stor.Storage,
 	l *zap.SugaredLogger,
-	key *rsa.PrivateKey,
+	keys *encrypting.Keyring,
+	h *hub.Hub,
+	alerts *alerting.Manager,
+	guard *replay.Guard,
+	tlsConfig *tls.Config,
 ) *HTTPServer {
 	logger := l.Named("http-server")
 	gin.DisableConsoleColor()
 	gin.SetMode(gin.ReleaseMode)
 	rounter := gin.New()
 	rounter.RedirectFixedPath = true
+	// Арендатор передается обработчикам через контекст запроса
+	rounter.ContextWithFallback = true
+	// Токены проверяются при чтении конфигурации
+	tenants, _ := set.Tenants()
+	tokens, _ := set.AccessTokens()
 
 	logger.Infof("Server initing with %s storage", storage.Type())
 
 	//usage middleware
 	rounter.Use(
 		middleware2.Logger(logger),
+		middleware2.ClientCert(),
 		middleware2.SubnetTrust(logger, set.TrustedSubnet),
-		middleware2.Hash(logger, set.HashKey),
-		middleware2.Decrypt(logger, key),
-		middleware2.Gzip(logger),
+		middleware2.Tenant(logger, tenants),
+		middleware2.Hash(logger, set.HashKey, guard),
 	)
+	gzip := middleware2.Gzip(logger)
 
-	rounter.GET("/", handlers.GetRootMetricsHandler(logger, storage))
-	rounter.GET("/value/:type/:name/", handlers.GETMetricHandler(logger, storage))
-	rounter.GET("/ping/", handlers.Ping(logger, storage))
-	rounter.POST("/value/", handlers.GetMetricJSONHandler(logger, storage))
-	rounter.POST("/update/:type/:name/:value/", handlers.POSTMetricHandler(logger, storage))
-	rounter.POST("/update/", handlers.POSTMetricJSONHandler(logger, storage))
-	rounter.POST("/updates/", handlers.PostMetricUpdates(logger, storage))
+	rounter.GET("/ping/", gzip, handlers.Ping(logger, storage))
+
+	read := rounter.Group("/", middleware2.Auth(logger, tokens, auth.ScopeRead), gzip)
+	{
+		read.GET("/", handlers.GetRootMetricsHandler(logger, storage))
+		read.GET("/value/:type/:name/", handlers.GETMetricHandler(logger, storage))
+		read.GET("/history/:type/:name/", handlers.GetMetricRangeHandler(logger, storage))
+		read.GET("/rate/:name/", handlers.GetCounterRateHandler(logger, storage))
+		read.GET("/quantiles/:name/", handlers.GetQuantilesHandler(logger, storage))
+		read.GET("/series/:type/:name/", handlers.GetSeriesHandler(logger, storage))
+		read.GET("/view/:type/:name/", handlers.GetMetricPageHandler(logger, storage))
+		read.GET("/metrics", handlers.GetPrometheusMetricsHandler(logger, storage))
+		read.GET("/stream", handlers.GetStreamHandler(logger, h))
+		read.GET("/alerts", handlers.GetAlertsHandler(logger, alerts))
+		read.POST("/value/", handlers.GetMetricJSONHandler(logger, storage))
+		read.POST("/query", handlers.PostQueryHandler(logger, storage))
+	}
+
+	// Агент шифрует и подписывает тело запроса, поэтому оно
+	// расшифровывается и подпись обязательна только на маршрутах агента.
+	// Клиенты InfluxDB и OTLP отправляют открытые неподписанные данные.
+	update := rounter.Group("/",
+		middleware2.Auth(logger, tokens, auth.ScopeWrite),
+		middleware2.RequireSignature(logger, set.HashKey),
+		middleware2.Decrypt(logger, keys),
+		gzip,
+	)
+	{
+		update.POST("/update/:type/:name/:value/", handlers.POSTMetricHandler(logger, storage))
+		update.POST("/update/", handlers.POSTMetricJSONHandler(logger, storage))
+		update.POST("/updates/", handlers.PostMetricUpdates(logger, storage))
+	}
+
+	write := rounter.Group("/", middleware2.Auth(logger, tokens, auth.ScopeWrite), gzip)
+	{
+		write.POST("/write", handlers.PostWriteHandler(logger, storage))
+		write.POST("/v1/metrics", handlers.PostOTLPMetricsHandler(logger, storage))
+	}
+
+	rounter.DELETE("/value/:type/:name/",
+		middleware2.Auth(logger, tokens, auth.ScopeAdmin),
+		middleware2.AdminToken(logger, set.AdminToken, tokens),
+		gzip,
+		handlers.DeleteMetricHandler(logger, storage, audit.New(logger)))
 
 	//pproff tools api
-	profiler := rounter.Group("/debug/pprof")
+	profiler := rounter.Group("/debug/pprof", middleware2.Auth(logger, tokens, auth.ScopeAdmin), gzip)
 	{
 		profiler.GET("/", gin.WrapF(pprof.Index))
 		profiler.GET("/cmdline", gin.WrapF(pprof.Cmdline))
@@ -72,8 +125,9 @@ func New(
 
 	return &HTTPServer{
 		server: &http.Server{
-			Addr:    set.Host,
-			Handler: rounter,
+			Addr:      set.Host,
+			Handler:   rounter,
+			TLSConfig: tlsConfig,
 		},
 		engine:  rounter,
 		logger:  logger,
@@ -87,7 +141,14 @@ func (s *HTTPServer) Run(ctx context.Context, wg *sync.WaitGroup) {
 
 	go func() {
 
-		if err := s.server.ListenAndServe(); err != nil {
+		var err error
+		if s.server.TLSConfig != nil {
+			// Сертификат берется из TLSConfig.GetCertificate
+			err = s.server.ListenAndServeTLS("", "")
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil {
 			s.logger.Errorf("Server error: %v", err)
 		}
 		wg.Done()
@@ -0,0 +1,169 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/alerting"
+	"github.com/Eqke/metric-collector/internal/encrypting"
+	"github.com/Eqke/metric-collector/internal/hub"
+	"github.com/Eqke/metric-collector/internal/server/config"
+	"github.com/Eqke/metric-collector/internal/storage/localstorage"
+	"github.com/Eqke/metric-collector/internal/tenant"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/hash"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// Функция newTestServer возвращает сервер с локальным хранилищем и
+// ключом шифрования во временном каталоге
+func newTestServer(t *testing.T, set *config.ServerConfig) (*HTTPServer, *localstorage.LocalStorage) {
+	logger := zaptest.NewLogger(t).Sugar()
+	dir := t.TempDir()
+	_, err := encrypting.GenerateKey(dir, time.Now())
+	require.NoError(t, err)
+	keys, err := encrypting.NewKeyring(logger, dir, 60)
+	require.NoError(t, err)
+	storage := localstorage.New(logger)
+	alerts := alerting.New(logger, storage, nil, alerting.NewWebhookNotifier(""), 30)
+	return New(set, storage, logger, keys, hub.New(logger), alerts, nil, nil), storage
+}
+
+func TestHTTPServer_PlaintextIngestion(t *testing.T) {
+	s, storage := newTestServer(t, &config.ServerConfig{})
+
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        string
+		code        int
+		metric      metric.Metrics
+	}{
+		{
+			name: "influx_write",
+			url:  "/write",
+			body: "cpu,host=web1 usage=12.5",
+			code: http.StatusNoContent,
+			metric: metric.Metrics{ID: "cpu_usage", MType: metric.TypeGauge.String(),
+				Labels: map[string]string{"host": "web1"}},
+		},
+		{
+			name:        "otlp_json",
+			url:         "/v1/metrics",
+			contentType: "application/json",
+			body: `{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"temperature",
+				"gauge":{"dataPoints":[{"asDouble":21.5}]}}]}]}]}`,
+			code:   http.StatusOK,
+			metric: metric.Metrics{ID: "temperature", MType: metric.TypeGauge.String()},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, test.url, strings.NewReader(test.body))
+			if test.contentType != "" {
+				req.Header.Set("Content-Type", test.contentType)
+			}
+			w := httptest.NewRecorder()
+			s.engine.ServeHTTP(w, req)
+
+			require.Equal(t, test.code, w.Code, w.Body.String())
+			m, err := storage.GetMetric(context.Background(), test.metric)
+			require.NoError(t, err)
+			require.NotNil(t, m.Value)
+		})
+	}
+}
+
+func TestHTTPServer_DeleteTenantMetric(t *testing.T) {
+	s, storage := newTestServer(t, &config.ServerConfig{
+		AdminToken:   "admin-secret",
+		TenantTokens: "team-a:tenant-secret",
+	})
+	ctx := tenant.WithTenant(context.Background(), "team-a")
+	value := 1.5
+	m := metric.Metrics{ID: "cpu", MType: metric.TypeGauge.String(), Value: &value}
+	require.NoError(t, storage.SetMetric(ctx, m))
+
+	req := httptest.NewRequest(http.MethodDelete, "/value/gauge/cpu/", nil)
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	req.Header.Set(tenant.Header, "team-a")
+	req.Header.Set(tenant.TokenHeader, "tenant-secret")
+	w := httptest.NewRecorder()
+	s.engine.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	_, err := storage.GetMetric(ctx, m)
+	require.Error(t, err)
+}
+
+func TestHTTPServer_DeleteWithAdminScope(t *testing.T) {
+	s, storage := newTestServer(t, &config.ServerConfig{
+		AdminToken: "admin-secret",
+		AuthTokens: "ops:admin,agent:write",
+	})
+	value := 1.5
+	m := metric.Metrics{ID: "cpu", MType: metric.TypeGauge.String(), Value: &value}
+	require.NoError(t, storage.SetMetric(context.Background(), m))
+
+	for _, test := range []struct {
+		name string
+		auth string
+		code int
+	}{
+		{name: "write_scope", auth: "Bearer agent", code: http.StatusForbidden},
+		{name: "admin_scope", auth: "Bearer ops", code: http.StatusOK},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/value/gauge/cpu/", nil)
+			req.Header.Set("Authorization", test.auth)
+			w := httptest.NewRecorder()
+			s.engine.ServeHTTP(w, req)
+			require.Equal(t, test.code, w.Code, w.Body.String())
+		})
+	}
+	_, err := storage.GetMetric(context.Background(), m)
+	require.Error(t, err)
+}
+
+func TestHTTPServer_RequireSignature(t *testing.T) {
+	s, _ := newTestServer(t, &config.ServerConfig{HashKey: "secret"})
+
+	for _, test := range []struct {
+		name   string
+		target string
+		signed string
+		code   int
+	}{
+		{name: "unsigned", target: "/update/gauge/cpu/1.5/", code: http.StatusBadRequest},
+		{name: "signed", target: "/update/gauge/cpu/1.5/", signed: "/update/gauge/cpu/1.5", code: http.StatusOK},
+		{name: "signed_labels", target: "/update/gauge/cpu/1.5/?label=host%3Dweb1",
+			signed: "/update/gauge/cpu/1.5?label=host%3Dweb1", code: http.StatusOK},
+		// Подпись покрывает значение и метки из URL
+		{name: "changed_value", target: "/update/gauge/cpu/9/", signed: "/update/gauge/cpu/1.5", code: http.StatusBadRequest},
+		{name: "changed_label", target: "/update/gauge/cpu/1.5/?label=host%3Dweb2",
+			signed: "/update/gauge/cpu/1.5?label=host%3Dweb1", code: http.StatusBadRequest},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, test.target, nil)
+			if test.signed != "" {
+				u, err := url.Parse(test.signed)
+				require.NoError(t, err)
+				payload := hash.RequestPayload(http.MethodPost, u.Path, u.Query(), nil)
+				timestamp, nonce := hash.Timestamp(time.Now()), hash.NewNonce()
+				req.Header.Set(hash.HeaderTimestamp, timestamp)
+				req.Header.Set(hash.HeaderNonce, nonce)
+				req.Header.Set(hash.HeaderSignature, hash.SignRequest(payload, timestamp, nonce, "secret"))
+			}
+			w := httptest.NewRecorder()
+			s.engine.ServeHTTP(w, req)
+			require.Equal(t, test.code, w.Code, w.Body.String())
+		})
+	}
+}
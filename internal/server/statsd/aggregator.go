@@ -0,0 +1,224 @@
+package statsd
+
+import (
+	"math"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Границы корзин гистограммы таймеров в миллисекундах
+var TimerBounds = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Число сбросов без обновлений, после которого агрегатор забывает gauge
+// и остаток counter. Значение gauge остается в хранилище и используется
+// как исходное при следующем относительном изменении.
+const idleFlushes = 10
+
+// Тип series - идентичность метрики StatsD
+type series struct {
+	name   string
+	labels map[string]string
+}
+
+// Тип gaugeState хранит значение gauge между сбросами. Пока значение
+// не известно (known == false), value содержит сумму +/- изменений.
+// idle - число сбросов без обновлений.
+type gaugeState struct {
+	series
+	value float64
+	known bool
+	dirty bool
+	idle  int
+}
+
+// Тип aggregator накапливает значения между сбросами в хранилище.
+// Ключом карт является metric.SeriesKey.
+type aggregator struct {
+	counters map[string]*counterState
+	gauges   map[string]*gaugeState
+	timers   map[string]*timerState
+}
+
+// Тип counterState хранит сумму counter с учетом частоты выборки.
+// Дробный остаток суммы переносится в следующий сброс.
+type counterState struct {
+	series
+	sum  float64
+	idle int
+}
+
+type timerState struct {
+	series
+	hist *metric.Histogram
+}
+
+// Функция newAggregator возвращает пустой агрегатор
+func newAggregator() *aggregator {
+	return &aggregator{
+		counters: make(map[string]*counterState),
+		gauges:   make(map[string]*gaugeState),
+		timers:   make(map[string]*timerState),
+	}
+}
+
+// Метод add учитывает строку StatsD
+func (a *aggregator) add(l Line) {
+	key := metric.SeriesKey(l.Name, l.Labels)
+	s := series{name: l.Name, labels: l.Labels}
+	switch l.Type {
+	case TypeCounter:
+		c, ok := a.counters[key]
+		if !ok {
+			c = &counterState{series: s}
+			a.counters[key] = c
+		}
+		c.sum += l.Value / l.Rate
+		c.idle = 0
+	case TypeGauge:
+		g, ok := a.gauges[key]
+		if !ok {
+			g = &gaugeState{series: s}
+			a.gauges[key] = g
+		}
+		if l.Relative {
+			g.value += l.Value
+		} else {
+			g.value = l.Value
+			g.known = true
+		}
+		g.dirty = true
+		g.idle = 0
+	case TypeTimer:
+		t, ok := a.timers[key]
+		if !ok {
+			t = &timerState{series: s, hist: metric.NewHistogram(TimerBounds)}
+			a.timers[key] = t
+		}
+		t.hist.ObserveN(l.Value, uint64(math.Round(1/l.Rate)))
+	}
+}
+
+// Метод takeCounters возвращает целую часть накопленных сумм counter,
+// дробный остаток остается в агрегаторе до следующего сброса
+func (a *aggregator) takeCounters() []metric.Metrics {
+	res := make([]metric.Metrics, 0, len(a.counters))
+	for key, c := range a.counters {
+		delta := int64(math.Trunc(c.sum))
+		c.sum -= float64(delta)
+		if c.sum == 0 || c.idle >= idleFlushes {
+			delete(a.counters, key)
+		} else {
+			c.idle++
+		}
+		if delta == 0 {
+			continue
+		}
+		res = append(res, metric.Metrics{
+			ID:     c.name,
+			MType:  metric.TypeCounter.String(),
+			Delta:  &delta,
+			Labels: c.labels,
+		})
+	}
+	return res
+}
+
+// Метод takeTimers возвращает накопленные гистограммы и очищает их
+func (a *aggregator) takeTimers() []metric.Metrics {
+	res := make([]metric.Metrics, 0, len(a.timers))
+	for _, t := range a.timers {
+		res = append(res, metric.Metrics{
+			ID:        t.name,
+			MType:     metric.TypeHistogram.String(),
+			Histogram: t.hist,
+			Labels:    t.labels,
+		})
+	}
+	a.timers = make(map[string]*timerState)
+	return res
+}
+
+// Метод takeGauges возвращает измененные gauge с известным значением
+// и ключи тех, для которых известны только изменения. Значения gauge
+// сохраняются между сбросами, пока не пройдет idleFlushes сбросов без
+// обновлений.
+func (a *aggregator) takeGauges() ([]metric.Metrics, []string) {
+	res := make([]metric.Metrics, 0, len(a.gauges))
+	var unknown []string
+	for key, g := range a.gauges {
+		if !g.dirty {
+			g.idle++
+			if g.idle >= idleFlushes {
+				delete(a.gauges, key)
+			}
+			continue
+		}
+		if !g.known {
+			unknown = append(unknown, key)
+			continue
+		}
+		value := g.value
+		res = append(res, metric.Metrics{
+			ID:     g.name,
+			MType:  metric.TypeGauge.String(),
+			Value:  &value,
+			Labels: g.labels,
+		})
+		g.dirty = false
+	}
+	return res, unknown
+}
+
+// Метод resolveGauge задает исходное значение gauge, для которого были
+// известны только изменения, и возвращает его текущее значение
+func (a *aggregator) resolveGauge(key string, base float64) metric.Metrics {
+	g := a.gauges[key]
+	if !g.known {
+		g.value += base
+		g.known = true
+	}
+	g.dirty = false
+	value := g.value
+	return metric.Metrics{
+		ID:     g.name,
+		MType:  metric.TypeGauge.String(),
+		Value:  &value,
+		Labels: g.labels,
+	}
+}
+
+// Метод restore возвращает в агрегатор метрики, которые не удалось
+// сохранить: изменения counter и гистограммы складываются с накопленными
+// после сброса, gauge снова отмечаются измененными
+func (a *aggregator) restore(metrics []metric.Metrics) {
+	for _, m := range metrics {
+		key := metric.SeriesKey(m.ID, m.Labels)
+		s := series{name: m.ID, labels: m.Labels}
+		switch m.MType {
+		case metric.TypeCounter.String():
+			c, ok := a.counters[key]
+			if !ok {
+				c = &counterState{series: s}
+				a.counters[key] = c
+			}
+			c.sum += float64(*m.Delta)
+			c.idle = 0
+		case metric.TypeGauge.String():
+			g, ok := a.gauges[key]
+			if !ok {
+				g = &gaugeState{series: s, value: *m.Value, known: true}
+				a.gauges[key] = g
+			}
+			g.dirty = true
+			g.idle = 0
+		case metric.TypeHistogram.String():
+			t, ok := a.timers[key]
+			if !ok {
+				a.timers[key] = &timerState{series: s, hist: m.Histogram}
+				continue
+			}
+			// Гистограммы таймеров имеют одинаковые границы TimerBounds
+			_ = t.hist.Merge(*m.Histogram)
+		}
+	}
+}
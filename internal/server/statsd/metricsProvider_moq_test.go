@@ -0,0 +1,133 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package statsd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Ensure, that MetricsProviderMock does implement MetricsProvider.
+// If this is not the case, regenerate this file with moq.
+var _ MetricsProvider = &MetricsProviderMock{}
+
+// MetricsProviderMock is a mock implementation of MetricsProvider.
+//
+//	func TestSomethingThatUsesMetricsProvider(t *testing.T) {
+//
+//		// make and configure a mocked MetricsProvider
+//		mockedMetricsProvider := &MetricsProviderMock{
+//			GetMetricFunc: func(contextMoqParam context.Context, metrics metric.Metrics) (metric.Metrics, error) {
+//				panic("mock out the GetMetric method")
+//			},
+//			SetMetricsFunc: func(contextMoqParam context.Context, metricss []metric.Metrics) error {
+//				panic("mock out the SetMetrics method")
+//			},
+//		}
+//
+//		// use mockedMetricsProvider in code that requires MetricsProvider
+//		// and then make assertions.
+//
+//	}
+type MetricsProviderMock struct {
+	// GetMetricFunc mocks the GetMetric method.
+	GetMetricFunc func(contextMoqParam context.Context, metrics metric.Metrics) (metric.Metrics, error)
+
+	// SetMetricsFunc mocks the SetMetrics method.
+	SetMetricsFunc func(contextMoqParam context.Context, metricss []metric.Metrics) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// GetMetric holds details about calls to the GetMetric method.
+		GetMetric []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metrics is the metrics argument value.
+			Metrics metric.Metrics
+		}
+		// SetMetrics holds details about calls to the SetMetrics method.
+		SetMetrics []struct {
+			// ContextMoqParam is the contextMoqParam argument value.
+			ContextMoqParam context.Context
+			// Metricss is the metricss argument value.
+			Metricss []metric.Metrics
+		}
+	}
+	lockGetMetric  sync.RWMutex
+	lockSetMetrics sync.RWMutex
+}
+
+// GetMetric calls GetMetricFunc.
+func (mock *MetricsProviderMock) GetMetric(contextMoqParam context.Context, metrics metric.Metrics) (metric.Metrics, error) {
+	if mock.GetMetricFunc == nil {
+		panic("MetricsProviderMock.GetMetricFunc: method is nil but MetricsProvider.GetMetric was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metrics:         metrics,
+	}
+	mock.lockGetMetric.Lock()
+	mock.calls.GetMetric = append(mock.calls.GetMetric, callInfo)
+	mock.lockGetMetric.Unlock()
+	return mock.GetMetricFunc(contextMoqParam, metrics)
+}
+
+// GetMetricCalls gets all the calls that were made to GetMetric.
+// Check the length with:
+//
+//	len(mockedMetricsProvider.GetMetricCalls())
+func (mock *MetricsProviderMock) GetMetricCalls() []struct {
+	ContextMoqParam context.Context
+	Metrics         metric.Metrics
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metrics         metric.Metrics
+	}
+	mock.lockGetMetric.RLock()
+	calls = mock.calls.GetMetric
+	mock.lockGetMetric.RUnlock()
+	return calls
+}
+
+// SetMetrics calls SetMetricsFunc.
+func (mock *MetricsProviderMock) SetMetrics(contextMoqParam context.Context, metricss []metric.Metrics) error {
+	if mock.SetMetricsFunc == nil {
+		panic("MetricsProviderMock.SetMetricsFunc: method is nil but MetricsProvider.SetMetrics was just called")
+	}
+	callInfo := struct {
+		ContextMoqParam context.Context
+		Metricss        []metric.Metrics
+	}{
+		ContextMoqParam: contextMoqParam,
+		Metricss:        metricss,
+	}
+	mock.lockSetMetrics.Lock()
+	mock.calls.SetMetrics = append(mock.calls.SetMetrics, callInfo)
+	mock.lockSetMetrics.Unlock()
+	return mock.SetMetricsFunc(contextMoqParam, metricss)
+}
+
+// SetMetricsCalls gets all the calls that were made to SetMetrics.
+// Check the length with:
+//
+//	len(mockedMetricsProvider.SetMetricsCalls())
+func (mock *MetricsProviderMock) SetMetricsCalls() []struct {
+	ContextMoqParam context.Context
+	Metricss        []metric.Metrics
+} {
+	var calls []struct {
+		ContextMoqParam context.Context
+		Metricss        []metric.Metrics
+	}
+	mock.lockSetMetrics.RLock()
+	calls = mock.calls.SetMetrics
+	mock.lockSetMetrics.RUnlock()
+	return calls
+}
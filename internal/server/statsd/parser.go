@@ -0,0 +1,102 @@
+package statsd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Перечень поддерживаемых типов StatsD
+const (
+	TypeCounter = "c"
+	TypeGauge   = "g"
+	TypeTimer   = "ms"
+)
+
+var (
+	ErrInvalidLine       = errors.New("invalid statsd line")
+	ErrUnknownType       = errors.New("unknown statsd type")
+	ErrInvalidSampleRate = errors.New("invalid statsd sample rate")
+)
+
+// Тип Line представляет разобранную строку StatsD вида
+// name:value|type[|@rate][|#tag:value,...]
+type Line struct {
+	Name  string
+	Type  string
+	Value float64
+	// Rate - частота выборки в диапазоне (0, 1]
+	Rate float64
+	// Relative означает, что значение gauge задано как +/- изменение
+	Relative bool
+	// Labels заполняются из тегов в формате DogStatsD
+	Labels map[string]string
+}
+
+// Функция ParseLine разбирает одну строку StatsD
+func ParseLine(s string) (Line, error) {
+	line := Line{Rate: 1}
+	name, rest, ok := strings.Cut(s, ":")
+	if !ok || name == "" {
+		return line, ErrInvalidLine
+	}
+	line.Name = name
+
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 || parts[0] == "" {
+		return line, ErrInvalidLine
+	}
+	value, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return line, errors.Join(ErrInvalidLine, err)
+	}
+	line.Value = value
+
+	line.Type = parts[1]
+	switch line.Type {
+	case TypeCounter, TypeTimer:
+	case TypeGauge:
+		line.Relative = parts[0][0] == '+' || parts[0][0] == '-'
+	default:
+		return line, ErrUnknownType
+	}
+
+	for _, part := range parts[2:] {
+		switch {
+		case strings.HasPrefix(part, "@"):
+			rate, err := strconv.ParseFloat(part[1:], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return line, ErrInvalidSampleRate
+			}
+			line.Rate = rate
+		case strings.HasPrefix(part, "#"):
+			labels, err := parseTags(part[1:])
+			if err != nil {
+				return line, err
+			}
+			line.Labels = labels
+		default:
+			return line, ErrInvalidLine
+		}
+	}
+	return line, nil
+}
+
+// Функция parseTags разбирает теги вида name:value,name:value.
+// Тег без значения становится меткой с пустым значением.
+func parseTags(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	labels := make(map[string]string)
+	for _, tag := range strings.Split(s, ",") {
+		name, value, _ := strings.Cut(tag, ":")
+		labels[name] = value
+	}
+	if err := metric.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
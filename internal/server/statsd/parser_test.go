@@ -0,0 +1,88 @@
+package statsd
+
+import (
+	"testing"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLine(t *testing.T) {
+	type testCase struct {
+		name    string
+		line    string
+		want    Line
+		wantErr error
+	}
+
+	tests := []testCase{
+		{
+			name: "counter",
+			line: "requests:3|c",
+			want: Line{Name: "requests", Type: TypeCounter, Value: 3, Rate: 1},
+		},
+		{
+			name: "counter_with_rate",
+			line: "requests:1|c|@0.1",
+			want: Line{Name: "requests", Type: TypeCounter, Value: 1, Rate: 0.1},
+		},
+		{
+			name: "gauge",
+			line: "temperature:21.5|g",
+			want: Line{Name: "temperature", Type: TypeGauge, Value: 21.5, Rate: 1},
+		},
+		{
+			name: "gauge_delta",
+			line: "temperature:-2|g",
+			want: Line{Name: "temperature", Type: TypeGauge, Value: -2, Rate: 1, Relative: true},
+		},
+		{
+			name: "timer_with_tags",
+			line: "latency:320|ms|#host:web1,zone:eu",
+			want: Line{Name: "latency", Type: TypeTimer, Value: 320, Rate: 1,
+				Labels: map[string]string{"host": "web1", "zone": "eu"}},
+		},
+		{
+			name:    "no_value",
+			line:    "requests",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "no_type",
+			line:    "requests:1",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "bad_value",
+			line:    "requests:abc|c",
+			wantErr: ErrInvalidLine,
+		},
+		{
+			name:    "unknown_type",
+			line:    "users:42|s",
+			wantErr: ErrUnknownType,
+		},
+		{
+			name:    "bad_rate",
+			line:    "requests:1|c|@2",
+			wantErr: ErrInvalidSampleRate,
+		},
+		{
+			name:    "bad_tag",
+			line:    "requests:1|c|#a=b:c",
+			wantErr: metric.ErrInvalidLabel,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			line, err := ParseLine(test.line)
+			if test.wantErr != nil {
+				require.ErrorIs(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, line)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Пакет statsd предоставляет UDP-сервер, принимающий метрики в формате
+// StatsD. Значения агрегируются и записываются в хранилище раз в период
+// сброса: counter - суммой с учетом частоты выборки, gauge - последним
+// значением, таймеры ms - гистограммой.
+package statsd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/Eqke/metric-collector/utils/retry"
+	"go.uber.org/zap"
+)
+
+const (
+	// Максимальный размер UDP-пакета
+	maxPacketSize = 65535
+)
+
+//go:generate moq -out metricsProvider_moq_test.go . MetricsProvider
+type MetricsProvider interface {
+	GetMetric(context.Context, metric.Metrics) (metric.Metrics, error)
+	SetMetrics(context.Context, []metric.Metrics) error
+}
+
+type Server struct {
+	logger  *zap.SugaredLogger
+	storage MetricsProvider
+	host    string
+	ticker  time.Duration
+
+	mu  sync.Mutex
+	agg *aggregator
+}
+
+func New(
+	logger *zap.SugaredLogger,
+	storage MetricsProvider,
+	host string,
+	flushInterval int,
+) *Server {
+	return &Server{
+		logger:  logger.Named("statsd-server"),
+		storage: storage,
+		host:    host,
+		ticker:  time.Duration(flushInterval) * time.Second,
+		agg:     newAggregator(),
+	}
+}
+
+func (s *Server) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+	conn, err := net.ListenPacket("udp", s.host)
+	if err != nil {
+		s.logger.Errorw("Failed to listen", "host", s.host, "error", err)
+		return
+	}
+	s.logger.Infof("StatsD server was started. Listening on: %s", s.host)
+	go s.serve(conn)
+
+	t := time.NewTicker(s.ticker)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			{
+				conn.Close()
+				// Накопленные значения записываются до остановки
+				s.Flush(context.WithoutCancel(ctx))
+				s.logger.Info("StatsD server was stopped")
+				return
+			}
+		case <-t.C:
+			{
+				s.Flush(ctx)
+			}
+		}
+	}
+}
+
+// Метод serve читает пакеты до закрытия соединения
+func (s *Server) serve(conn net.PacketConn) {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			s.logger.Errorw("Failed to read packet", "error", err)
+			continue
+		}
+		s.HandlePacket(buf[:n])
+	}
+}
+
+// Метод HandlePacket разбирает пакет, содержащий строки StatsD,
+// разделенные переводом строки. Некорректные строки пропускаются.
+func (s *Server) HandlePacket(packet []byte) {
+	for _, raw := range strings.Split(string(packet), "\n") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		line, err := ParseLine(raw)
+		if err != nil {
+			s.logger.Errorw("Failed to parse line", "line", raw, "error", err)
+			continue
+		}
+		s.mu.Lock()
+		s.agg.add(line)
+		s.mu.Unlock()
+	}
+}
+
+// Метод Flush записывает накопленные значения в хранилище
+func (s *Server) Flush(ctx context.Context) {
+	s.mu.Lock()
+	metrics := s.agg.takeCounters()
+	metrics = append(metrics, s.agg.takeTimers()...)
+	gauges, unknown := s.agg.takeGauges()
+	metrics = append(metrics, gauges...)
+	s.mu.Unlock()
+
+	// Для gauge, заданных только изменениями, исходным значением
+	// считается сохраненное в хранилище
+	for _, key := range unknown {
+		id, labels, _ := metric.ParseSeriesKey(key)
+		var base float64
+		m, err := s.storage.GetMetric(ctx, metric.Metrics{ID: id, MType: metric.TypeGauge.String(), Labels: labels})
+		switch {
+		case err == nil && m.Value != nil:
+			base = *m.Value
+		case err != nil && !errors.Is(err, storage.ErrIsMetricDoesntExist):
+			s.logger.Errorw("Failed to read gauge", "metric", key, "error", err)
+		}
+		s.mu.Lock()
+		metrics = append(metrics, s.agg.resolveGauge(key, base))
+		s.mu.Unlock()
+	}
+
+	if len(metrics) == 0 {
+		return
+	}
+	if err := retry.Retry(s.logger, 3, func() error {
+		return s.storage.SetMetrics(ctx, metrics)
+	}); err != nil {
+		s.logger.Errorw("Failed to store metrics", "error", err)
+		// Несохраненные значения отправляются при следующем сбросе
+		s.mu.Lock()
+		s.agg.restore(metrics)
+		s.mu.Unlock()
+		return
+	}
+	s.logger.Infof("StatsD metrics were stored: %d", len(metrics))
+}
@@ -0,0 +1,155 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestServer_Flush(t *testing.T) {
+	logger := zaptest.NewLogger(t).Sugar()
+	var stored []metric.Metrics
+	mock := &MetricsProviderMock{
+		GetMetricFunc: func(_ context.Context, m metric.Metrics) (metric.Metrics, error) {
+			if m.ID == "queue" {
+				value := 10.0
+				m.Value = &value
+				return m, nil
+			}
+			return m, storage.ErrIsMetricDoesntExist
+		},
+		SetMetricsFunc: func(_ context.Context, metrics []metric.Metrics) error {
+			stored = metrics
+			return nil
+		},
+	}
+	s := New(logger, mock, "127.0.0.1:0", 10)
+
+	byKey := func() map[string]metric.Metrics {
+		res := make(map[string]metric.Metrics, len(stored))
+		for _, m := range stored {
+			res[metric.SeriesKey(m.ID, m.Labels)] = m
+		}
+		return res
+	}
+
+	s.HandlePacket([]byte("requests:2|c\nrequests:1|c|@0.5\n" +
+		"requests:1|c|#host:web1\n" +
+		"temperature:20|g\ntemperature:+1.5|g\n" +
+		"queue:-3|g\nfresh:+4|g\n" +
+		"latency:3|ms\nlatency:30|ms|@0.5\n" +
+		"broken\n"))
+	s.Flush(context.Background())
+
+	got := byKey()
+	require.Len(t, got, 6)
+	require.Equal(t, int64(4), *got["requests"].Delta)
+	require.Equal(t, int64(1), *got[`requests{host="web1"}`].Delta)
+	require.Equal(t, 21.5, *got["temperature"].Value)
+	require.Equal(t, 7.0, *got["queue"].Value)
+	require.Equal(t, 4.0, *got["fresh"].Value)
+	latency := got["latency"]
+	require.Equal(t, metric.TypeHistogram.String(), latency.MType)
+	require.Equal(t, uint64(3), latency.Histogram.Count)
+	require.Equal(t, 63.0, latency.Histogram.Sum)
+
+	t.Run("next_interval", func(t *testing.T) {
+		stored = nil
+		s.HandlePacket([]byte("queue:+1|g\nrequests:1|c"))
+		s.Flush(context.Background())
+
+		got := byKey()
+		require.Len(t, got, 2)
+		require.Equal(t, int64(1), *got["requests"].Delta)
+		require.Equal(t, 8.0, *got["queue"].Value)
+		require.Len(t, mock.GetMetricCalls(), 2)
+	})
+
+	t.Run("empty_interval", func(t *testing.T) {
+		calls := len(mock.SetMetricsCalls())
+		s.Flush(context.Background())
+		require.Len(t, mock.SetMetricsCalls(), calls)
+	})
+}
+
+func TestServer_FlushCounterRemainder(t *testing.T) {
+	logger := zaptest.NewLogger(t).Sugar()
+	var total int64
+	mock := &MetricsProviderMock{
+		SetMetricsFunc: func(_ context.Context, metrics []metric.Metrics) error {
+			for _, m := range metrics {
+				total += *m.Delta
+			}
+			return nil
+		},
+	}
+	s := New(logger, mock, "127.0.0.1:0", 10)
+
+	// Каждая строка дает 1/0.3 = 3.33 события, остаток не теряется
+	for i := 0; i < 3; i++ {
+		s.HandlePacket([]byte("hits:1|c|@0.3"))
+		s.Flush(context.Background())
+	}
+	require.Equal(t, int64(10), total)
+	require.Empty(t, s.agg.counters)
+}
+
+func TestServer_FlushRestoresOnFailure(t *testing.T) {
+	logger := zaptest.NewLogger(t).Sugar()
+	fail := true
+	var stored []metric.Metrics
+	mock := &MetricsProviderMock{
+		SetMetricsFunc: func(_ context.Context, metrics []metric.Metrics) error {
+			if fail {
+				return errors.New("storage is unavailable")
+			}
+			stored = metrics
+			return nil
+		},
+	}
+	s := New(logger, mock, "127.0.0.1:0", 10)
+
+	s.HandlePacket([]byte("hits:3|c\ntemperature:20|g\nlatency:5|ms"))
+	s.Flush(context.Background())
+	require.Empty(t, stored)
+
+	fail = false
+	s.HandlePacket([]byte("hits:2|c\nlatency:50|ms"))
+	s.Flush(context.Background())
+
+	got := make(map[string]metric.Metrics, len(stored))
+	for _, m := range stored {
+		got[m.ID] = m
+	}
+	require.Len(t, got, 3)
+	require.Equal(t, int64(5), *got["hits"].Delta)
+	require.Equal(t, 20.0, *got["temperature"].Value)
+	require.Equal(t, uint64(2), got["latency"].Histogram.Count)
+}
+
+func TestAggregator_EvictsIdleSeries(t *testing.T) {
+	a := newAggregator()
+	a.add(Line{Name: "temperature", Type: TypeGauge, Value: 20, Rate: 1})
+	a.add(Line{Name: "hits", Type: TypeCounter, Value: 1, Rate: 0.4})
+
+	gauges, _ := a.takeGauges()
+	require.Len(t, gauges, 1)
+	require.Len(t, a.takeCounters(), 1)
+	for i := 0; i < idleFlushes-1; i++ {
+		gauges, _ = a.takeGauges()
+		require.Empty(t, gauges)
+		require.Empty(t, a.takeCounters())
+	}
+	require.Contains(t, a.gauges, "temperature")
+	require.Contains(t, a.counters, "hits")
+
+	a.takeGauges()
+	a.takeCounters()
+	require.Empty(t, a.gauges)
+	require.Empty(t, a.counters)
+}
@@ -0,0 +1,53 @@
+// Пакет storage предоставляет интерфейс для хранилища.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// HTTP-заголовок с идентификатором пакета метрик
+	BatchIDHeader = "Idempotency-Key"
+	// Время, в течение которого хранилище помнит примененные пакеты,
+	// по умолчанию
+	DefaultBatchIDTTL = time.Hour
+)
+
+var (
+	ErrInvalidBatchID = errors.New("invalid batch id")
+	// Ошибка SetMetrics для пакета, который уже был применен. Пакет
+	// повторно не применяется, для клиента запрос считается успешным.
+	ErrBatchApplied = errors.New("batch already applied")
+)
+
+// Допустимый идентификатор пакета
+var batchIDRe = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,128}$`)
+
+type batchContextKey struct{}
+
+// Функция ValidateBatchID проверяет идентификатор пакета: латинские
+// буквы, цифры, '_', '.', ':', '-', не длиннее 128 символов
+func ValidateBatchID(id string) error {
+	if !batchIDRe.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidBatchID, id)
+	}
+	return nil
+}
+
+// Функция WithBatchID возвращает контекст с идентификатором пакета id.
+// SetMetrics применяет пакет с идентификатором не более одного раза за
+// время хранения идентификаторов у арендатора.
+func WithBatchID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, batchContextKey{}, id)
+}
+
+// Функция BatchIDFromContext возвращает идентификатор пакета из
+// контекста или пустую строку
+func BatchIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(batchContextKey{}).(string)
+	return id
+}
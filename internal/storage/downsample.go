@@ -0,0 +1,52 @@
+// Пакет storage предоставляет интерфейс для хранилища.
+package storage
+
+import (
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Функция Downsample группирует отсортированные по времени значения
+// по интервалам длиной step, выровненным по началу эпохи.
+// Для gauge в интервал попадает среднее значение, для counter -
+// последнее накопленное значение. Метка времени значения - начало интервала.
+func Downsample(metricType string, samples []metric.Sample, step time.Duration) []metric.Sample {
+	if step <= 0 || len(samples) == 0 {
+		return samples
+	}
+	res := make([]metric.Sample, 0, len(samples))
+	var (
+		bucket int64
+		sum    float64
+		count  int
+		last   metric.Sample
+	)
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		ts := time.Unix(0, bucket).UTC()
+		switch metricType {
+		case metric.TypeGauge.String():
+			avg := sum / float64(count)
+			res = append(res, metric.Sample{Timestamp: ts, Value: &avg})
+		case metric.TypeCounter.String():
+			res = append(res, metric.Sample{Timestamp: ts, Delta: last.Delta})
+		}
+	}
+	for i, smp := range samples {
+		b := smp.Timestamp.UnixNano() / int64(step) * int64(step)
+		if i == 0 || b != bucket {
+			flush()
+			bucket, sum, count = b, 0, 0
+		}
+		if smp.Value != nil {
+			sum += *smp.Value
+		}
+		last = smp
+		count++
+	}
+	flush()
+	return res
+}
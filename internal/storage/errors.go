@@ -9,12 +9,26 @@ var (
 	ErrIsUnknownType       = errors.New("unknown metric type")
 	ErrIDIsEmpty           = errors.New("metric name is empty")
 	ErrValueIsEmpty        = errors.New("metric value is empty")
+	ErrInvalidRange        = errors.New("invalid time range")
+	ErrInvalidRetention    = errors.New("invalid retention tiers")
+	ErrInvalidQuery        = errors.New("invalid query")
+	ErrPrefixIsEmpty       = errors.New("metric name prefix is empty")
+	ErrSeriesLimitExceeded = errors.New("tenant series limit exceeded")
 
 	ErrPointSetValue          = "error in storage.SetValue(): "
 	ErrPointSetMetric         = "error in storage.SetMetric(): "
+	ErrPointSetMetrics        = "error in storage.SetMetrics(): "
 	ErrPointGetValue          = "error in storage.GetValue(): "
 	ErrPointGetMetrics        = "error in storage.GetMetrics(): "
 	ErrPointGetMetric         = "error in storage.GetMetric(): "
+	ErrPointFindMetrics       = "error in storage.FindMetrics(): "
+	ErrPointGetMetricRange    = "error in storage.GetMetricRange(): "
+	ErrPointCompact           = "error in storage.Compact(): "
+	ErrPointQuery             = "error in storage.Query(): "
+	ErrPointDeleteMetric      = "error in storage.DeleteMetric(): "
+	ErrPointDeleteByPrefix    = "error in storage.DeleteByPrefix(): "
+	ErrPointResetCounter      = "error in storage.ResetCounter(): "
+	ErrPointDeleteStale       = "error in storage.DeleteStale(): "
 	ErrPointGetGaugeMetrics   = "error in storage.GetGaugeMetrics(): "
 	ErrPointGetGaugeMetric    = "error in storage.GetGaugeMetric(): "
 	ErrPointGetCounterMetrics = "error in storage.GetCounterMetrics(): "
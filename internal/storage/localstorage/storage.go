@@ -6,10 +6,16 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	store "github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/internal/tenant"
 	e "github.com/Eqke/metric-collector/pkg/error"
 	"github.com/Eqke/metric-collector/pkg/metric"
 	"go.uber.org/zap"
@@ -19,33 +25,88 @@ const (
 	TYPE = "Local mem database"
 )
 
-// Тип LocalStorage является реализацией хранилища в памяти
+// Тип LocalStorage является реализацией хранилища в памяти. Метрики
+// каждого арендатора хранятся в отдельных картах.
 type LocalStorage struct {
-	logger  *zap.SugaredLogger
-	mu      *sync.Mutex
+	logger *zap.SugaredLogger
+	mu     *sync.Mutex
+	// Метрики арендатора tenant.Default
 	storage storage
+	// <Tenant, storage>, метрики остальных арендаторов
+	tenants map[string]storage
+	// Ограничение числа серий арендатора, 0 - без ограничения
+	maxSeries int
+	// Примененные пакеты и очередь истечения их идентификаторов в
+	// порядке применения
+	batches    map[batchRef]time.Time
+	batchQueue []batchRef
+	batchTTL   time.Duration
 }
 
-// storage - Внутренний тип хранилища, содержит две карты для каждого типа
-// метрик
+// dump - формат сохранения хранилища в файл. Метрики арендатора по
+// умолчанию сохраняются на верхнем уровне, как до появления арендаторов.
+type dump struct {
+	storage
+	Tenants map[string]storage `json:",omitempty"`
+}
+
+// storage - Внутренний тип хранилища, содержит карты для каждого типа
+// метрик и историю их значений. Ключом карт является ключ серии
+// metric.SeriesKey, для метрик без меток он совпадает с именем.
 type storage struct {
-	// <NameMetric, Metric>
-	GaugeMetrics   map[string]metric.Gauge
-	CounterMetrics map[string]metric.Counter
+	// <SeriesKey, Metric>
+	GaugeMetrics     map[string]metric.Gauge
+	CounterMetrics   map[string]metric.Counter
+	HistogramMetrics map[string]metric.Histogram
+	// <SeriesKey, []Sample>, значения отсортированы по времени
+	GaugeHistory   map[string][]metric.Sample
+	CounterHistory map[string][]metric.Sample
+	// <Resolution, <SeriesKey, []Aggregate>>, агрегаты отсортированы по времени
+	GaugeRollups   map[time.Duration]map[string][]store.Aggregate
+	CounterRollups map[time.Duration]map[string][]store.Aggregate
+	// <Type, <SeriesKey, Time>>, время последнего обновления серии
+	Updated map[string]map[string]time.Time
 }
 
 // Функция New вовзращает экземляр LocalStorage
 func New(logger *zap.SugaredLogger) *LocalStorage {
 	return &LocalStorage{
-		storage: newStorage(),
-		mu:      &sync.Mutex{},
-		logger:  logger,
+		storage:  newStorage(),
+		mu:       &sync.Mutex{},
+		logger:   logger,
+		batchTTL: store.DefaultBatchIDTTL,
 	}
 }
 
+// Метод SetSeriesLimit устанавливает ограничение числа серий
+// арендатора, 0 снимает ограничение
+func (s *LocalStorage) SetSeriesLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxSeries = limit
+}
+
+// Метод SetBatchIDTTL устанавливает время, в течение которого
+// хранилище помнит примененные пакеты
+func (s *LocalStorage) SetBatchIDTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batchTTL = ttl
+}
+
 func (s *LocalStorage) SetValue(ctx context.Context, metricType, name, value string) error {
+	key, err := normalizeKey(name)
+	if err != nil {
+		s.logger.Error(store.ErrPointSetValue, err)
+		return e.WrapError(store.ErrPointSetValue, err)
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	st := s.forTenant(ctx)
+	if err = s.checkSeriesLimit(st, seriesRef{metricType, key}); err != nil {
+		s.logger.Error(store.ErrPointSetValue, err)
+		return err
+	}
 	switch metricType {
 	case metric.TypeCounter.String():
 		{
@@ -54,7 +115,8 @@ func (s *LocalStorage) SetValue(ctx context.Context, metricType, name, value str
 				s.logger.Error(store.ErrPointSetValue, err)
 				return e.WrapError(store.ErrPointSetValue, err)
 			}
-			s.storage.CounterMetrics[name] += metric.Counter(metricValueInt)
+			st.CounterMetrics[key] += metric.Counter(metricValueInt)
+			st.recordCounter(key, time.Now().UTC())
 		}
 	case metric.TypeGauge.String():
 		{
@@ -63,7 +125,20 @@ func (s *LocalStorage) SetValue(ctx context.Context, metricType, name, value str
 				s.logger.Error(store.ErrPointSetValue, err)
 				return e.WrapError(store.ErrPointSetValue, err)
 			}
-			s.storage.GaugeMetrics[name] = metric.Gauge(metricGauge)
+			st.GaugeMetrics[key] = metric.Gauge(metricGauge)
+			st.recordGauge(key, time.Now().UTC())
+		}
+	case metric.TypeHistogram.String():
+		{
+			h, err := metric.ParseHistogram(value)
+			if err != nil {
+				s.logger.Error(store.ErrPointSetValue, err)
+				return e.WrapError(store.ErrPointSetValue, err)
+			}
+			if err = st.mergeHistogram(key, *h); err != nil {
+				s.logger.Error(store.ErrPointSetValue, err)
+				return e.WrapError(store.ErrPointSetValue, err)
+			}
 		}
 	default:
 		{
@@ -72,6 +147,7 @@ func (s *LocalStorage) SetValue(ctx context.Context, metricType, name, value str
 
 		}
 	}
+	st.touch(metricType, key, time.Now().UTC())
 	s.logger.Infof("metric was saved with type: %s, name: %s, value: %s",
 		metricType, name, value)
 	return nil
@@ -84,12 +160,17 @@ func (s *LocalStorage) SetMetric(ctx context.Context, m metric.Metrics) error {
 }
 
 func (s *LocalStorage) GetValue(ctx context.Context, metricType, name string) (string, error) {
+	key, err := normalizeKey(name)
+	if err != nil {
+		return "", e.WrapError(store.ErrPointGetValue, err)
+	}
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	st := s.forTenant(ctx)
 	switch metricType {
 	case metric.TypeCounter.String():
 		{
-			val, ok := s.storage.CounterMetrics[name]
+			val, ok := st.CounterMetrics[key]
 			if !ok {
 				return "", store.ErrIsMetricDoesntExist
 			}
@@ -100,7 +181,7 @@ func (s *LocalStorage) GetValue(ctx context.Context, metricType, name string) (s
 		}
 	case metric.TypeGauge.String():
 		{
-			val, ok := s.storage.GaugeMetrics[name]
+			val, ok := st.GaugeMetrics[key]
 			if !ok {
 				return "", store.ErrIsMetricDoesntExist
 			}
@@ -109,6 +190,17 @@ func (s *LocalStorage) GetValue(ctx context.Context, metricType, name string) (s
 				metricType, name, v)
 			return v, nil
 		}
+	case metric.TypeHistogram.String():
+		{
+			val, ok := st.HistogramMetrics[key]
+			if !ok {
+				return "", store.ErrIsMetricDoesntExist
+			}
+			v := val.String()
+			s.logger.Infof("metric was found with type: %s, name: %s, value: %s",
+				metricType, name, v)
+			return v, nil
+		}
 	default:
 		{
 			return "", e.WrapError(store.ErrPointGetValue, store.ErrIsUnknownType)
@@ -120,85 +212,320 @@ func (s *LocalStorage) GetValue(ctx context.Context, metricType, name string) (s
 func (s *LocalStorage) GetMetric(ctx context.Context, m metric.Metrics) (metric.Metrics, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	var met metric.Metrics
-	switch m.MType {
-	case metric.TypeCounter.String():
-		{
-			var val metric.Counter
-			var ok bool
-			if val, ok = s.storage.CounterMetrics[m.ID]; !ok {
-				return met, store.ErrIsMetricDoesntExist
-			}
-			delta := int64(val)
-			met = metric.Metrics{
-				ID:    m.ID,
-				MType: m.MType,
-				Delta: &delta,
-			}
+	st := s.forTenant(ctx)
+	return st.getMetric(metric.SeriesKey(m.ID, m.Labels), m)
+}
+
+func (s *LocalStorage) FindMetrics(
+	ctx context.Context,
+	m metric.Metrics,
+	matchers []*metric.LabelMatcher,
+) ([]metric.Metrics, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.forTenant(ctx)
+	keys, ok := st.keys(m.MType)
+	if !ok {
+		return nil, e.WrapError(store.ErrPointFindMetrics, store.ErrIsUnknownType)
+	}
+	res := make([]metric.Metrics, 0)
+	for _, key := range keys {
+		id, labels, err := metric.ParseSeriesKey(key)
+		if err != nil || id != m.ID || !metric.MatchLabels(labels, matchers) {
+			continue
 		}
-	case metric.TypeGauge.String():
-		{
-			var val metric.Gauge
-			var ok bool
-			if val, ok = s.storage.GaugeMetrics[m.ID]; !ok {
-				return met, store.ErrIsMetricDoesntExist
-			}
-			value := float64(val)
-			met = metric.Metrics{
-				ID:    m.ID,
-				MType: m.MType,
-				Value: &value,
-			}
+		met, err := st.getMetric(key, metric.Metrics{ID: id, MType: m.MType, Labels: labels})
+		if err != nil {
+			return nil, err
 		}
-	default:
-		{
-			return met, e.WrapError(store.ErrPointGetMetric, store.ErrIsUnknownType)
+		res = append(res, met)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return metric.SeriesKey(res[i].ID, res[i].Labels) < metric.SeriesKey(res[j].ID, res[j].Labels)
+	})
+	return res, nil
+}
+
+// Метод Query отбирает серии из GetMetrics по шаблону имени и
+// условиям на метки и вычисляет агрегацию
+func (s *LocalStorage) Query(ctx context.Context, q store.Query) ([]store.QueryResult, error) {
+	if err := q.Validate(); err != nil {
+		return nil, e.WrapError(store.ErrPointQuery, err)
+	}
+	name := regexp.MustCompile(q.NamePattern())
+	metrics, err := s.GetMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]store.QueryResult, 0)
+	for _, m := range metrics[q.MType] {
+		if !name.MatchString(m.Name) || !metric.MatchLabels(m.Labels, q.Matchers) {
+			continue
+		}
+		v, err := strconv.ParseFloat(m.Value, 64)
+		if err != nil {
+			return nil, e.WrapError(store.ErrPointQuery, err)
 		}
+		series = append(series, store.QueryResult{ID: m.Name, Labels: m.Labels, Value: v})
 	}
-	return met, nil
+	return q.Aggregate(series), nil
 }
 
 func (s *LocalStorage) GetMetrics(ctx context.Context) (map[string][]store.Metric, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	metrics := make(map[string][]store.Metric, 2)
-	metrics[metric.TypeCounter.String()] = make([]store.Metric, 0, len(s.storage.CounterMetrics))
-	metrics[metric.TypeGauge.String()] = make([]store.Metric, 0, len(s.storage.GaugeMetrics))
-	for name := range s.storage.CounterMetrics {
-		m := store.Metric{
-			Name:  name,
-			Value: s.storage.CounterMetrics[name].String(),
+	st := s.forTenant(ctx)
+	metrics := make(map[string][]store.Metric, 3)
+	metrics[metric.TypeCounter.String()] = make([]store.Metric, 0, len(st.CounterMetrics))
+	metrics[metric.TypeGauge.String()] = make([]store.Metric, 0, len(st.GaugeMetrics))
+	metrics[metric.TypeHistogram.String()] = make([]store.Metric, 0, len(st.HistogramMetrics))
+	for key := range st.CounterMetrics {
+		metrics[metric.TypeCounter.String()] = append(metrics[metric.TypeCounter.String()],
+			newMetric(key, st.CounterMetrics[key].String(), st.updatedAt(metric.TypeCounter.String(), key)))
+	}
+	for key := range st.GaugeMetrics {
+		metrics[metric.TypeGauge.String()] = append(metrics[metric.TypeGauge.String()],
+			newMetric(key, st.GaugeMetrics[key].String(), st.updatedAt(metric.TypeGauge.String(), key)))
+	}
+	for key, h := range st.HistogramMetrics {
+		metrics[metric.TypeHistogram.String()] = append(metrics[metric.TypeHistogram.String()],
+			newMetric(key, h.String(), st.updatedAt(metric.TypeHistogram.String(), key)))
+	}
+	return metrics, nil
+}
+
+func (s *LocalStorage) GetMetricRange(
+	ctx context.Context,
+	m metric.Metrics,
+	from, to time.Time,
+	step time.Duration,
+) ([]metric.Sample, error) {
+	if to.Before(from) || step < 0 {
+		return nil, e.WrapError(store.ErrPointGetMetricRange, store.ErrInvalidRange)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.forTenant(ctx)
+	history, rollups, ok := st.byType(m.MType)
+	if !ok {
+		return nil, e.WrapError(store.ErrPointGetMetricRange, store.ErrIsUnknownType)
+	}
+	key := metric.SeriesKey(m.ID, m.Labels)
+	samples, found := history[key]
+	res := make([]metric.Sample, 0)
+	// Более старые значения хранятся в агрегатах
+	for _, byName := range rollups {
+		aggs, ok := byName[key]
+		if !ok {
+			continue
+		}
+		found = true
+		for _, a := range aggs {
+			if !a.Timestamp.Before(from) && !a.Timestamp.After(to) {
+				res = append(res, store.AggregateToSample(m.MType, a))
+			}
 		}
-		metrics[metric.TypeCounter.String()] = append(metrics[metric.TypeCounter.String()], m)
 	}
-	for name := range s.storage.GaugeMetrics {
-		m := store.Metric{
-			Name:  name,
-			Value: s.storage.GaugeMetrics[name].String(),
+	if !found {
+		return nil, store.ErrIsMetricDoesntExist
+	}
+	lo := sort.Search(len(samples), func(i int) bool {
+		return !samples[i].Timestamp.Before(from)
+	})
+	hi := sort.Search(len(samples), func(i int) bool {
+		return samples[i].Timestamp.After(to)
+	})
+	res = append(res, samples[lo:hi]...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp.Before(res[j].Timestamp)
+	})
+	return store.Downsample(m.MType, res, step), nil
+}
+
+func (s *LocalStorage) Compact(ctx context.Context, tiers []store.RetentionTier, now time.Time) error {
+	if len(tiers) == 0 || tiers[0].Resolution != 0 {
+		return e.WrapError(store.ErrPointCompact, store.ErrInvalidRetention)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, st := range s.all() {
+		for _, mt := range []string{metric.TypeGauge.String(), metric.TypeCounter.String()} {
+			history, rollups, _ := st.byType(mt)
+			s.compactHistory(mt, history, rollups, tiers, now)
+			s.compactRollups(mt, rollups, tiers, now)
 		}
-		metrics[metric.TypeGauge.String()] = append(metrics[metric.TypeGauge.String()], m)
 	}
-	return metrics, nil
+	return nil
 }
 
 func (s *LocalStorage) SetMetrics(ctx context.Context, metrics []metric.Metrics) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	now := time.Now()
+	batch := batchRef{tenant: tenant.FromContext(ctx), id: store.BatchIDFromContext(ctx)}
+	if batch.id != "" {
+		s.expireBatches(now)
+		if _, ok := s.batches[batch]; ok {
+			return store.ErrBatchApplied
+		}
+	}
+	// Пакет с некорректной метрикой или превышающий ограничение числа
+	// серий не применяется даже частично
+	st := s.forTenant(ctx)
+	refs := make([]seriesRef, 0, len(metrics))
+	bounds := make(map[string][]float64)
 	for _, m := range metrics {
-		err := s.setMetric(ctx, m)
-		if err != nil {
+		if err := validateMetric(m); err != nil {
+			return err
+		}
+		key := metric.SeriesKey(m.ID, m.Labels)
+		if err := st.checkBounds(key, m, bounds); err != nil {
+			return err
+		}
+		refs = append(refs, seriesRef{m.MType, key})
+	}
+	if err := s.checkSeriesLimit(st, refs...); err != nil {
+		return err
+	}
+	for i, m := range metrics {
+		if err := st.applyMetric(refs[i].key, m); err != nil {
 			return err
 		}
 	}
+	if batch.id != "" {
+		if s.batches == nil {
+			s.batches = make(map[batchRef]time.Time)
+		}
+		s.batches[batch] = now
+		s.batchQueue = append(s.batchQueue, batch)
+	}
 	return nil
 }
 
+// Тип batchRef - арендатор и идентификатор пакета
+type batchRef struct {
+	tenant string
+	id     string
+}
+
+// Метод expireBatches забывает пакеты, примененные раньше now-batchTTL
+func (s *LocalStorage) expireBatches(now time.Time) {
+	n := 0
+	for _, ref := range s.batchQueue {
+		if !s.batches[ref].Before(now.Add(-s.batchTTL)) {
+			break
+		}
+		delete(s.batches, ref)
+		n++
+	}
+	s.batchQueue = s.batchQueue[n:]
+}
+
+func (s *LocalStorage) DeleteMetric(
+	ctx context.Context,
+	m metric.Metrics,
+	matchers []*metric.LabelMatcher,
+) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.forTenant(ctx)
+	keys, ok := st.keys(m.MType)
+	if !ok {
+		return 0, e.WrapError(store.ErrPointDeleteMetric, store.ErrIsUnknownType)
+	}
+	deleted := 0
+	for _, key := range keys {
+		id, labels, err := metric.ParseSeriesKey(key)
+		if err != nil || id != m.ID || !metric.MatchLabels(labels, matchers) {
+			continue
+		}
+		st.delete(m.MType, key)
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *LocalStorage) DeleteByPrefix(ctx context.Context, metricType, prefix string) (int, error) {
+	if prefix == "" {
+		return 0, e.WrapError(store.ErrPointDeleteByPrefix, store.ErrPrefixIsEmpty)
+	}
+	types := []string{metricType}
+	if metricType == "" {
+		types = []string{
+			metric.TypeGauge.String(),
+			metric.TypeCounter.String(),
+			metric.TypeHistogram.String(),
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.forTenant(ctx)
+	deleted := 0
+	for _, mt := range types {
+		keys, ok := st.keys(mt)
+		if !ok {
+			return 0, e.WrapError(store.ErrPointDeleteByPrefix, store.ErrIsUnknownType)
+		}
+		for _, key := range keys {
+			id, _, err := metric.ParseSeriesKey(key)
+			if err != nil || !strings.HasPrefix(id, prefix) {
+				continue
+			}
+			st.delete(mt, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (s *LocalStorage) ResetCounter(ctx context.Context, m metric.Metrics) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.forTenant(ctx)
+	key := metric.SeriesKey(m.ID, m.Labels)
+	if _, ok := st.CounterMetrics[key]; !ok {
+		return e.WrapError(store.ErrPointResetCounter, store.ErrIsMetricDoesntExist)
+	}
+	now := time.Now().UTC()
+	st.CounterMetrics[key] = 0
+	st.recordCounter(key, now)
+	st.touch(metric.TypeCounter.String(), key, now)
+	return nil
+}
+
+func (s *LocalStorage) DeleteStale(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for _, st := range s.all() {
+		for mt, updated := range st.Updated {
+			for key, t := range updated {
+				if t.Before(before) {
+					st.delete(mt, key)
+					deleted++
+				}
+			}
+		}
+	}
+	return deleted, nil
+}
+
 func (s *LocalStorage) ToJSON(ctx context.Context) ([]byte, error) {
-	return json.MarshalIndent(s.storage, "", "  ")
+	return json.MarshalIndent(dump{storage: s.storage, Tenants: s.tenants}, "", "  ")
 }
 
 func (s *LocalStorage) FromJSON(ctx context.Context, data []byte) error {
-	return json.Unmarshal(data, &s.storage)
+	var d dump
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	s.storage = d.storage.init(now)
+	s.tenants = make(map[string]storage, len(d.Tenants))
+	for id, st := range d.Tenants {
+		s.tenants[id] = st.init(now)
+	}
+	return nil
 }
 
 func (s *LocalStorage) ToFile(ctx context.Context, path string) error {
@@ -240,38 +567,503 @@ func (s *LocalStorage) Type() string {
 }
 
 func (s *LocalStorage) setMetric(ctx context.Context, m metric.Metrics) error {
+	if err := validateMetric(m); err != nil {
+		return err
+	}
+	key := metric.SeriesKey(m.ID, m.Labels)
+	st := s.forTenant(ctx)
+	if err := s.checkSeriesLimit(st, seriesRef{m.MType, key}); err != nil {
+		return err
+	}
+	return st.applyMetric(key, m)
+}
+
+// Функция validateMetric проверяет метрику, не изменяя хранилище
+func validateMetric(m metric.Metrics) error {
 	if m.ID == "" {
 		return store.ErrIDIsEmpty
 	}
+	if err := metric.ValidateLabels(m.Labels); err != nil {
+		return err
+	}
+	switch m.MType {
+	case metric.TypeCounter.String():
+		if m.Delta == nil {
+			return store.ErrValueIsEmpty
+		}
+	case metric.TypeGauge.String():
+		if m.Value == nil {
+			return store.ErrValueIsEmpty
+		}
+	case metric.TypeHistogram.String():
+		if m.Histogram == nil {
+			return store.ErrValueIsEmpty
+		}
+		return m.Histogram.Validate()
+	default:
+		return store.ErrIsUnknownType
+	}
+	return nil
+}
+
+// Метод checkBounds проверяет, что границы корзин гистограммы совпадают
+// с границами сохраненной серии key или ее предыдущего значения в пакете.
+// Границы значений пакета запоминаются в seen.
+func (s storage) checkBounds(key string, m metric.Metrics, seen map[string][]float64) error {
+	if m.MType != metric.TypeHistogram.String() {
+		return nil
+	}
+	want, ok := seen[key]
+	if !ok {
+		cur, stored := s.HistogramMetrics[key]
+		if !stored {
+			seen[key] = m.Histogram.Bounds
+			return nil
+		}
+		want = cur.Bounds
+		seen[key] = want
+	}
+	if !slices.Equal(want, m.Histogram.Bounds) {
+		return metric.ErrBucketsMismatch
+	}
+	return nil
+}
 
+// Метод applyMetric сохраняет проверенную метрику под ключом серии key
+func (s storage) applyMetric(key string, m metric.Metrics) error {
 	switch m.MType {
 	case metric.TypeCounter.String():
 		{
-			if m.Delta == nil {
-				return store.ErrValueIsEmpty
-			}
-			s.storage.CounterMetrics[m.ID] += metric.Counter(*m.Delta)
+			s.CounterMetrics[key] += metric.Counter(*m.Delta)
+			s.recordCounter(key, time.Now().UTC())
 		}
 	case metric.TypeGauge.String():
 		{
-			if m.Value == nil {
-				return store.ErrValueIsEmpty
+			s.GaugeMetrics[key] = metric.Gauge(*m.Value)
+			s.recordGauge(key, sampleTime(m))
+		}
+	case metric.TypeHistogram.String():
+		{
+			if err := s.mergeHistogram(key, *m.Histogram); err != nil {
+				return err
 			}
-			s.storage.GaugeMetrics[m.ID] = metric.Gauge(*m.Value)
 		}
 	default:
 		{
 			return store.ErrIsUnknownType
 		}
 	}
+	s.touch(m.MType, key, time.Now().UTC())
+	return nil
+}
+
+// Метод getMetric возвращает метрику, сохраненную под ключом серии key
+func (s storage) getMetric(key string, m metric.Metrics) (metric.Metrics, error) {
+	var met metric.Metrics
+	switch m.MType {
+	case metric.TypeCounter.String():
+		{
+			var val metric.Counter
+			var ok bool
+			if val, ok = s.CounterMetrics[key]; !ok {
+				return met, store.ErrIsMetricDoesntExist
+			}
+			delta := int64(val)
+			met = metric.Metrics{
+				ID:     m.ID,
+				MType:  m.MType,
+				Delta:  &delta,
+				Labels: m.Labels,
+			}
+		}
+	case metric.TypeGauge.String():
+		{
+			var val metric.Gauge
+			var ok bool
+			if val, ok = s.GaugeMetrics[key]; !ok {
+				return met, store.ErrIsMetricDoesntExist
+			}
+			value := float64(val)
+			met = metric.Metrics{
+				ID:     m.ID,
+				MType:  m.MType,
+				Value:  &value,
+				Labels: m.Labels,
+			}
+		}
+	case metric.TypeHistogram.String():
+		{
+			val, ok := s.HistogramMetrics[key]
+			if !ok {
+				return met, store.ErrIsMetricDoesntExist
+			}
+			h := metric.NewHistogram(val.Bounds)
+			_ = h.Merge(val)
+			met = metric.Metrics{
+				ID:        m.ID,
+				MType:     m.MType,
+				Histogram: h,
+				Labels:    m.Labels,
+			}
+		}
+	default:
+		{
+			return met, e.WrapError(store.ErrPointGetMetric, store.ErrIsUnknownType)
+		}
+	}
+	met.UpdatedAt = s.updatedAt(m.MType, key)
+	return met, nil
+}
+
+// Метод mergeHistogram добавляет значения к сохраненной гистограмме.
+// Гистограмма копируется, чтобы хранилище не разделяло срезы с вызывающим.
+func (s storage) mergeHistogram(name string, h metric.Histogram) error {
+	cur, ok := s.HistogramMetrics[name]
+	if !ok {
+		cur = *metric.NewHistogram(h.Bounds)
+	}
+	if err := cur.Merge(h); err != nil {
+		return err
+	}
+	s.HistogramMetrics[name] = cur
 	return nil
 }
 
+// Метод recordGauge сохраняет текущее значение gauge в историю
+func (s storage) recordGauge(name string, ts time.Time) {
+	value := float64(s.GaugeMetrics[name])
+	s.GaugeHistory[name] = insertSample(s.GaugeHistory[name],
+		metric.Sample{Timestamp: ts, Value: &value})
+}
+
+// Метод recordCounter сохраняет накопленное значение counter в историю.
+// Накопленное значение складывается в порядке получения, поэтому ts -
+// время получения: значение со временем клиента, пришедшее не по порядку,
+// нарушило бы монотонность истории.
+func (s storage) recordCounter(name string, ts time.Time) {
+	delta := int64(s.CounterMetrics[name])
+	s.CounterHistory[name] = insertSample(s.CounterHistory[name],
+		metric.Sample{Timestamp: ts, Delta: &delta})
+}
+
+// Функция sampleTime возвращает момент измерения метрики
+func sampleTime(m metric.Metrics) time.Time {
+	if m.Timestamp != nil {
+		return m.Timestamp.UTC()
+	}
+	return time.Now().UTC()
+}
+
+// Функция insertSample добавляет значение в историю, сохраняя порядок
+// по времени: значения с переданным временем могут приходить не по порядку
+func insertSample(samples []metric.Sample, sample metric.Sample) []metric.Sample {
+	i := sort.Search(len(samples), func(i int) bool {
+		return samples[i].Timestamp.After(sample.Timestamp)
+	})
+	return slices.Insert(samples, i, sample)
+}
+
+// Метод compactHistory переносит исходные значения старше срока хранения
+// в агрегаты следующего уровня или удаляет их, если уровень последний.
+// Переносятся только полностью завершенные интервалы следующего уровня.
+func (s *LocalStorage) compactHistory(
+	metricType string,
+	history map[string][]metric.Sample,
+	rollups map[time.Duration]map[string][]store.Aggregate,
+	tiers []store.RetentionTier,
+	now time.Time,
+) {
+	cutoff := now.Add(-tiers[0].Retention)
+	var dst *store.RetentionTier
+	if len(tiers) > 1 {
+		dst = &tiers[1]
+		cutoff = store.AlignTime(cutoff, dst.Resolution)
+	}
+	for name, samples := range history {
+		idx := sort.Search(len(samples), func(i int) bool {
+			return !samples[i].Timestamp.Before(cutoff)
+		})
+		if idx == 0 {
+			continue
+		}
+		if dst != nil {
+			if rollups[dst.Resolution] == nil {
+				rollups[dst.Resolution] = make(map[string][]store.Aggregate)
+			}
+			var prev *float64
+			if aggs := rollups[dst.Resolution][name]; len(aggs) > 0 {
+				prev = &aggs[len(aggs)-1].Last
+			}
+			rollups[dst.Resolution][name] = append(rollups[dst.Resolution][name],
+				store.RollupSamples(metricType, samples[:idx], dst.Resolution, prev)...)
+		}
+		history[name] = append([]metric.Sample(nil), samples[idx:]...)
+	}
+	s.logger.Infof("%s history was compacted before %v", metricType, cutoff)
+}
+
+// Метод compactRollups переносит агрегаты старше срока хранения своего
+// уровня на следующий уровень или удаляет их, если уровень последний
+func (s *LocalStorage) compactRollups(
+	metricType string,
+	rollups map[time.Duration]map[string][]store.Aggregate,
+	tiers []store.RetentionTier,
+	now time.Time,
+) {
+	for i := 1; i < len(tiers); i++ {
+		src := tiers[i]
+		cutoff := now.Add(-src.Retention)
+		var dst *store.RetentionTier
+		if i+1 < len(tiers) {
+			dst = &tiers[i+1]
+			cutoff = store.AlignTime(cutoff, dst.Resolution)
+		}
+		for name, aggs := range rollups[src.Resolution] {
+			idx := sort.Search(len(aggs), func(i int) bool {
+				return !aggs[i].Timestamp.Before(cutoff)
+			})
+			if idx == 0 {
+				continue
+			}
+			if dst != nil {
+				if rollups[dst.Resolution] == nil {
+					rollups[dst.Resolution] = make(map[string][]store.Aggregate)
+				}
+				rollups[dst.Resolution][name] = append(rollups[dst.Resolution][name],
+					store.RollupAggregates(metricType, aggs[:idx], dst.Resolution)...)
+			}
+			if idx == len(aggs) {
+				delete(rollups[src.Resolution], name)
+				continue
+			}
+			rollups[src.Resolution][name] = append([]store.Aggregate(nil), aggs[idx:]...)
+		}
+	}
+}
+
+// Метод forTenant возвращает хранилище арендатора из контекста,
+// создавая его при первом обращении
+func (s *LocalStorage) forTenant(ctx context.Context) storage {
+	id := tenant.FromContext(ctx)
+	if id == tenant.Default {
+		return s.storage
+	}
+	st, ok := s.tenants[id]
+	if !ok {
+		if s.tenants == nil {
+			s.tenants = make(map[string]storage)
+		}
+		st = newStorage()
+		s.tenants[id] = st
+	}
+	return st
+}
+
+// Метод all возвращает хранилища всех арендаторов
+func (s *LocalStorage) all() []storage {
+	res := make([]storage, 0, len(s.tenants)+1)
+	res = append(res, s.storage)
+	for _, st := range s.tenants {
+		res = append(res, st)
+	}
+	return res
+}
+
+// Тип seriesRef - тип метрики и ключ серии
+type seriesRef struct {
+	metricType string
+	key        string
+}
+
+// Метод checkSeriesLimit проверяет, что добавление серий refs не
+// превысит ограничение числа серий арендатора
+func (s *LocalStorage) checkSeriesLimit(st storage, refs ...seriesRef) error {
+	if s.maxSeries <= 0 {
+		return nil
+	}
+	added := make(map[seriesRef]struct{})
+	for _, ref := range refs {
+		if !st.has(ref.metricType, ref.key) {
+			added[ref] = struct{}{}
+		}
+	}
+	if len(added) > 0 && st.series()+len(added) > s.maxSeries {
+		return store.ErrSeriesLimitExceeded
+	}
+	return nil
+}
+
+// Метод has проверяет, что серия сохранена
+func (s storage) has(metricType, key string) bool {
+	var ok bool
+	switch metricType {
+	case metric.TypeCounter.String():
+		_, ok = s.CounterMetrics[key]
+	case metric.TypeGauge.String():
+		_, ok = s.GaugeMetrics[key]
+	case metric.TypeHistogram.String():
+		_, ok = s.HistogramMetrics[key]
+	}
+	return ok
+}
+
+// Метод series возвращает число сохраненных серий
+func (s storage) series() int {
+	return len(s.CounterMetrics) + len(s.GaugeMetrics) + len(s.HistogramMetrics)
+}
+
+// Метод byType возвращает историю и агрегаты для типа метрики
+func (s storage) byType(metricType string) (
+	map[string][]metric.Sample,
+	map[time.Duration]map[string][]store.Aggregate,
+	bool,
+) {
+	switch metricType {
+	case metric.TypeCounter.String():
+		return s.CounterHistory, s.CounterRollups, true
+	case metric.TypeGauge.String():
+		return s.GaugeHistory, s.GaugeRollups, true
+	default:
+		return nil, nil, false
+	}
+}
+
+// Метод touch сохраняет время последнего обновления серии
+func (s storage) touch(metricType, key string, now time.Time) {
+	if s.Updated[metricType] == nil {
+		s.Updated[metricType] = make(map[string]time.Time)
+	}
+	s.Updated[metricType][key] = now
+}
+
+// Метод updatedAt возвращает время последнего обновления серии
+func (s storage) updatedAt(metricType, key string) *time.Time {
+	t, ok := s.Updated[metricType][key]
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// Метод delete удаляет серию вместе с историей и агрегатами
+func (s storage) delete(metricType, key string) {
+	switch metricType {
+	case metric.TypeCounter.String():
+		delete(s.CounterMetrics, key)
+	case metric.TypeGauge.String():
+		delete(s.GaugeMetrics, key)
+	case metric.TypeHistogram.String():
+		delete(s.HistogramMetrics, key)
+	}
+	delete(s.Updated[metricType], key)
+	history, rollups, ok := s.byType(metricType)
+	if !ok {
+		return
+	}
+	delete(history, key)
+	for _, byName := range rollups {
+		delete(byName, key)
+	}
+}
+
+// Метод keys возвращает ключи серий для типа метрики
+func (s storage) keys(metricType string) ([]string, bool) {
+	var keys []string
+	switch metricType {
+	case metric.TypeCounter.String():
+		for key := range s.CounterMetrics {
+			keys = append(keys, key)
+		}
+	case metric.TypeGauge.String():
+		for key := range s.GaugeMetrics {
+			keys = append(keys, key)
+		}
+	case metric.TypeHistogram.String():
+		for key := range s.HistogramMetrics {
+			keys = append(keys, key)
+		}
+	default:
+		return nil, false
+	}
+	return keys, true
+}
+
+// Функция normalizeKey приводит имя или ключ серии к каноническому виду
+func normalizeKey(name string) (string, error) {
+	id, labels, err := metric.ParseSeriesKey(name)
+	if err != nil {
+		return "", err
+	}
+	return metric.SeriesKey(id, labels), nil
+}
+
+// Функция newMetric формирует метрику для отображения из ключа серии
+func newMetric(key, value string, updatedAt *time.Time) store.Metric {
+	id, labels, err := metric.ParseSeriesKey(key)
+	if err != nil {
+		id = key
+	}
+	return store.Metric{
+		Name:      id,
+		Value:     value,
+		Labels:    labels,
+		UpdatedAt: updatedAt,
+	}
+}
+
+// Метод init создает карты, отсутствующие в файле, записанном до их
+// появления. Серии без времени обновления считаются обновленными в now.
+func (s storage) init(now time.Time) storage {
+	if s.GaugeMetrics == nil {
+		s.GaugeMetrics = make(map[string]metric.Gauge)
+	}
+	if s.CounterMetrics == nil {
+		s.CounterMetrics = make(map[string]metric.Counter)
+	}
+	if s.HistogramMetrics == nil {
+		s.HistogramMetrics = make(map[string]metric.Histogram)
+	}
+	if s.GaugeHistory == nil {
+		s.GaugeHistory = make(map[string][]metric.Sample)
+	}
+	if s.CounterHistory == nil {
+		s.CounterHistory = make(map[string][]metric.Sample)
+	}
+	if s.GaugeRollups == nil {
+		s.GaugeRollups = make(map[time.Duration]map[string][]store.Aggregate)
+	}
+	if s.CounterRollups == nil {
+		s.CounterRollups = make(map[time.Duration]map[string][]store.Aggregate)
+	}
+	if s.Updated == nil {
+		s.Updated = make(map[string]map[string]time.Time)
+	}
+	for _, mt := range []string{
+		metric.TypeGauge.String(),
+		metric.TypeCounter.String(),
+		metric.TypeHistogram.String(),
+	} {
+		keys, _ := s.keys(mt)
+		for _, key := range keys {
+			if s.updatedAt(mt, key) == nil {
+				s.touch(mt, key, now)
+			}
+		}
+	}
+	return s
+}
+
 // Функция инициализация внутреннего типа хранилища
 func newStorage() storage {
 	//share for new metric
 	return storage{
-		GaugeMetrics:   make(map[string]metric.Gauge),
-		CounterMetrics: make(map[string]metric.Counter),
+		GaugeMetrics:     make(map[string]metric.Gauge),
+		CounterMetrics:   make(map[string]metric.Counter),
+		HistogramMetrics: make(map[string]metric.Histogram),
+		GaugeHistory:     make(map[string][]metric.Sample),
+		CounterHistory:   make(map[string][]metric.Sample),
+		GaugeRollups:     make(map[time.Duration]map[string][]store.Aggregate),
+		CounterRollups:   make(map[time.Duration]map[string][]store.Aggregate),
+		Updated:          make(map[string]map[string]time.Time),
 	}
 }
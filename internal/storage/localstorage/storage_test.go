@@ -3,11 +3,14 @@ package localstorage
 import (
 	"context"
 	store "github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/internal/tenant"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/Eqke/metric-collector/pkg/metric"
 	"go.uber.org/zap"
@@ -392,6 +395,10 @@ func TestLocalStorage_GetMetric(t *testing.T) {
 				t.Errorf("GetMetric() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if got.UpdatedAt == nil {
+				t.Errorf("GetMetric() updated at is not set")
+			}
+			got.UpdatedAt = nil
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("GetMetric() got = %v, want %v", got, tt.want)
 			}
@@ -643,3 +650,548 @@ func TestLocalStorage_SetMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalStorage_GetMetricRange(t *testing.T) {
+	base := time.Unix(1700000000, 0).UTC()
+	gauges := []float64{1, 3, 10}
+	counters := []int64{5, 7, 12}
+	history := newStorage()
+	for i := range gauges {
+		ts := base.Add(time.Duration(i) * 20 * time.Second)
+		history.GaugeHistory["gauge"] = append(history.GaugeHistory["gauge"],
+			metric.Sample{Timestamp: ts, Value: &gauges[i]})
+		history.CounterHistory["counter"] = append(history.CounterHistory["counter"],
+			metric.Sample{Timestamp: ts, Delta: &counters[i]})
+	}
+	avg := float64(2)
+	type args struct {
+		m        metric.Metrics
+		from, to time.Time
+		step     time.Duration
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []metric.Sample
+		wantErr bool
+	}{
+		{
+			name: "raw_gauge_samples",
+			args: args{
+				m:    metric.Metrics{ID: "gauge", MType: "gauge"},
+				from: base.Add(10 * time.Second),
+				to:   base.Add(time.Minute),
+			},
+			want: []metric.Sample{
+				{Timestamp: base.Add(20 * time.Second), Value: &gauges[1]},
+				{Timestamp: base.Add(40 * time.Second), Value: &gauges[2]},
+			},
+		},
+		{
+			name: "downsampled_gauge",
+			args: args{
+				m:    metric.Metrics{ID: "gauge", MType: "gauge"},
+				from: base,
+				to:   base.Add(30 * time.Second),
+				step: 40 * time.Second,
+			},
+			want: []metric.Sample{
+				{Timestamp: base, Value: &avg},
+			},
+		},
+		{
+			name: "downsampled_counter",
+			args: args{
+				m:    metric.Metrics{ID: "counter", MType: "counter"},
+				from: base,
+				to:   base.Add(time.Minute),
+				step: 40 * time.Second,
+			},
+			want: []metric.Sample{
+				{Timestamp: base, Delta: &counters[1]},
+				{Timestamp: base.Add(40 * time.Second), Delta: &counters[2]},
+			},
+		},
+		{
+			name: "unknown_metric",
+			args: args{
+				m:    metric.Metrics{ID: "unknown", MType: "gauge"},
+				from: base,
+				to:   base.Add(time.Minute),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid_range",
+			args: args{
+				m:    metric.Metrics{ID: "gauge", MType: "gauge"},
+				from: base.Add(time.Minute),
+				to:   base,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &LocalStorage{
+				logger:  zaptest.NewLogger(t).Sugar(),
+				mu:      &sync.Mutex{},
+				storage: history,
+			}
+			got, err := s.GetMetricRange(context.Background(), tt.args.m, tt.args.from, tt.args.to, tt.args.step)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMetricRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetMetricRange() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalStorage_Compact(t *testing.T) {
+	now := time.Unix(1700000000, 0).UTC()
+	tiers := []store.RetentionTier{
+		{Resolution: 0, Retention: time.Hour},
+		{Resolution: time.Minute, Retention: 24 * time.Hour},
+	}
+	s := New(zaptest.NewLogger(t).Sugar())
+	// Два значения в одном минутном интервале старше часа и одно свежее
+	gauges := []float64{2, 4, 8}
+	counters := []int64{10, 15, 3}
+	times := []time.Time{
+		now.Add(-2 * time.Hour),
+		now.Add(-2*time.Hour + 30*time.Second),
+		now.Add(-time.Minute),
+	}
+	for i := range times {
+		s.storage.GaugeHistory["gauge"] = append(s.storage.GaugeHistory["gauge"],
+			metric.Sample{Timestamp: times[i], Value: &gauges[i]})
+		s.storage.CounterHistory["counter"] = append(s.storage.CounterHistory["counter"],
+			metric.Sample{Timestamp: times[i], Delta: &counters[i]})
+	}
+
+	err := s.Compact(context.Background(), tiers, now)
+	require.NoError(t, err)
+
+	require.Len(t, s.storage.GaugeHistory["gauge"], 1)
+	gaugeAggs := s.storage.GaugeRollups[time.Minute]["gauge"]
+	require.Len(t, gaugeAggs, 1)
+	require.Equal(t, store.Aggregate{
+		Timestamp: store.AlignTime(times[0], time.Minute),
+		Count:     2, Min: 2, Max: 4, Sum: 6, Last: 4,
+	}, gaugeAggs[0])
+
+	counterAggs := s.storage.CounterRollups[time.Minute]["counter"]
+	require.Len(t, counterAggs, 1)
+	require.Equal(t, float64(5), counterAggs[0].Sum)
+	require.Equal(t, float64(15), counterAggs[0].Last)
+
+	// Агрегаты участвуют в чтении истории
+	got, err := s.GetMetricRange(context.Background(),
+		metric.Metrics{ID: "gauge", MType: "gauge"}, now.Add(-3*time.Hour), now, 0)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, float64(3), *got[0].Value)
+
+	// После истечения срока хранения последнего уровня агрегаты удаляются
+	err = s.Compact(context.Background(), tiers, now.Add(48*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, s.storage.GaugeRollups[time.Minute]["gauge"])
+	require.Empty(t, s.storage.GaugeHistory["gauge"])
+
+	err = s.Compact(context.Background(), nil, now)
+	require.Error(t, err)
+}
+
+func TestLocalStorage_SetHistogram(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	s := New(l)
+	ctx := context.Background()
+
+	h := metric.NewHistogram([]float64{1, 2, 4})
+	h.Observe(0.5)
+	h.Observe(3)
+	m := metric.Metrics{ID: "latency", MType: "histogram", Histogram: h}
+
+	require.NoError(t, s.SetMetric(ctx, m))
+	require.NoError(t, s.SetMetric(ctx, m))
+
+	got, err := s.GetMetric(ctx, metric.Metrics{ID: "latency", MType: "histogram"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), got.Histogram.Count)
+	require.Equal(t, []uint64{2, 0, 2, 0}, got.Histogram.Counts)
+	require.Equal(t, float64(7), got.Histogram.Sum)
+
+	other := metric.NewHistogram([]float64{1, 2})
+	err = s.SetMetric(ctx, metric.Metrics{ID: "latency", MType: "histogram", Histogram: other})
+	require.ErrorIs(t, err, metric.ErrBucketsMismatch)
+
+	err = s.SetMetric(ctx, metric.Metrics{ID: "latency", MType: "histogram"})
+	require.ErrorIs(t, err, store.ErrValueIsEmpty)
+}
+
+func TestLocalStorage_Labels(t *testing.T) {
+	l := zaptest.NewLogger(t).Sugar()
+	s := New(l)
+	ctx := context.Background()
+
+	web1, web2, plain := float64(1), float64(2), float64(3)
+	require.NoError(t, s.SetMetrics(ctx, []metric.Metrics{
+		{ID: "Alloc", MType: "gauge", Value: &web1, Labels: map[string]string{"host": "web1", "zone": "eu"}},
+		{ID: "Alloc", MType: "gauge", Value: &web2, Labels: map[string]string{"host": "web2", "zone": "us"}},
+		{ID: "Alloc", MType: "gauge", Value: &plain},
+	}))
+	require.NoError(t, s.SetValue(ctx, "counter", `PollCount{zone="eu",host="web1"}`, "5"))
+
+	t.Run("series_are_independent", func(t *testing.T) {
+		got, err := s.GetMetric(ctx, metric.Metrics{ID: "Alloc", MType: "gauge", Labels: map[string]string{"zone": "eu", "host": "web1"}})
+		require.NoError(t, err)
+		require.Equal(t, web1, *got.Value)
+
+		v, err := s.GetValue(ctx, "gauge", "Alloc")
+		require.NoError(t, err)
+		require.Equal(t, "3", v)
+
+		v, err = s.GetValue(ctx, "counter", `PollCount{host="web1",zone="eu"}`)
+		require.NoError(t, err)
+		require.Equal(t, "5", v)
+	})
+
+	t.Run("find_by_matchers", func(t *testing.T) {
+		matchers, err := metric.ParseLabelMatchers([]string{"host=~web.*", "zone!=us"})
+		require.NoError(t, err)
+		got, err := s.FindMetrics(ctx, metric.Metrics{ID: "Alloc", MType: "gauge"}, matchers)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, map[string]string{"host": "web1", "zone": "eu"}, got[0].Labels)
+
+		got, err = s.FindMetrics(ctx, metric.Metrics{ID: "Alloc", MType: "gauge"}, nil)
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+	})
+
+	t.Run("invalid_label", func(t *testing.T) {
+		err := s.SetMetric(ctx, metric.Metrics{ID: "Alloc", MType: "gauge", Value: &plain, Labels: map[string]string{"a=b": "c"}})
+		require.ErrorIs(t, err, metric.ErrInvalidLabel)
+	})
+}
+
+func TestLocalStorage_SetMetricWithTimestamp(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	ctx := context.Background()
+	base := time.Unix(1700000000, 0).UTC()
+
+	values := []float64{1, 2, 3}
+	offsets := []time.Duration{20 * time.Second, 0, 10 * time.Second}
+	for i := range values {
+		ts := base.Add(offsets[i])
+		require.NoError(t, s.SetMetric(ctx, metric.Metrics{ID: "gauge", MType: "gauge", Value: &values[i], Timestamp: &ts}))
+	}
+
+	got, err := s.GetMetricRange(ctx, metric.Metrics{ID: "gauge", MType: "gauge"}, base, base.Add(time.Minute), 0)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	for i, want := range []float64{2, 3, 1} {
+		require.Equal(t, base.Add(time.Duration(i)*10*time.Second), got[i].Timestamp)
+		require.Equal(t, want, *got[i].Value)
+	}
+}
+
+func TestLocalStorage_Query(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	ctx := context.Background()
+
+	heapWeb1, heapWeb2, heapDB, alloc := float64(10), float64(30), float64(20), float64(100)
+	require.NoError(t, s.SetMetrics(ctx, []metric.Metrics{
+		{ID: "HeapAlloc", MType: "gauge", Value: &heapWeb1, Labels: map[string]string{"host": "web1"}},
+		{ID: "HeapInuse", MType: "gauge", Value: &heapWeb2, Labels: map[string]string{"host": "web2"}},
+		{ID: "HeapAlloc", MType: "gauge", Value: &heapDB, Labels: map[string]string{"host": "db1"}},
+		{ID: "Alloc", MType: "gauge", Value: &alloc},
+	}))
+	web, err := metric.ParseLabelMatchers([]string{"host=~web.*"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		query store.Query
+		want  []store.QueryResult
+	}{
+		{
+			name:  "sum_glob",
+			query: store.Query{MType: "gauge", Name: "Heap*", Aggregation: store.AggregationSum},
+			want:  []store.QueryResult{{Value: 60}},
+		},
+		{
+			name:  "avg_matchers",
+			query: store.Query{MType: "gauge", Name: "Heap*", Matchers: web, Aggregation: store.AggregationAvg},
+			want:  []store.QueryResult{{Value: 20}},
+		},
+		{
+			name:  "min_regexp",
+			query: store.Query{MType: "gauge", Name: "Heap(Alloc|Sys)", Regexp: true, Aggregation: store.AggregationMin},
+			want:  []store.QueryResult{{Value: 10}},
+		},
+		{
+			name:  "count",
+			query: store.Query{MType: "gauge", Name: "*Alloc", Aggregation: store.AggregationCount},
+			want:  []store.QueryResult{{Value: 3}},
+		},
+		{
+			name:  "topk",
+			query: store.Query{MType: "gauge", Name: "Heap?????", Aggregation: store.AggregationTopK, K: 2},
+			want: []store.QueryResult{
+				{ID: "HeapInuse", Labels: map[string]string{"host": "web2"}, Value: 30},
+				{ID: "HeapAlloc", Labels: map[string]string{"host": "db1"}, Value: 20},
+			},
+		},
+		{
+			name:  "max_no_series",
+			query: store.Query{MType: "counter", Name: "Heap*", Aggregation: store.AggregationMax},
+			want:  []store.QueryResult{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Query(ctx, tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = s.Query(ctx, store.Query{MType: "histogram", Name: "Heap*", Aggregation: store.AggregationSum})
+	require.Error(t, err)
+}
+
+func TestLocalStorage_Delete(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	ctx := context.Background()
+
+	v := float64(1)
+	require.NoError(t, s.SetMetrics(ctx, []metric.Metrics{
+		{ID: "CPUutilization7", MType: "gauge", Value: &v, Labels: map[string]string{"host": "web1"}},
+		{ID: "CPUutilization7", MType: "gauge", Value: &v, Labels: map[string]string{"host": "web2"}},
+		{ID: "CPUutilization1", MType: "gauge", Value: &v},
+		{ID: "Alloc", MType: "gauge", Value: &v},
+	}))
+	require.NoError(t, s.SetValue(ctx, "counter", "CPUticks", "5"))
+
+	matchers, err := metric.ParseLabelMatchers([]string{"host=web1"})
+	require.NoError(t, err)
+	deleted, err := s.DeleteMetric(ctx, metric.Metrics{ID: "CPUutilization7", MType: "gauge"}, matchers)
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	got, err := s.FindMetrics(ctx, metric.Metrics{ID: "CPUutilization7", MType: "gauge"}, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, "web2", got[0].Labels["host"])
+
+	// История удаленной серии также удаляется
+	samples, err := s.GetMetricRange(ctx, metric.Metrics{ID: "CPUutilization7", MType: "gauge",
+		Labels: map[string]string{"host": "web1"}}, time.Unix(0, 0), time.Now().Add(time.Minute), 0)
+	require.Error(t, err)
+	require.Empty(t, samples)
+
+	deleted, err = s.DeleteByPrefix(ctx, "", "CPU")
+	require.NoError(t, err)
+	require.Equal(t, 3, deleted)
+	metrics, err := s.GetMetrics(ctx)
+	require.NoError(t, err)
+	require.Len(t, metrics["gauge"], 1)
+	require.Empty(t, metrics["counter"])
+
+	_, err = s.DeleteByPrefix(ctx, "", "")
+	require.Error(t, err)
+	_, err = s.DeleteMetric(ctx, metric.Metrics{ID: "Alloc", MType: "summary"}, nil)
+	require.Error(t, err)
+}
+
+func TestLocalStorage_ResetCounter(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	ctx := context.Background()
+
+	require.NoError(t, s.SetValue(ctx, "counter", "PollCount", "5"))
+	require.NoError(t, s.ResetCounter(ctx, metric.Metrics{ID: "PollCount"}))
+	v, err := s.GetValue(ctx, "counter", "PollCount")
+	require.NoError(t, err)
+	require.Equal(t, "0", v)
+
+	samples, err := s.GetMetricRange(ctx, metric.Metrics{ID: "PollCount", MType: "counter"},
+		time.Unix(0, 0), time.Now().Add(time.Minute), 0)
+	require.NoError(t, err)
+	require.Len(t, samples, 2)
+	require.Equal(t, int64(0), *samples[1].Delta)
+
+	err = s.ResetCounter(ctx, metric.Metrics{ID: "Unknown"})
+	require.Error(t, err)
+}
+
+func TestLocalStorage_DeleteStale(t *testing.T) {
+	ctx := context.Background()
+	s := New(zaptest.NewLogger(t).Sugar())
+	require.NoError(t, s.SetValue(ctx, "gauge", "Alloc", "1.5"))
+	require.NoError(t, s.SetValue(ctx, "counter", `PollCount{host="web1"}`, "5"))
+	before := time.Now().UTC()
+	time.Sleep(time.Millisecond)
+	require.NoError(t, s.SetValue(ctx, "counter", `PollCount{host="web2"}`, "5"))
+
+	metrics, err := s.GetMetrics(ctx)
+	require.NoError(t, err)
+	for _, m := range metrics["counter"] {
+		require.NotNil(t, m.UpdatedAt)
+	}
+
+	deleted, err := s.DeleteStale(ctx, before)
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	series, err := s.FindMetrics(ctx, metric.Metrics{ID: "PollCount", MType: "counter"}, nil)
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	require.Equal(t, map[string]string{"host": "web2"}, series[0].Labels)
+	require.NotNil(t, series[0].UpdatedAt)
+	require.False(t, series[0].UpdatedAt.Before(before))
+}
+
+func TestLocalStorage_Tenants(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	ctxA := tenant.WithTenant(context.Background(), "team-a")
+	ctxB := tenant.WithTenant(context.Background(), "team-b")
+	require.NoError(t, s.SetValue(ctxA, "counter", "PollCount", "5"))
+	require.NoError(t, s.SetValue(ctxB, "counter", "PollCount", "7"))
+
+	v, err := s.GetValue(ctxA, "counter", "PollCount")
+	require.NoError(t, err)
+	require.Equal(t, "5", v)
+	v, err = s.GetValue(ctxB, "counter", "PollCount")
+	require.NoError(t, err)
+	require.Equal(t, "7", v)
+	_, err = s.GetValue(context.Background(), "counter", "PollCount")
+	require.ErrorIs(t, err, store.ErrIsMetricDoesntExist)
+
+	deleted, err := s.DeleteByPrefix(ctxA, "", "Poll")
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+	_, err = s.GetValue(ctxB, "counter", "PollCount")
+	require.NoError(t, err)
+
+	// Метрики арендаторов сохраняются в файл и восстанавливаются
+	data, err := s.ToJSON(context.Background())
+	require.NoError(t, err)
+	restored := New(zaptest.NewLogger(t).Sugar())
+	require.NoError(t, restored.FromJSON(context.Background(), data))
+	v, err = restored.GetValue(ctxB, "counter", "PollCount")
+	require.NoError(t, err)
+	require.Equal(t, "7", v)
+}
+
+func TestLocalStorage_SeriesLimit(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	s.SetSeriesLimit(2)
+	ctx := tenant.WithTenant(context.Background(), "team-a")
+	require.NoError(t, s.SetValue(ctx, "gauge", "Alloc", "1"))
+	require.NoError(t, s.SetValue(ctx, "gauge", `Alloc{host="web1"}`, "1"))
+	// Обновление существующей серии не ограничивается
+	require.NoError(t, s.SetValue(ctx, "gauge", "Alloc", "2"))
+	require.ErrorIs(t, s.SetValue(ctx, "gauge", "Frees", "1"), store.ErrSeriesLimitExceeded)
+
+	// Пакет, превышающий ограничение, не применяется
+	delta := int64(1)
+	err := s.SetMetrics(ctx, []metric.Metrics{
+		{ID: "PollCount", MType: "counter", Delta: &delta},
+		{ID: "Frees", MType: "gauge", Value: new(float64)},
+	})
+	require.ErrorIs(t, err, store.ErrSeriesLimitExceeded)
+	_, err = s.GetValue(ctx, "counter", "PollCount")
+	require.Error(t, err)
+
+	// Ограничение действует для каждого арендатора отдельно
+	require.NoError(t, s.SetValue(context.Background(), "gauge", "Frees", "1"))
+}
+
+func TestLocalStorage_BatchID(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	delta := int64(3)
+	batch := []metric.Metrics{{ID: "PollCount", MType: "counter", Delta: &delta}}
+	ctx := store.WithBatchID(context.Background(), "agent-1:1")
+	require.NoError(t, s.SetMetrics(ctx, batch))
+	// Повтор пакета не применяется
+	require.ErrorIs(t, s.SetMetrics(ctx, batch), store.ErrBatchApplied)
+	// Пакеты без идентификатора и пакеты других арендаторов применяются
+	require.NoError(t, s.SetMetrics(context.Background(), batch))
+	require.NoError(t, s.SetMetrics(tenant.WithTenant(ctx, "team-a"), batch))
+	v, err := s.GetValue(context.Background(), "counter", "PollCount")
+	require.NoError(t, err)
+	require.Equal(t, "6", v)
+
+	// Неприменившийся пакет можно повторить
+	failed := store.WithBatchID(context.Background(), "agent-1:2")
+	require.Error(t, s.SetMetrics(failed, []metric.Metrics{{ID: "PollCount", MType: "counter"}}))
+	require.NoError(t, s.SetMetrics(failed, batch))
+
+	// После истечения срока хранения идентификатор забывается
+	s.SetBatchIDTTL(0)
+	require.NoError(t, s.SetMetrics(ctx, batch))
+	require.Len(t, s.batches, 1)
+}
+
+func TestLocalStorage_SetMetricsAtomic(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	delta := int64(1)
+	ctx := store.WithBatchID(context.Background(), "agent-1:1")
+	// Некорректная метрика в конце пакета отменяет весь пакет
+	err := s.SetMetrics(ctx, []metric.Metrics{
+		{ID: "PollCount", MType: "counter", Delta: &delta},
+		{ID: "Alloc", MType: "gauge"},
+	})
+	require.ErrorIs(t, err, store.ErrValueIsEmpty)
+	_, err = s.GetValue(ctx, "counter", "PollCount")
+	require.ErrorIs(t, err, store.ErrIsMetricDoesntExist)
+	require.Empty(t, s.storage.CounterHistory)
+	require.Empty(t, s.batches)
+
+	// Гистограммы пакета с разными границами корзин не сливаются
+	h1 := metric.NewHistogram([]float64{1})
+	h2 := metric.NewHistogram([]float64{1, 2})
+	err = s.SetMetrics(ctx, []metric.Metrics{
+		{ID: "PollCount", MType: "counter", Delta: &delta},
+		{ID: "Latency", MType: "histogram", Histogram: h1},
+		{ID: "Latency", MType: "histogram", Histogram: h2},
+	})
+	require.ErrorIs(t, err, metric.ErrBucketsMismatch)
+	require.Empty(t, s.storage.CounterMetrics)
+	require.Empty(t, s.storage.HistogramMetrics)
+
+	// Исправленный пакет с тем же идентификатором применяется
+	require.NoError(t, s.SetMetrics(ctx, []metric.Metrics{
+		{ID: "PollCount", MType: "counter", Delta: &delta},
+		{ID: "Latency", MType: "histogram", Histogram: h1},
+	}))
+	v, err := s.GetValue(ctx, "counter", "PollCount")
+	require.NoError(t, err)
+	require.Equal(t, "1", v)
+}
+
+func TestLocalStorage_CounterHistoryOrder(t *testing.T) {
+	s := New(zaptest.NewLogger(t).Sugar())
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour).UTC()
+
+	// Значения с временем клиента приходят не по порядку
+	deltas := []int64{1, 2, 4}
+	offsets := []time.Duration{20 * time.Second, 0, 10 * time.Second}
+	for i := range deltas {
+		ts := base.Add(offsets[i])
+		require.NoError(t, s.SetMetric(ctx, metric.Metrics{ID: "PollCount", MType: "counter", Delta: &deltas[i], Timestamp: &ts}))
+	}
+
+	got, err := s.GetMetricRange(ctx, metric.Metrics{ID: "PollCount", MType: "counter"},
+		base, time.Now().Add(time.Minute), 0)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	for i, want := range []int64{1, 3, 7} {
+		require.Equal(t, want, *got[i].Delta)
+		require.True(t, got[i].Timestamp.After(base.Add(time.Minute)))
+	}
+}
@@ -3,15 +3,19 @@ package storage
 
 import (
 	"context"
+	"time"
+
 	"github.com/Eqke/metric-collector/pkg/metric"
 )
 
-// Интерфейс хранилища
+// Интерфейс хранилища. Операции с метриками выполняются от имени
+// арендатора из контекста (tenant.FromContext), каждый арендатор видит
+// только свои метрики. Compact и DeleteStale обслуживают всех арендаторов.
 type Storage interface {
 	// Метод SetValue добавляет или обновляет значение метрики.
 	// Получает на вход(порядок соответствует):
 	// metricType - тип метрики,
-	// name - имя метрики,
+	// name - имя метрики или ключ серии metric.SeriesKey,
 	// value - значение метрики.
 	SetValue(context.Context, string, string, string) error
 
@@ -21,13 +25,15 @@ type Storage interface {
 	SetMetric(context.Context, metric.Metrics) error
 
 	// Метод SetMetrics добавляет или обновляет массив метрик
-	// Получает на вход массив metric.Metrics.
+	// Получает на вход массив metric.Metrics. Пакет с идентификатором
+	// из контекста (WithBatchID), который уже был применен, повторно не
+	// применяется: возвращается ErrBatchApplied.
 	SetMetrics(context.Context, []metric.Metrics) error
 
 	// Метод GetValue позволяет получить значение метрики.
 	// Получает на вход:
 	// metricType - тип метрики,
-	// name - имя метрики.
+	// name - имя метрики или ключ серии metric.SeriesKey.
 	GetValue(context.Context, string, string) (string, error)
 
 	// Метод GetMetric полвзоялет получить экземлпяр metric.Metric.
@@ -38,6 +44,58 @@ type Storage interface {
 	// Метод GetMetrics позволяет получить карту метрик
 	GetMetrics(context.Context) (map[string][]Metric, error)
 
+	// Метод FindMetrics позволяет получить все серии метрики.
+	// Получает на вход:
+	// m - экземпляр metric.Metrics, определяющий тип и имя метрики,
+	// matchers - условия, которым должны удовлетворять метки серии.
+	FindMetrics(context.Context, metric.Metrics, []*metric.LabelMatcher) ([]metric.Metrics, error)
+
+	// Метод Query вычисляет агрегацию по сериям, имя которых
+	// соответствует шаблону, а метки - условиям запроса.
+	// Получает на вход экземпляр Query.
+	Query(context.Context, Query) ([]QueryResult, error)
+
+	// Метод GetMetricRange позволяет получить историю значений метрики.
+	// Получает на вход:
+	// m - экземпляр metric.Metrics, определяющий тип и имя метрики,
+	// from, to - границы периода включительно,
+	// step - длина интервала агрегации, при нулевом значении
+	// возвращаются все сохраненные значения.
+	GetMetricRange(context.Context, metric.Metrics, time.Time, time.Time, time.Duration) ([]metric.Sample, error)
+
+	// Метод Compact применяет политику хранения: значения старше срока
+	// хранения уровня агрегируются на следующий уровень, а значения
+	// старше срока хранения последнего уровня удаляются.
+	// Получает на вход уровни хранения, первый из которых описывает
+	// исходные значения, и текущее время.
+	Compact(context.Context, []RetentionTier, time.Time) error
+
+	// Метод DeleteMetric удаляет серии метрики вместе с историей значений.
+	// Получает на вход:
+	// m - экземпляр metric.Metrics, определяющий тип и имя метрики,
+	// matchers - условия, которым должны удовлетворять метки серий.
+	// Возвращает число удаленных серий.
+	DeleteMetric(context.Context, metric.Metrics, []*metric.LabelMatcher) (int, error)
+
+	// Метод DeleteByPrefix удаляет серии всех метрик, имя которых
+	// начинается с префикса, вместе с историей значений.
+	// Получает на вход:
+	// metricType - тип метрики, пустое значение означает все типы,
+	// prefix - непустой префикс имени.
+	// Возвращает число удаленных серий.
+	DeleteByPrefix(context.Context, string, string) (int, error)
+
+	// Метод ResetCounter обнуляет серию counter. Обнуление сохраняется
+	// в историю и учитывается при вычислении скорости как отрицательный
+	// прирост.
+	// Получает на вход экземпляр metric.Metrics с именем и метками серии.
+	ResetCounter(context.Context, metric.Metrics) error
+
+	// Метод DeleteStale удаляет серии всех типов, которые не обновлялись
+	// с момента before, вместе с историей значений.
+	// Возвращает число удаленных серий.
+	DeleteStale(context.Context, time.Time) (int, error)
+
 	// Метод ToJSON используется для сериализации
 	ToJSON(context.Context) ([]byte, error)
 
@@ -63,6 +121,9 @@ type Storage interface {
 // Тип Metric нужен используется для дальнейшего отображения
 // в строковом формате
 type Metric struct {
-	Name  string
-	Value string
+	Name      string
+	Value     string
+	Labels    map[string]string `json:",omitempty"`
+	UpdatedAt *time.Time        `json:",omitempty"`
+	Stale     bool              `json:",omitempty"`
 }
@@ -4,8 +4,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	store "github.com/Eqke/metric-collector/internal/storage"
+	"github.com/Eqke/metric-collector/internal/tenant"
 	"github.com/Eqke/metric-collector/pkg/metric"
 	"github.com/Eqke/metric-collector/utils/retry"
 	"github.com/jackc/pgx/v5"
@@ -17,29 +22,174 @@ const (
 	// Тип хранилища
 	TYPE = "PostgresSQL database"
 
-	// Запросы на создание таблиц
-	queryCreateGauges   = `CREATE TABLE IF NOT EXISTS gauges(name text primary key, value double precision)`
-	queryCreateCounters = `CREATE TABLE IF NOT EXISTS counters(name text primary key, value bigint)`
+	// Запросы для пакетов метрик: пакет занимается вставкой строки в
+	// транзакции, в которой он применяется. Параллельная вставка того же
+	// пакета ждет завершения транзакции.
+	queryExpireBatches = `DELETE FROM batches WHERE applied_at < now() - make_interval(secs => $1)`
+	queryClaimBatch    = `INSERT INTO batches(tenant, id) VALUES($1, $2) ON CONFLICT(tenant, id) DO NOTHING`
 
-	// Запросы для gauge
-	queryGetGauge    = `SELECT value FROM gauges WHERE name = $1 LIMIT 1`
-	queryGetAllGauge = `SELECT name, value FROM gauges`
-	querySetGauge    = `INSERT INTO gauges(name, value) VALUES($1, $2) ON CONFLICT(name) DO UPDATE SET value = $2`
+	// Запросы для gauge. Первым параметром всех запросов к сериям
+	// передается арендатор
+	queryGetGauge    = `SELECT value FROM gauges WHERE tenant = $1 AND name = $2 AND labels = $3 LIMIT 1`
+	queryGetAllGauge = `SELECT name, labels, value, updated_at FROM gauges WHERE tenant = $1`
+	queryFindGauge   = `SELECT labels, updated_at, value FROM gauges WHERE tenant = $1 AND name = $2`
+	querySetGauge    = `WITH upd AS (INSERT INTO gauges(tenant, name, labels, value) VALUES($1, $2, $3, $4) ON CONFLICT(tenant, name, labels) DO UPDATE SET value = EXCLUDED.value, updated_at = now() RETURNING tenant, name, labels, value)
+		INSERT INTO gauge_samples(tenant, name, labels, ts, value) SELECT tenant, name, labels, coalesce($5::timestamptz, now()), value FROM upd`
 
-	// Запросы для counter
-	queryGetCounter    = `SELECT value FROM counters WHERE name = $1 LIMIT 1`
-	queryGetAllCounter = `SELECT name, value FROM counters`
-	querySetCounter    = `INSERT INTO counters(name, value) VALUES($1, $2) ON CONFLICT(name) DO UPDATE SET value = counters.value + EXCLUDED.value`
+	// Запрос значения gauge вместе со временем последнего обновления
+	queryGetGaugeMetric = `SELECT value, updated_at FROM gauges WHERE tenant = $1 AND name = $2 AND labels = $3 LIMIT 1`
+
+	// Запросы существования серии: пустая история серии, которой нет,
+	// отличается от пустой истории за период
+	queryGaugeExists   = `SELECT EXISTS (SELECT 1 FROM gauges WHERE tenant = $1 AND name = $2 AND labels = $3)`
+	queryCounterExists = `SELECT EXISTS (SELECT 1 FROM counters WHERE tenant = $1 AND name = $2 AND labels = $3)`
+
+	queryGetGaugeRange = `SELECT ts, value FROM (
+			SELECT ts, value FROM gauge_samples WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+			UNION ALL
+			SELECT ts, sum / count FROM gauge_rollups WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+		) s ORDER BY ts`
+	queryGetGaugeRangeStep = `SELECT to_timestamp(floor(extract(epoch FROM ts) / $6::float8) * $6::float8) AS bucket, avg(value) FROM (
+			SELECT ts, value FROM gauge_samples WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+			UNION ALL
+			SELECT ts, sum / count FROM gauge_rollups WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+		) s GROUP BY bucket ORDER BY bucket`
+
+	// Запросы для counter. Накопленное значение сохраняется в историю
+	// со временем записи, чтобы история оставалась монотонной
+	queryGetCounter    = `SELECT value FROM counters WHERE tenant = $1 AND name = $2 AND labels = $3 LIMIT 1`
+	queryGetAllCounter = `SELECT name, labels, value, updated_at FROM counters WHERE tenant = $1`
+	queryFindCounter   = `SELECT labels, updated_at, value FROM counters WHERE tenant = $1 AND name = $2`
+	querySetCounter    = `WITH upd AS (INSERT INTO counters(tenant, name, labels, value) VALUES($1, $2, $3, $4) ON CONFLICT(tenant, name, labels) DO UPDATE SET value = counters.value + EXCLUDED.value, updated_at = now() RETURNING tenant, name, labels, value)
+		INSERT INTO counter_samples(tenant, name, labels, ts, value) SELECT tenant, name, labels, clock_timestamp(), value FROM upd`
+
+	// Запрос значения counter вместе со временем последнего обновления
+	queryGetCounterMetric = `SELECT value, updated_at FROM counters WHERE tenant = $1 AND name = $2 AND labels = $3 LIMIT 1`
+
+	queryGetCounterRange = `SELECT ts, value FROM (
+			SELECT ts, value FROM counter_samples WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+			UNION ALL
+			SELECT ts, last FROM counter_rollups WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+		) s ORDER BY ts`
+	queryGetCounterRangeStep = `SELECT to_timestamp(floor(extract(epoch FROM ts) / $6::float8) * $6::float8) AS bucket, (array_agg(value ORDER BY ts DESC))[1] FROM (
+			SELECT ts, value FROM counter_samples WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+			UNION ALL
+			SELECT ts, last FROM counter_rollups WHERE tenant = $1 AND name = $2 AND labels = $3 AND ts BETWEEN $4 AND $5
+		) s GROUP BY bucket ORDER BY bucket`
+
+	// Запрос обнуления counter, обнуление сохраняется в историю
+	queryResetCounter = `WITH upd AS (UPDATE counters SET value = 0, updated_at = now() WHERE tenant = $1 AND name = $2 AND labels = $3 RETURNING tenant, name, labels, value)
+		INSERT INTO counter_samples(tenant, name, labels, ts, value) SELECT tenant, name, labels, now(), value FROM upd`
+
+	// Запросы для histogram. Счетчики корзин складываются поэлементно,
+	// строка не обновляется, если границы корзин не совпадают
+	queryGetHistogram    = `SELECT updated_at, bounds, counts, sum, count FROM histograms WHERE tenant = $1 AND name = $2 AND labels = $3 LIMIT 1`
+	queryGetAllHistogram = `SELECT name, labels, updated_at, bounds, counts, sum, count FROM histograms WHERE tenant = $1`
+	queryFindHistogram   = `SELECT labels, updated_at, bounds, counts, sum, count FROM histograms WHERE tenant = $1 AND name = $2`
+	querySetHistogram    = `INSERT INTO histograms(tenant, name, labels, bounds, counts, sum, count) VALUES($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT(tenant, name, labels) DO UPDATE SET
+			counts = (SELECT array_agg(a + b ORDER BY i) FROM unnest(histograms.counts, EXCLUDED.counts) WITH ORDINALITY t(a, b, i)),
+			sum = histograms.sum + EXCLUDED.sum,
+			count = histograms.count + EXCLUDED.count,
+			updated_at = now()
+		WHERE histograms.bounds = EXCLUDED.bounds`
+
+	// Запрос проверки ограничения числа серий: существует ли серия и
+	// сколько серий сохранено у арендатора
+	querySeriesCount = `SELECT EXISTS(SELECT 1 FROM %s WHERE tenant = $1 AND name = $2 AND labels = $3),
+		(SELECT count(*) FROM gauges WHERE tenant = $1) + (SELECT count(*) FROM counters WHERE tenant = $1) + (SELECT count(*) FROM histograms WHERE tenant = $1)`
 )
 
+// Тип compactQueries содержит запросы применения политики хранения
+// для одного типа метрик
+type compactQueries struct {
+	// $1 - граница, $2 - разрешение уровня назначения
+	rollupSamples string
+	// $1 - граница, $2 - разрешение уровня назначения, $3 - исходного уровня
+	rollupAggregates string
+	// $1 - граница
+	expireSamples string
+	// $1 - граница, $2 - разрешение уровня
+	expireRollups string
+}
+
+// Перечень запросов применения политики хранения по типам метрик
+var compactQueriesByType = map[string]compactQueries{
+	metric.TypeGauge.String(): {
+		rollupSamples: `WITH moved AS (DELETE FROM gauge_samples WHERE ts < $1 RETURNING tenant, name, labels, ts, value)
+			INSERT INTO gauge_rollups(tenant, name, labels, resolution, ts, count, min, max, sum, last)
+			SELECT tenant, name, labels, $2::bigint, to_timestamp(floor(extract(epoch FROM ts) / $2::bigint) * $2::bigint) AS bucket,
+				count(*), min(value), max(value), sum(value), (array_agg(value ORDER BY ts DESC))[1]
+			FROM moved GROUP BY tenant, name, labels, bucket
+			ON CONFLICT(tenant, name, labels, resolution, ts) DO UPDATE SET
+				count = gauge_rollups.count + EXCLUDED.count,
+				min = least(gauge_rollups.min, EXCLUDED.min),
+				max = greatest(gauge_rollups.max, EXCLUDED.max),
+				sum = gauge_rollups.sum + EXCLUDED.sum,
+				last = EXCLUDED.last`,
+		rollupAggregates: `WITH moved AS (DELETE FROM gauge_rollups WHERE resolution = $3 AND ts < $1 RETURNING tenant, name, labels, ts, count, min, max, sum, last)
+			INSERT INTO gauge_rollups(tenant, name, labels, resolution, ts, count, min, max, sum, last)
+			SELECT tenant, name, labels, $2::bigint, to_timestamp(floor(extract(epoch FROM ts) / $2::bigint) * $2::bigint) AS bucket,
+				sum(count), min(min), max(max), sum(sum), (array_agg(last ORDER BY ts DESC))[1]
+			FROM moved GROUP BY tenant, name, labels, bucket
+			ON CONFLICT(tenant, name, labels, resolution, ts) DO UPDATE SET
+				count = gauge_rollups.count + EXCLUDED.count,
+				min = least(gauge_rollups.min, EXCLUDED.min),
+				max = greatest(gauge_rollups.max, EXCLUDED.max),
+				sum = gauge_rollups.sum + EXCLUDED.sum,
+				last = EXCLUDED.last`,
+		expireSamples: `DELETE FROM gauge_samples WHERE ts < $1`,
+		expireRollups: `DELETE FROM gauge_rollups WHERE ts < $1 AND resolution = $2`,
+	},
+	metric.TypeCounter.String(): {
+		rollupSamples: `WITH moved AS (DELETE FROM counter_samples WHERE ts < $1 RETURNING tenant, name, labels, ts, value),
+			inc AS (
+				SELECT tenant, name, labels, ts, value, coalesce(
+					lag(value) OVER (PARTITION BY tenant, name, labels ORDER BY ts),
+					(SELECT r.last FROM counter_rollups r WHERE r.tenant = moved.tenant AND r.name = moved.name AND r.labels = moved.labels AND r.resolution = $2::bigint ORDER BY r.ts DESC LIMIT 1),
+					value) AS prev
+				FROM moved
+			)
+			INSERT INTO counter_rollups(tenant, name, labels, resolution, ts, count, sum, last, rate)
+			SELECT tenant, name, labels, $2::bigint, to_timestamp(floor(extract(epoch FROM ts) / $2::bigint) * $2::bigint) AS bucket,
+				count(*), sum(value - prev),
+				(array_agg(value ORDER BY ts DESC))[1],
+				sum(value - prev)::double precision / $2::bigint
+			FROM inc GROUP BY tenant, name, labels, bucket
+			ON CONFLICT(tenant, name, labels, resolution, ts) DO UPDATE SET
+				count = counter_rollups.count + EXCLUDED.count,
+				sum = counter_rollups.sum + EXCLUDED.sum,
+				last = EXCLUDED.last,
+				rate = (counter_rollups.sum + EXCLUDED.sum)::double precision / $2::bigint`,
+		rollupAggregates: `WITH moved AS (DELETE FROM counter_rollups WHERE resolution = $3 AND ts < $1 RETURNING tenant, name, labels, ts, count, sum, last)
+			INSERT INTO counter_rollups(tenant, name, labels, resolution, ts, count, sum, last, rate)
+			SELECT tenant, name, labels, $2::bigint, to_timestamp(floor(extract(epoch FROM ts) / $2::bigint) * $2::bigint) AS bucket,
+				sum(count), sum(sum), (array_agg(last ORDER BY ts DESC))[1], sum(sum)::double precision / $2::bigint
+			FROM moved GROUP BY tenant, name, labels, bucket
+			ON CONFLICT(tenant, name, labels, resolution, ts) DO UPDATE SET
+				count = counter_rollups.count + EXCLUDED.count,
+				sum = counter_rollups.sum + EXCLUDED.sum,
+				last = EXCLUDED.last,
+				rate = (counter_rollups.sum + EXCLUDED.sum)::double precision / $2::bigint`,
+		expireSamples: `DELETE FROM counter_samples WHERE ts < $1`,
+		expireRollups: `DELETE FROM counter_rollups WHERE ts < $1 AND resolution = $2`,
+	},
+}
+
 // Тип PSQLStorage представляет реализацию хранилища
 type PSQLStorage struct {
 	db     *pgxpool.Pool
 	logger *zap.SugaredLogger
+	// Ограничение числа серий арендатора, 0 - без ограничения
+	maxSeries int
+	// Время, в течение которого хранилище помнит примененные пакеты
+	batchTTL time.Duration
 }
 
-// Функция New возвращает экземпляр хранилища PSQLStorage
-func New(ctx context.Context, logger *zap.SugaredLogger, conn string) (*PSQLStorage, error) {
+// Функция New возвращает экземпляр хранилища PSQLStorage. Если
+// migrate, к базе данных применяются недостающие миграции схемы, иначе
+// проверяется, что версия схемы совпадает с ожидаемой.
+func New(ctx context.Context, logger *zap.SugaredLogger, conn string, migrate bool) (*PSQLStorage, error) {
 
 	db, err := pgxpool.New(ctx, conn)
 	if err != nil {
@@ -57,39 +207,55 @@ func New(ctx context.Context, logger *zap.SugaredLogger, conn string) (*PSQLStor
 		return nil, err
 	}
 
-	err = retry.Retry(logger, 3, func() error {
-		_, err = db.Exec(ctx, queryCreateGauges)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+	migrator, err := NewMigrator(logger, db)
 	if err != nil {
 		return nil, err
 	}
-
-	err = retry.Retry(logger, 3, func() error {
-		_, err = db.Exec(ctx, queryCreateCounters)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+	if migrate {
+		err = retry.Retry(logger, 3, func() error {
+			return migrator.Up(ctx)
+		})
+	} else {
+		err = migrator.Verify(ctx)
+	}
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	return &PSQLStorage{
-		db:     db,
-		logger: logger,
+		db:       db,
+		logger:   logger,
+		batchTTL: store.DefaultBatchIDTTL,
 	}, nil
 }
 
+// Метод SetSeriesLimit устанавливает ограничение числа серий
+// арендатора, 0 снимает ограничение. Вызывается до начала записи.
+func (p *PSQLStorage) SetSeriesLimit(limit int) {
+	p.maxSeries = limit
+}
+
+// Метод SetBatchIDTTL устанавливает время, в течение которого
+// хранилище помнит примененные пакеты. Вызывается до начала записи.
+func (p *PSQLStorage) SetBatchIDTTL(ttl time.Duration) {
+	p.batchTTL = ttl
+}
+
 func (p *PSQLStorage) SetValue(ctx context.Context, metricType, name, value string) error {
+	id, labels, err := metric.ParseSeriesKey(name)
+	if err != nil {
+		p.logger.Error(store.ErrPointSetValue, err)
+		return err
+	}
+	if err = p.checkSeriesLimit(ctx, metricType, id, labels); err != nil {
+		p.logger.Error(store.ErrPointSetValue, err)
+		return err
+	}
 	switch metricType {
 	case metric.TypeCounter.String():
 		{
-			_, err := p.db.Exec(ctx, querySetCounter, name, value)
+			_, err := p.db.Exec(ctx, querySetCounter, tenant.FromContext(ctx), id, labelsArg(labels), value)
 			if err != nil {
 				p.logger.Errorf("Database exec error: %v", err)
 				return err
@@ -97,12 +263,23 @@ func (p *PSQLStorage) SetValue(ctx context.Context, metricType, name, value stri
 		}
 	case metric.TypeGauge.String():
 		{
-			_, err := p.db.Exec(ctx, querySetGauge, name, value)
+			_, err := p.db.Exec(ctx, querySetGauge, tenant.FromContext(ctx), id, labelsArg(labels), value, nil)
 			if err != nil {
 				p.logger.Errorf("Database exec error: %v", err)
 				return err
 			}
 		}
+	case metric.TypeHistogram.String():
+		{
+			h, err := metric.ParseHistogram(value)
+			if err != nil {
+				p.logger.Error(store.ErrPointSetValue, err)
+				return err
+			}
+			if err = p.setHistogram(ctx, id, labels, h); err != nil {
+				return err
+			}
+		}
 	default:
 		{
 			p.logger.Error(store.ErrPointSetValue, store.ErrIsUnknownType)
@@ -117,10 +294,18 @@ func (p *PSQLStorage) SetValue(ctx context.Context, metricType, name, value stri
 }
 
 func (p *PSQLStorage) SetMetric(ctx context.Context, m metric.Metrics) error {
+	if err := validateMetric(m); err != nil {
+		p.logger.Error(store.ErrPointSetMetric, err)
+		return err
+	}
+	if err := p.checkSeriesLimit(ctx, m.MType, m.ID, m.Labels); err != nil {
+		p.logger.Error(store.ErrPointSetMetric, err)
+		return err
+	}
 	switch m.MType {
 	case metric.TypeCounter.String():
 		{
-			_, err := p.db.Exec(ctx, querySetCounter, m.ID, *m.Delta)
+			_, err := p.db.Exec(ctx, querySetCounter, tenant.FromContext(ctx), m.ID, labelsArg(m.Labels), *m.Delta)
 			if err != nil {
 				p.logger.Errorf("Database exec error: %v. metric: %v", err, m)
 				return err
@@ -128,56 +313,171 @@ func (p *PSQLStorage) SetMetric(ctx context.Context, m metric.Metrics) error {
 		}
 	case metric.TypeGauge.String():
 		{
-			_, err := p.db.Exec(ctx, querySetGauge, m.ID, *m.Value)
+			_, err := p.db.Exec(ctx, querySetGauge, tenant.FromContext(ctx), m.ID, labelsArg(m.Labels), *m.Value, m.Timestamp)
 			if err != nil {
 				p.logger.Errorf("Database exec error: %v. metric: %v", err, m)
 				return err
 			}
 		}
-	default:
+	case metric.TypeHistogram.String():
 		{
-			p.logger.Error(store.ErrPointSetValue, store.ErrIsUnknownType)
-			return store.ErrIsUnknownType
+			return p.setHistogram(ctx, m.ID, m.Labels, m.Histogram)
 		}
 	}
 	return nil
 }
 
+// Метод SetMetrics сохраняет пакет метрик в одной транзакции: пакет
+// применяется целиком или не применяется совсем. Запросы записи
+// отправляются одним pgx.Batch, без ожидания ответа на каждую метрику.
 func (p *PSQLStorage) SetMetrics(ctx context.Context, m []metric.Metrics) error {
 	if len(m) == 0 {
 		return nil
 	}
 	for _, v := range m {
-		err := p.SetMetric(ctx, v)
+		if err := validateMetric(v); err != nil {
+			p.logger.Error(store.ErrPointSetMetrics, err)
+			return err
+		}
+	}
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Errorf("Database begin error: %v", err)
+		return err
+	}
+	// После фиксации откат ничего не делает
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+	if batchID := store.BatchIDFromContext(ctx); batchID != "" {
+		if err = p.claimBatch(ctx, tx, batchID); err != nil {
+			return err
+		}
+	}
+	if err = p.checkBatchSeriesLimit(ctx, tx, m); err != nil {
+		p.logger.Error(store.ErrPointSetMetrics, err)
+		return err
+	}
+
+	id := tenant.FromContext(ctx)
+	batch := &pgx.Batch{}
+	for _, v := range m {
+		switch v.MType {
+		case metric.TypeCounter.String():
+			batch.Queue(querySetCounter, id, v.ID, labelsArg(v.Labels), *v.Delta)
+		case metric.TypeGauge.String():
+			batch.Queue(querySetGauge, id, v.ID, labelsArg(v.Labels), *v.Value, v.Timestamp)
+		case metric.TypeHistogram.String():
+			batch.Queue(querySetHistogram, histogramArgs(id, v.ID, v.Labels, v.Histogram)...)
+		}
+	}
+	results := tx.SendBatch(ctx, batch)
+	for _, v := range m {
+		tag, err := results.Exec()
+		if err == nil && v.MType == metric.TypeHistogram.String() && tag.RowsAffected() == 0 {
+			err = metric.ErrBucketsMismatch
+		}
 		if err != nil {
+			_ = results.Close()
+			p.logger.Errorf("Database exec error: %v. metric: %v", err, v)
 			return err
 		}
 	}
+	if err = results.Close(); err != nil {
+		p.logger.Errorf("Database batch error: %v", err)
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		p.logger.Errorf("Database commit error: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Метод claimBatch отмечает пакет batchID арендатора примененным в
+// транзакции tx. Если пакет уже применен, возвращается
+// store.ErrBatchApplied.
+func (p *PSQLStorage) claimBatch(ctx context.Context, tx pgx.Tx, batchID string) error {
+	if _, err := tx.Exec(ctx, queryExpireBatches, p.batchTTL.Seconds()); err != nil {
+		p.logger.Errorf("Database exec error: %v", err)
+		return err
+	}
+	tag, err := tx.Exec(ctx, queryClaimBatch, tenant.FromContext(ctx), batchID)
+	if err != nil {
+		p.logger.Errorf("Database exec error: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrBatchApplied
+	}
+	return nil
+}
+
+// Функция validateMetric проверяет метрику до записи: тип, имя, метки
+// и наличие значения
+func validateMetric(m metric.Metrics) error {
+	if m.ID == "" {
+		return store.ErrIDIsEmpty
+	}
+	if err := metric.ValidateLabels(m.Labels); err != nil {
+		return err
+	}
+	switch m.MType {
+	case metric.TypeCounter.String():
+		if m.Delta == nil {
+			return store.ErrValueIsEmpty
+		}
+	case metric.TypeGauge.String():
+		if m.Value == nil {
+			return store.ErrValueIsEmpty
+		}
+	case metric.TypeHistogram.String():
+		if m.Histogram == nil {
+			return store.ErrValueIsEmpty
+		}
+		return m.Histogram.Validate()
+	default:
+		return store.ErrIsUnknownType
+	}
 	return nil
 }
 
 func (p *PSQLStorage) GetValue(ctx context.Context, metricType, name string) (string, error) {
 	var row pgx.Row
 	var value string
+	id, labels, err := metric.ParseSeriesKey(name)
+	if err != nil {
+		p.logger.Error(store.ErrPointGetValue, err)
+		return "", err
+	}
 	switch metricType {
 	case metric.TypeCounter.String():
 		{
-			row = p.db.QueryRow(ctx, queryGetCounter, name)
+			row = p.db.QueryRow(ctx, queryGetCounter, tenant.FromContext(ctx), id, labelsArg(labels))
 			if err := row.Scan(&value); err != nil {
 				p.logger.Errorf("Database scan metricType: %s, name: %s error: %v. ", metricType, name, err)
-				return "", err
+				return "", notFound(err)
 			}
 			return value, nil
 		}
 	case metric.TypeGauge.String():
 		{
-			row = p.db.QueryRow(ctx, queryGetGauge, name)
+			row = p.db.QueryRow(ctx, queryGetGauge, tenant.FromContext(ctx), id, labelsArg(labels))
 			if err := row.Scan(&value); err != nil {
 				p.logger.Errorf("Database scan metricType: %s, name: %s error: %v.", metricType, name, err)
-				return "", err
+				return "", notFound(err)
 			}
 			return value, nil
 		}
+	case metric.TypeHistogram.String():
+		{
+			var updatedAt *time.Time
+			h, err := p.getHistogram(ctx, id, labels, &updatedAt)
+			if err != nil {
+				return "", err
+			}
+			return h.String(), nil
+		}
 	default:
 		{
 			p.logger.Error(store.ErrPointGetValue, store.ErrIsUnknownType)
@@ -187,41 +487,79 @@ func (p *PSQLStorage) GetValue(ctx context.Context, metricType, name string) (st
 }
 
 func (p *PSQLStorage) GetMetrics(ctx context.Context) (map[string][]store.Metric, error) {
-	metrics := make(map[string][]store.Metric, 2)
+	id := tenant.FromContext(ctx)
+	metrics := make(map[string][]store.Metric, 3)
 	metrics[metric.TypeCounter.String()] = make([]store.Metric, 0, 2)
 	metrics[metric.TypeGauge.String()] = make([]store.Metric, 0, 31)
+	metrics[metric.TypeHistogram.String()] = make([]store.Metric, 0, 1)
 	var rows pgx.Rows
 	var err error
 
-	rows, err = p.db.Query(ctx, queryGetAllGauge)
+	rows, err = p.db.Query(ctx, queryGetAllGauge, id)
 	if err != nil {
 		p.logger.Errorf("Database query error: %v", err)
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var m store.Metric
-		if err = rows.Scan(&m.Name, &m.Value); err != nil {
+		if err = rows.Scan(&m.Name, &m.Labels, &m.Value, &m.UpdatedAt); err != nil {
 			p.logger.Errorf("Database scan metric: %v error: %v. ", m, err)
 			return nil, err
 		}
+		m.Labels = normalizeLabels(m.Labels)
 		metrics[metric.TypeGauge.String()] = append(metrics[metric.TypeGauge.String()], m)
 	}
+	if err = rows.Err(); err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return nil, err
+	}
 
-	rows, err = p.db.Query(ctx, queryGetAllCounter)
+	rows, err = p.db.Query(ctx, queryGetAllCounter, id)
 	if err != nil {
 		p.logger.Errorf("Database query error: %v", err)
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var m store.Metric
-		if err = rows.Scan(&m.Name, &m.Value); err != nil {
+		if err = rows.Scan(&m.Name, &m.Labels, &m.Value, &m.UpdatedAt); err != nil {
 			p.logger.Errorf("Database scan metric: %v error: %v. ", m, err)
 			return nil, err
 		}
+		m.Labels = normalizeLabels(m.Labels)
 		metrics[metric.TypeCounter.String()] = append(metrics[metric.TypeCounter.String()], m)
 	}
+	if err = rows.Err(); err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return nil, err
+	}
+
+	rows, err = p.db.Query(ctx, queryGetAllHistogram, id)
+	if err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var labels map[string]string
+		var updatedAt *time.Time
+		h, err := scanHistogram(rows, &name, &labels, &updatedAt)
+		if err != nil {
+			p.logger.Errorf("Database scan histogram: %s error: %v. ", name, err)
+			return nil, err
+		}
+		metrics[metric.TypeHistogram.String()] = append(metrics[metric.TypeHistogram.String()],
+			store.Metric{Name: name, Value: h.String(), Labels: normalizeLabels(labels), UpdatedAt: updatedAt})
+	}
+	if err = rows.Err(); err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return nil, err
+	}
 
 	return metrics, nil
 }
@@ -231,20 +569,28 @@ func (p *PSQLStorage) GetMetric(ctx context.Context, m metric.Metrics) (metric.M
 	switch m.MType {
 	case metric.TypeCounter.String():
 		{
-			if err := p.db.QueryRow(ctx, queryGetCounter, m.ID).Scan(&m.Delta); err != nil {
+			if err := p.db.QueryRow(ctx, queryGetCounterMetric, tenant.FromContext(ctx), m.ID, labelsArg(m.Labels)).Scan(&m.Delta, &m.UpdatedAt); err != nil {
 				p.logger.Errorf("Database scan metric: %v error: %v. ", m, err)
-				return m, err
+				return m, notFound(err)
 			}
 
 		}
 	case metric.TypeGauge.String():
 		{
-			if err := p.db.QueryRow(ctx, queryGetGauge, m.ID).Scan(&m.Value); err != nil {
+			if err := p.db.QueryRow(ctx, queryGetGaugeMetric, tenant.FromContext(ctx), m.ID, labelsArg(m.Labels)).Scan(&m.Value, &m.UpdatedAt); err != nil {
 				p.logger.Errorf("Database scan metric: %v error: %v. ", m, err)
-				return m, err
+				return m, notFound(err)
 			}
 
 		}
+	case metric.TypeHistogram.String():
+		{
+			h, err := p.getHistogram(ctx, m.ID, m.Labels, &m.UpdatedAt)
+			if err != nil {
+				return m, err
+			}
+			m.Histogram = h
+		}
 	default:
 		{
 			p.logger.Error(store.ErrPointGetMetric, store.ErrIsUnknownType)
@@ -255,6 +601,515 @@ func (p *PSQLStorage) GetMetric(ctx context.Context, m metric.Metrics) (metric.M
 	return m, nil
 }
 
+// Метод Query вычисляет агрегацию на стороне базы данных. Шаблон имени
+// и условия на метки переводятся в регулярные выражения PostgreSQL.
+func (p *PSQLStorage) Query(ctx context.Context, q store.Query) ([]store.QueryResult, error) {
+	if err := q.Validate(); err != nil {
+		p.logger.Error(store.ErrPointQuery, err)
+		return nil, err
+	}
+	query, args := buildQuery(tenant.FromContext(ctx), q)
+	res := make([]store.QueryResult, 0)
+	err := retry.Retry(p.logger, 3, func() error {
+		res = res[:0]
+		rows, err := p.db.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var r store.QueryResult
+			if q.Aggregation == store.AggregationTopK {
+				err = rows.Scan(&r.ID, &r.Labels, &r.Value)
+				r.Labels = normalizeLabels(r.Labels)
+				res = append(res, r)
+			} else {
+				// avg, min и max по пустому набору серий возвращают NULL
+				var v *float64
+				err = rows.Scan(&v)
+				if v != nil {
+					res = append(res, store.QueryResult{Value: *v})
+				}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		p.logger.Errorf("%sdatabase query error: %v", store.ErrPointQuery, err)
+		return nil, err
+	}
+	return res, nil
+}
+
+// Функция buildQuery возвращает текст и параметры запроса агрегации
+// по сериям арендатора id
+func buildQuery(id string, q store.Query) (string, []any) {
+	table := "gauges"
+	if q.MType == metric.TypeCounter.String() {
+		table = "counters"
+	}
+	cond, args := matchersCondition("tenant = $1 AND name ~ $2", []any{id, q.NamePattern()}, q.Matchers)
+
+	switch q.Aggregation {
+	case store.AggregationTopK:
+		args = append(args, q.K)
+		return fmt.Sprintf(`SELECT name, labels, value::float8 FROM %s WHERE %s AND value IS NOT NULL
+			ORDER BY value DESC, name, labels::text LIMIT $%d`, table, cond, len(args)), args
+	case store.AggregationCount:
+		return fmt.Sprintf(`SELECT count(*)::float8 FROM %s WHERE %s`, table, cond), args
+	case store.AggregationSum:
+		return fmt.Sprintf(`SELECT coalesce(sum(value), 0)::float8 FROM %s WHERE %s`, table, cond), args
+	}
+	return fmt.Sprintf(`SELECT %s(value)::float8 FROM %s WHERE %s`, q.Aggregation, table, cond), args
+}
+
+// Таблицы серий по типу метрики: таблица текущих значений и таблицы
+// истории значений
+var seriesTables = map[string][]string{
+	metric.TypeGauge.String():     {"gauges", "gauge_samples", "gauge_rollups"},
+	metric.TypeCounter.String():   {"counters", "counter_samples", "counter_rollups"},
+	metric.TypeHistogram.String(): {"histograms"},
+}
+
+// Функция deleteQuery возвращает запрос удаления серий, удовлетворяющих
+// условию cond, вместе с историей. Запрос возвращает число удаленных серий.
+func deleteQuery(tables []string, cond string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "WITH del AS (DELETE FROM %s WHERE %s RETURNING tenant, name, labels)", tables[0], cond)
+	for i, t := range tables[1:] {
+		fmt.Fprintf(&b, ", h%d AS (DELETE FROM %s h USING del WHERE h.tenant = del.tenant AND h.name = del.name AND h.labels = del.labels)", i, t)
+	}
+	b.WriteString(" SELECT count(*) FROM del")
+	return b.String()
+}
+
+func (p *PSQLStorage) DeleteMetric(
+	ctx context.Context,
+	m metric.Metrics,
+	matchers []*metric.LabelMatcher,
+) (int, error) {
+	tables, ok := seriesTables[m.MType]
+	if !ok {
+		p.logger.Error(store.ErrPointDeleteMetric, store.ErrIsUnknownType)
+		return 0, store.ErrIsUnknownType
+	}
+	cond, args := matchersCondition("tenant = $1 AND name = $2", []any{tenant.FromContext(ctx), m.ID}, matchers)
+	var deleted int
+	err := p.db.QueryRow(ctx, deleteQuery(tables, cond), args...).Scan(&deleted)
+	if err != nil {
+		p.logger.Errorf("%sdatabase query error: %v", store.ErrPointDeleteMetric, err)
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (p *PSQLStorage) DeleteByPrefix(ctx context.Context, metricType, prefix string) (int, error) {
+	if prefix == "" {
+		p.logger.Error(store.ErrPointDeleteByPrefix, store.ErrPrefixIsEmpty)
+		return 0, store.ErrPrefixIsEmpty
+	}
+	types := []string{metricType}
+	if metricType == "" {
+		types = []string{
+			metric.TypeGauge.String(),
+			metric.TypeCounter.String(),
+			metric.TypeHistogram.String(),
+		}
+	}
+	id := tenant.FromContext(ctx)
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Errorf("Database begin error: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	total := 0
+	for _, mt := range types {
+		tables, ok := seriesTables[mt]
+		if !ok {
+			p.logger.Error(store.ErrPointDeleteByPrefix, store.ErrIsUnknownType)
+			return 0, store.ErrIsUnknownType
+		}
+		var deleted int
+		err = tx.QueryRow(ctx, deleteQuery(tables, "tenant = $1 AND starts_with(name, $2)"), id, prefix).Scan(&deleted)
+		if err != nil {
+			p.logger.Errorf("%sdatabase query error: %v. type: %s", store.ErrPointDeleteByPrefix, err, mt)
+			return 0, err
+		}
+		total += deleted
+	}
+	return total, tx.Commit(ctx)
+}
+
+func (p *PSQLStorage) ResetCounter(ctx context.Context, m metric.Metrics) error {
+	tag, err := p.db.Exec(ctx, queryResetCounter, tenant.FromContext(ctx), m.ID, labelsArg(m.Labels))
+	if err != nil {
+		p.logger.Errorf("%sdatabase exec error: %v", store.ErrPointResetCounter, err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return store.ErrIsMetricDoesntExist
+	}
+	return nil
+}
+
+func (p *PSQLStorage) DeleteStale(ctx context.Context, before time.Time) (int, error) {
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Errorf("Database begin error: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	total := 0
+	for mt, tables := range seriesTables {
+		var deleted int
+		err = tx.QueryRow(ctx, deleteQuery(tables, "updated_at < $1"), before).Scan(&deleted)
+		if err != nil {
+			p.logger.Errorf("%sdatabase query error: %v. type: %s", store.ErrPointDeleteStale, err, mt)
+			return 0, err
+		}
+		total += deleted
+	}
+	return total, tx.Commit(ctx)
+}
+
+// Функция matchersCondition дополняет условие cond с параметрами args
+// условиями на метки и возвращает условие и параметры
+func matchersCondition(cond string, args []any, matchers []*metric.LabelMatcher) (string, []any) {
+	where := []string{cond}
+	for _, m := range matchers {
+		args = append(args, m.Name)
+		label := fmt.Sprintf("coalesce(labels->>$%d, '')", len(args))
+		value := m.Value
+		var op string
+		switch m.Type {
+		case metric.MatchEqual:
+			op = "="
+		case metric.MatchNotEqual:
+			op = "<>"
+		case metric.MatchRegexp:
+			op, value = "~", "^(?:"+value+")$"
+		case metric.MatchNotRegexp:
+			op, value = "!~", "^(?:"+value+")$"
+		}
+		args = append(args, value)
+		where = append(where, fmt.Sprintf("%s %s $%d", label, op, len(args)))
+	}
+	return strings.Join(where, " AND "), args
+}
+
+func (p *PSQLStorage) FindMetrics(
+	ctx context.Context,
+	m metric.Metrics,
+	matchers []*metric.LabelMatcher,
+) ([]metric.Metrics, error) {
+	var query string
+	switch m.MType {
+	case metric.TypeCounter.String():
+		query = queryFindCounter
+	case metric.TypeGauge.String():
+		query = queryFindGauge
+	case metric.TypeHistogram.String():
+		query = queryFindHistogram
+	default:
+		p.logger.Error(store.ErrPointFindMetrics, store.ErrIsUnknownType)
+		return nil, store.ErrIsUnknownType
+	}
+	rows, err := p.db.Query(ctx, query, tenant.FromContext(ctx), m.ID)
+	if err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]metric.Metrics, 0)
+	for rows.Next() {
+		met := metric.Metrics{ID: m.ID, MType: m.MType}
+		switch m.MType {
+		case metric.TypeCounter.String():
+			err = rows.Scan(&met.Labels, &met.UpdatedAt, &met.Delta)
+		case metric.TypeGauge.String():
+			err = rows.Scan(&met.Labels, &met.UpdatedAt, &met.Value)
+		default:
+			met.Histogram, err = scanHistogram(rows, &met.Labels, &met.UpdatedAt)
+		}
+		if err != nil {
+			p.logger.Errorf("Database scan metric: %v error: %v. ", m, err)
+			return nil, err
+		}
+		met.Labels = normalizeLabels(met.Labels)
+		// Условия на метки проверяются после выборки по имени
+		if metric.MatchLabels(met.Labels, matchers) {
+			res = append(res, met)
+		}
+	}
+	return res, rows.Err()
+}
+
+// Метод checkSeriesLimit проверяет, что запись серии не превысит
+// ограничение числа серий арендатора. Проверка выполняется отдельно
+// от записи, поэтому при параллельной записи новых серий ограничение
+// может быть превышено на число одновременно добавляемых серий.
+func (p *PSQLStorage) checkSeriesLimit(ctx context.Context, metricType, name string, labels map[string]string) error {
+	tables, ok := seriesTables[metricType]
+	if p.maxSeries <= 0 || !ok {
+		return nil
+	}
+	var exists bool
+	var count int
+	err := p.db.QueryRow(ctx, fmt.Sprintf(querySeriesCount, tables[0]),
+		tenant.FromContext(ctx), name, labelsArg(labels)).Scan(&exists, &count)
+	if err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return err
+	}
+	if !exists && count >= p.maxSeries {
+		return store.ErrSeriesLimitExceeded
+	}
+	return nil
+}
+
+// Метод checkBatchSeriesLimit проверяет в транзакции tx, что пакет не
+// добавит арендатору серий сверх ограничения. Проверки серий пакета
+// отправляются одним pgx.Batch.
+func (p *PSQLStorage) checkBatchSeriesLimit(ctx context.Context, tx pgx.Tx, m []metric.Metrics) error {
+	if p.maxSeries <= 0 {
+		return nil
+	}
+	type seriesRef struct {
+		metricType string
+		key        string
+	}
+	id := tenant.FromContext(ctx)
+	seen := make(map[seriesRef]struct{}, len(m))
+	batch := &pgx.Batch{}
+	for _, v := range m {
+		ref := seriesRef{v.MType, metric.SeriesKey(v.ID, v.Labels)}
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		tables := seriesTables[v.MType]
+		batch.Queue(fmt.Sprintf(querySeriesCount, tables[0]), id, v.ID, labelsArg(v.Labels))
+	}
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+	var added, count int
+	for range seen {
+		var exists bool
+		if err := results.QueryRow().Scan(&exists, &count); err != nil {
+			p.logger.Errorf("Database query error: %v", err)
+			return err
+		}
+		if !exists {
+			added++
+		}
+	}
+	if added > 0 && count+added > p.maxSeries {
+		return store.ErrSeriesLimitExceeded
+	}
+	return nil
+}
+
+// Метод setHistogram добавляет значения к сохраненной гистограмме.
+// Если границы корзин отличаются от сохраненных, возвращается
+// metric.ErrBucketsMismatch
+func (p *PSQLStorage) setHistogram(ctx context.Context, name string, labels map[string]string, h *metric.Histogram) error {
+	tag, err := p.db.Exec(ctx, querySetHistogram, histogramArgs(tenant.FromContext(ctx), name, labels, h)...)
+	if err != nil {
+		p.logger.Errorf("Database exec error: %v. histogram: %s", err, name)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		p.logger.Error(store.ErrPointSetValue, metric.ErrBucketsMismatch)
+		return metric.ErrBucketsMismatch
+	}
+	return nil
+}
+
+// Функция histogramArgs возвращает параметры запроса querySetHistogram
+func histogramArgs(tenantID, name string, labels map[string]string, h *metric.Histogram) []any {
+	counts := make([]int64, len(h.Counts))
+	for i, c := range h.Counts {
+		counts[i] = int64(c)
+	}
+	return []any{tenantID, name, labelsArg(labels), h.Bounds, counts, h.Sum, int64(h.Count)}
+}
+
+// Метод getHistogram возвращает сохраненную гистограмму и записывает
+// время ее последнего обновления в updatedAt
+func (p *PSQLStorage) getHistogram(
+	ctx context.Context,
+	name string,
+	labels map[string]string,
+	updatedAt **time.Time,
+) (*metric.Histogram, error) {
+	h, err := scanHistogram(p.db.QueryRow(ctx, queryGetHistogram, tenant.FromContext(ctx), name, labelsArg(labels)), updatedAt)
+	if err != nil {
+		p.logger.Errorf("Database scan histogram: %s error: %v. ", name, err)
+		return nil, notFound(err)
+	}
+	return h, nil
+}
+
+// Функция notFound переводит отсутствие строки в ErrIsMetricDoesntExist,
+// как его возвращает локальное хранилище
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return store.ErrIsMetricDoesntExist
+	}
+	return err
+}
+
+// Функция scanHistogram читает гистограмму из строки результата.
+// dest - поля, предшествующие гистограмме в строке
+func scanHistogram(row pgx.Row, dest ...any) (*metric.Histogram, error) {
+	var (
+		h      metric.Histogram
+		counts []int64
+		count  int64
+	)
+	if err := row.Scan(append(dest, &h.Bounds, &counts, &h.Sum, &count)...); err != nil {
+		return nil, err
+	}
+	h.Counts = make([]uint64, len(counts))
+	for i, c := range counts {
+		h.Counts[i] = uint64(c)
+	}
+	h.Count = uint64(count)
+	return &h, nil
+}
+
+func (p *PSQLStorage) GetMetricRange(
+	ctx context.Context,
+	m metric.Metrics,
+	from, to time.Time,
+	step time.Duration,
+) ([]metric.Sample, error) {
+	if to.Before(from) || step < 0 {
+		return nil, store.ErrInvalidRange
+	}
+	var query string
+	switch {
+	case m.MType == metric.TypeCounter.String() && step > 0:
+		query = queryGetCounterRangeStep
+	case m.MType == metric.TypeCounter.String():
+		query = queryGetCounterRange
+	case m.MType == metric.TypeGauge.String() && step > 0:
+		query = queryGetGaugeRangeStep
+	case m.MType == metric.TypeGauge.String():
+		query = queryGetGaugeRange
+	default:
+		p.logger.Error(store.ErrPointGetMetricRange, store.ErrIsUnknownType)
+		return nil, store.ErrIsUnknownType
+	}
+	args := []any{tenant.FromContext(ctx), m.ID, labelsArg(m.Labels), from, to}
+	if step > 0 {
+		args = append(args, step.Seconds())
+	}
+	rows, err := p.db.Query(ctx, query, args...)
+	if err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	samples := make([]metric.Sample, 0)
+	for rows.Next() {
+		var smp metric.Sample
+		var dest any
+		if m.MType == metric.TypeCounter.String() {
+			dest = &smp.Delta
+		} else {
+			dest = &smp.Value
+		}
+		if err = rows.Scan(&smp.Timestamp, dest); err != nil {
+			p.logger.Errorf("Database scan metric: %v error: %v. ", m, err)
+			return nil, err
+		}
+		samples = append(samples, smp)
+	}
+	if err = rows.Err(); err != nil {
+		p.logger.Errorf("Database query error: %v", err)
+		return nil, err
+	}
+	if len(samples) == 0 {
+		exists := queryGaugeExists
+		if m.MType == metric.TypeCounter.String() {
+			exists = queryCounterExists
+		}
+		var found bool
+		if err = p.db.QueryRow(ctx, exists, tenant.FromContext(ctx), m.ID, labelsArg(m.Labels)).Scan(&found); err != nil {
+			p.logger.Errorf("Database scan metric: %v error: %v. ", m, err)
+			return nil, err
+		}
+		if !found {
+			return nil, store.ErrIsMetricDoesntExist
+		}
+	}
+	return samples, nil
+}
+
+func (p *PSQLStorage) Compact(ctx context.Context, tiers []store.RetentionTier, now time.Time) error {
+	if len(tiers) == 0 || tiers[0].Resolution != 0 {
+		return store.ErrInvalidRetention
+	}
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		p.logger.Errorf("Database begin error: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for mt, q := range compactQueriesByType {
+		for i, tier := range tiers {
+			cutoff := now.Add(-tier.Retention)
+			switch {
+			case i+1 < len(tiers) && i == 0:
+				dst := tiers[i+1]
+				_, err = tx.Exec(ctx, q.rollupSamples,
+					store.AlignTime(cutoff, dst.Resolution), int64(dst.Resolution.Seconds()))
+			case i+1 < len(tiers):
+				dst := tiers[i+1]
+				_, err = tx.Exec(ctx, q.rollupAggregates,
+					store.AlignTime(cutoff, dst.Resolution), int64(dst.Resolution.Seconds()), int64(tier.Resolution.Seconds()))
+			case i == 0:
+				_, err = tx.Exec(ctx, q.expireSamples, cutoff)
+			default:
+				_, err = tx.Exec(ctx, q.expireRollups, cutoff, int64(tier.Resolution.Seconds()))
+			}
+			if err != nil {
+				p.logger.Errorf("Database exec error: %v. type: %s, tier: %v", err, mt, tier)
+				return err
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// Функция labelsArg возвращает метки для передачи в запрос. Метрика без
+// меток хранится с пустым объектом, а не с null
+func labelsArg(labels map[string]string) map[string]string {
+	if labels == nil {
+		return map[string]string{}
+	}
+	return labels
+}
+
+// Функция normalizeLabels приводит пустой набор меток к nil,
+// как у метрик, полученных без меток
+func normalizeLabels(labels map[string]string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	return labels
+}
+
 func (p *PSQLStorage) ToJSON(ctx context.Context) ([]byte, error) {
 	return nil, nil
 }
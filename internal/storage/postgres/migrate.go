@@ -0,0 +1,266 @@
+package postgres
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	e "github.com/Eqke/metric-collector/pkg/error"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const (
+	errPointMigrate = "error in postgres.Migrator: "
+
+	// Ключ рекомендательной блокировки, под которой применяются
+	// миграции, чтобы несколько серверов не изменяли схему одновременно
+	migrationLockID = 4_172_302_117
+
+	queryCreateSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations(version bigint PRIMARY KEY, name text NOT NULL, applied_at timestamptz NOT NULL DEFAULT now())`
+	querySchemaMigrationsExist  = `SELECT to_regclass('schema_migrations') IS NOT NULL`
+	querySchemaVersion          = `SELECT coalesce(max(version), 0) FROM schema_migrations`
+	queryInsertMigration        = `INSERT INTO schema_migrations(version, name) VALUES($1, $2)`
+	queryDeleteMigration        = `DELETE FROM schema_migrations WHERE version = $1`
+	queryLockMigrations         = `SELECT pg_advisory_lock($1)`
+	queryUnlockMigrations       = `SELECT pg_advisory_unlock($1)`
+)
+
+var (
+	ErrInvalidMigrations = errors.New("invalid migrations")
+	ErrSchemaVersion     = errors.New("unexpected schema version")
+)
+
+// Миграции схемы: <версия>_<имя>.up.sql применяет миграцию,
+// <версия>_<имя>.down.sql отменяет ее
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Имя файла миграции
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Тип Migration - версия схемы и запросы перехода к ней и обратно
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Функция loadMigrations читает миграции каталога migrations. Версии
+// должны идти подряд с 1, у каждой миграции должны быть оба скрипта.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: unexpected file %s", ErrInvalidMigrations, entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		data, err := fs.ReadFile(fsys, "migrations/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: version %d has names %s and %s", ErrInvalidMigrations, version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i, m := range migrations {
+		switch {
+		case m.Version != i+1:
+			return nil, fmt.Errorf("%w: version %d is missing", ErrInvalidMigrations, i+1)
+		case m.Up == "" || m.Down == "":
+			return nil, fmt.Errorf("%w: version %d needs up and down scripts", ErrInvalidMigrations, m.Version)
+		}
+	}
+	return migrations, nil
+}
+
+// Тип Migrator применяет и отменяет миграции схемы. Примененные версии
+// хранятся в таблице schema_migrations, каждая миграция выполняется в
+// отдельной транзакции под рекомендательной блокировкой.
+type Migrator struct {
+	db         *pgxpool.Pool
+	logger     *zap.SugaredLogger
+	migrations []Migration
+}
+
+// Функция NewMigrator возвращает Migrator со встроенными миграциями
+func NewMigrator(logger *zap.SugaredLogger, db *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, e.WrapError(errPointMigrate, err)
+	}
+	return &Migrator{
+		db:         db,
+		logger:     logger.Named("migrator"),
+		migrations: migrations,
+	}, nil
+}
+
+// Метод Latest возвращает версию схемы, которую ожидает хранилище
+func (m *Migrator) Latest() int {
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Метод Version возвращает текущую версию схемы, 0 - если миграции не
+// применялись
+func (m *Migrator) Version(ctx context.Context) (int, error) {
+	var exists bool
+	if err := m.db.QueryRow(ctx, querySchemaMigrationsExist).Scan(&exists); err != nil {
+		return 0, e.WrapError(errPointMigrate, err)
+	}
+	if !exists {
+		return 0, nil
+	}
+	var version int
+	if err := m.db.QueryRow(ctx, querySchemaVersion).Scan(&version); err != nil {
+		return 0, e.WrapError(errPointMigrate, err)
+	}
+	return version, nil
+}
+
+// Метод Verify проверяет, что версия схемы совпадает с ожидаемой
+func (m *Migrator) Verify(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if version != m.Latest() {
+		return fmt.Errorf("%w: %d, expected %d", ErrSchemaVersion, version, m.Latest())
+	}
+	return nil
+}
+
+// Метод Up применяет миграции, версия которых больше текущей. Схема
+// новее ожидаемой не изменяется, возвращается ErrSchemaVersion.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn, version int) error {
+		if version > m.Latest() {
+			return fmt.Errorf("%w: %d is newer than %d", ErrSchemaVersion, version, m.Latest())
+		}
+		for _, migration := range m.migrations[version:] {
+			m.logger.Infof("applying migration %d_%s", migration.Version, migration.Name)
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, queryInsertMigration, migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Метод Down отменяет последнюю примененную миграцию
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn, version int) error {
+		if version == 0 {
+			return nil
+		}
+		if version > m.Latest() {
+			return fmt.Errorf("%w: %d is newer than %d", ErrSchemaVersion, version, m.Latest())
+		}
+		migration := m.migrations[version-1]
+		m.logger.Infof("reverting migration %d_%s", migration.Version, migration.Name)
+		err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, migration.Down); err != nil {
+				return err
+			}
+			_, err := tx.Exec(ctx, queryDeleteMigration, migration.Version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		return nil
+	})
+}
+
+// Метод withLock выполняет f на соединении, удерживающем блокировку
+// миграций, и передает текущую версию схемы
+func (m *Migrator) withLock(ctx context.Context, f func(*pgxpool.Conn, int) error) error {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return e.WrapError(errPointMigrate, err)
+	}
+	defer conn.Release()
+	if _, err = conn.Exec(ctx, queryLockMigrations, int64(migrationLockID)); err != nil {
+		return e.WrapError(errPointMigrate, err)
+	}
+	defer func() {
+		// Блокировка снимается и после отмены контекста
+		if _, err := conn.Exec(context.WithoutCancel(ctx), queryUnlockMigrations, int64(migrationLockID)); err != nil {
+			m.logger.Errorf("unlock migrations: %v", err)
+		}
+	}()
+	if _, err = conn.Exec(ctx, queryCreateSchemaMigrations); err != nil {
+		return e.WrapError(errPointMigrate, err)
+	}
+	var version int
+	if err = conn.QueryRow(ctx, querySchemaVersion).Scan(&version); err != nil {
+		return e.WrapError(errPointMigrate, err)
+	}
+	// Ошибка не оборачивается, чтобы ее можно было проверить errors.Is
+	return f(conn, version)
+}
+
+// Функция Migrate подключается к базе данных conn и применяет миграции
+// или, если down, отменяет последнюю примененную миграцию
+func Migrate(ctx context.Context, logger *zap.SugaredLogger, conn string, down bool) error {
+	db, err := pgxpool.New(ctx, conn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	migrator, err := NewMigrator(logger, db)
+	if err != nil {
+		return err
+	}
+	if down {
+		err = migrator.Down(ctx)
+	} else {
+		err = migrator.Up(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	version, err := migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Infof("schema version: %d", version)
+	return nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	require.Equal(t, "init", migrations[0].Name)
+	for i, m := range migrations {
+		require.Equal(t, i+1, m.Version)
+		require.NotEmpty(t, m.Up, m.Name)
+		require.NotEmpty(t, m.Down, m.Name)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(data string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(data)}
+	}
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []Migration
+		wantErr error
+	}{
+		{
+			name: "sorted",
+			files: fstest.MapFS{
+				"migrations/0002_b.up.sql":   file("up b"),
+				"migrations/0002_b.down.sql": file("down b"),
+				"migrations/0001_a.up.sql":   file("up a"),
+				"migrations/0001_a.down.sql": file("down a"),
+			},
+			want: []Migration{
+				{Version: 1, Name: "a", Up: "up a", Down: "down a"},
+				{Version: 2, Name: "b", Up: "up b", Down: "down b"},
+			},
+		},
+		{
+			name: "missing_down",
+			files: fstest.MapFS{
+				"migrations/0001_a.up.sql": file("up a"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+		{
+			name: "missing_version",
+			files: fstest.MapFS{
+				"migrations/0002_b.up.sql":   file("up b"),
+				"migrations/0002_b.down.sql": file("down b"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+		{
+			name: "names_differ",
+			files: fstest.MapFS{
+				"migrations/0001_a.up.sql":   file("up a"),
+				"migrations/0001_b.down.sql": file("down b"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+		{
+			name: "unexpected_file",
+			files: fstest.MapFS{
+				"migrations/README.md": file("docs"),
+			},
+			wantErr: ErrInvalidMigrations,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files)
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, tt.want, migrations)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS histograms;
+DROP TABLE IF EXISTS counter_rollups;
+DROP TABLE IF EXISTS gauge_rollups;
+DROP TABLE IF EXISTS counter_samples;
+DROP TABLE IF EXISTS gauge_samples;
+DROP TABLE IF EXISTS counters;
+DROP TABLE IF EXISTS gauges;
@@ -0,0 +1,137 @@
+-- Исходная схема хранилища. Идентичность серии определяется
+-- арендатором, именем и набором меток.
+CREATE TABLE IF NOT EXISTS gauges(
+	tenant text NOT NULL DEFAULT 'default',
+	name text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}',
+	value double precision,
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY(tenant, name, labels)
+);
+
+CREATE TABLE IF NOT EXISTS counters(
+	tenant text NOT NULL DEFAULT 'default',
+	name text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}',
+	value bigint,
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY(tenant, name, labels)
+);
+
+-- История значений
+CREATE TABLE IF NOT EXISTS gauge_samples(
+	tenant text NOT NULL DEFAULT 'default',
+	name text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}',
+	ts timestamptz NOT NULL,
+	value double precision NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS counter_samples(
+	tenant text NOT NULL DEFAULT 'default',
+	name text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}',
+	ts timestamptz NOT NULL,
+	value bigint NOT NULL
+);
+
+-- Агрегаты, resolution хранится в секундах
+CREATE TABLE IF NOT EXISTS gauge_rollups(
+	tenant text NOT NULL DEFAULT 'default',
+	name text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}',
+	resolution bigint NOT NULL,
+	ts timestamptz NOT NULL,
+	count bigint NOT NULL,
+	min double precision NOT NULL,
+	max double precision NOT NULL,
+	sum double precision NOT NULL,
+	last double precision NOT NULL,
+	PRIMARY KEY(tenant, name, labels, resolution, ts)
+);
+
+CREATE TABLE IF NOT EXISTS counter_rollups(
+	tenant text NOT NULL DEFAULT 'default',
+	name text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}',
+	resolution bigint NOT NULL,
+	ts timestamptz NOT NULL,
+	count bigint NOT NULL,
+	sum bigint NOT NULL,
+	last bigint NOT NULL,
+	rate double precision NOT NULL,
+	PRIMARY KEY(tenant, name, labels, resolution, ts)
+);
+
+CREATE TABLE IF NOT EXISTS histograms(
+	tenant text NOT NULL DEFAULT 'default',
+	name text NOT NULL,
+	labels jsonb NOT NULL DEFAULT '{}',
+	bounds double precision[] NOT NULL,
+	counts bigint[] NOT NULL,
+	sum double precision NOT NULL,
+	count bigint NOT NULL,
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY(tenant, name, labels)
+);
+
+-- Таблицы, созданные до появления меток: добавляется столбец labels
+-- и перестраивается первичный ключ
+DO $$
+DECLARE
+	t record;
+BEGIN
+	FOR t IN SELECT * FROM (VALUES
+		('gauges', 'name, labels'),
+		('counters', 'name, labels'),
+		('gauge_samples', ''),
+		('counter_samples', ''),
+		('gauge_rollups', 'name, labels, resolution, ts'),
+		('counter_rollups', 'name, labels, resolution, ts'),
+		('histograms', 'name, labels')
+	) AS v(tbl, pkey) LOOP
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = t.tbl AND column_name = 'labels') THEN
+			EXECUTE format('ALTER TABLE %I ADD COLUMN labels jsonb NOT NULL DEFAULT ''{}''', t.tbl);
+			IF t.pkey <> '' THEN
+				EXECUTE format('ALTER TABLE %I DROP CONSTRAINT IF EXISTS %I', t.tbl, t.tbl || '_pkey');
+				EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (%s)', t.tbl, t.pkey);
+			END IF;
+		END IF;
+	END LOOP;
+END $$;
+
+-- Таблицы текущих значений, созданные до появления времени обновления
+ALTER TABLE gauges ADD COLUMN IF NOT EXISTS updated_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE counters ADD COLUMN IF NOT EXISTS updated_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE histograms ADD COLUMN IF NOT EXISTS updated_at timestamptz NOT NULL DEFAULT now();
+
+-- Таблицы, созданные до появления арендаторов: существующие метрики
+-- принадлежат арендатору по умолчанию
+DO $$
+DECLARE
+	t record;
+BEGIN
+	FOR t IN SELECT * FROM (VALUES
+		('gauges', 'tenant, name, labels'),
+		('counters', 'tenant, name, labels'),
+		('gauge_samples', ''),
+		('counter_samples', ''),
+		('gauge_rollups', 'tenant, name, labels, resolution, ts'),
+		('counter_rollups', 'tenant, name, labels, resolution, ts'),
+		('histograms', 'tenant, name, labels')
+	) AS v(tbl, pkey) LOOP
+		IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = t.tbl AND column_name = 'tenant') THEN
+			EXECUTE format('ALTER TABLE %I ADD COLUMN tenant text NOT NULL DEFAULT ''default''', t.tbl);
+			IF t.pkey <> '' THEN
+				EXECUTE format('ALTER TABLE %I DROP CONSTRAINT IF EXISTS %I', t.tbl, t.tbl || '_pkey');
+				EXECUTE format('ALTER TABLE %I ADD PRIMARY KEY (%s)', t.tbl, t.pkey);
+			END IF;
+		END IF;
+	END LOOP;
+END $$;
+
+-- Индексы создаются после добавления столбца tenant
+CREATE INDEX IF NOT EXISTS gauge_samples_tenant_name_ts ON gauge_samples(tenant, name, ts);
+CREATE INDEX IF NOT EXISTS counter_samples_tenant_name_ts ON counter_samples(tenant, name, ts);
@@ -0,0 +1,9 @@
+-- Примененные пакеты метрик, по которым повтор пакета не применяется
+CREATE TABLE IF NOT EXISTS batches(
+	tenant text NOT NULL,
+	id text NOT NULL,
+	applied_at timestamptz NOT NULL DEFAULT now(),
+	PRIMARY KEY(tenant, id)
+);
+
+CREATE INDEX IF NOT EXISTS batches_applied_at ON batches(applied_at);
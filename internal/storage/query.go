@@ -0,0 +1,154 @@
+// Пакет storage предоставляет интерфейс для хранилища.
+package storage
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Тип Aggregation - функция агрегации запроса
+type Aggregation string
+
+// Перечень функций агрегации
+const (
+	AggregationSum   = Aggregation("sum")
+	AggregationAvg   = Aggregation("avg")
+	AggregationMin   = Aggregation("min")
+	AggregationMax   = Aggregation("max")
+	AggregationCount = Aggregation("count")
+	AggregationTopK  = Aggregation("topk")
+)
+
+// Тип Query описывает запрос агрегации по сериям gauge или counter.
+// Name - шаблон имени метрики: glob (*, ?, [...]) или, если Regexp
+// установлен, регулярное выражение, совпадающее с именем целиком.
+// K - число серий для topk.
+type Query struct {
+	MType       string
+	Name        string
+	Regexp      bool
+	Matchers    []*metric.LabelMatcher
+	Aggregation Aggregation
+	K           int
+}
+
+// Тип QueryResult - результат запроса. Для topk содержит серию,
+// для остальных функций - только значение.
+type QueryResult struct {
+	ID     string            `json:"id,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Value  float64           `json:"value"`
+}
+
+// Метод Validate проверяет запрос
+func (q Query) Validate() error {
+	if q.MType != metric.TypeGauge.String() && q.MType != metric.TypeCounter.String() {
+		return ErrIsUnknownType
+	}
+	switch q.Aggregation {
+	case AggregationSum, AggregationAvg, AggregationMin, AggregationMax, AggregationCount:
+	case AggregationTopK:
+		if q.K <= 0 {
+			return ErrInvalidQuery
+		}
+	default:
+		return ErrInvalidQuery
+	}
+	if q.Name == "" {
+		return ErrIDIsEmpty
+	}
+	if _, err := regexp.Compile(q.NamePattern()); err != nil {
+		return errors.Join(ErrInvalidQuery, err)
+	}
+	return nil
+}
+
+// Метод NamePattern возвращает регулярное выражение, совпадающее с
+// именем целиком. Шаблон glob переводится в регулярное выражение.
+func (q Query) NamePattern() string {
+	if q.Regexp {
+		return "^(?:" + q.Name + ")$"
+	}
+	return "^" + globToRegexp(q.Name) + "$"
+}
+
+// Функция globToRegexp переводит шаблон glob в регулярное выражение
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			j := strings.IndexByte(glob[i+1:], ']')
+			if j < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+j]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += j + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// Метод Aggregate вычисляет результат запроса по значениям серий.
+// Серии должны удовлетворять запросу. Для min, max и avg по пустому
+// набору серий результат пуст.
+func (q Query) Aggregate(series []QueryResult) []QueryResult {
+	if q.Aggregation == AggregationTopK {
+		res := slices.Clone(series)
+		sort.SliceStable(res, func(i, j int) bool {
+			if res[i].Value != res[j].Value {
+				return res[i].Value > res[j].Value
+			}
+			return metric.SeriesKey(res[i].ID, res[i].Labels) < metric.SeriesKey(res[j].ID, res[j].Labels)
+		})
+		if len(res) > q.K {
+			res = res[:q.K]
+		}
+		return res
+	}
+	if q.Aggregation == AggregationCount {
+		return []QueryResult{{Value: float64(len(series))}}
+	}
+	if len(series) == 0 {
+		if q.Aggregation == AggregationSum {
+			return []QueryResult{{Value: 0}}
+		}
+		return []QueryResult{}
+	}
+	var sum float64
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		sum += s.Value
+		min = math.Min(min, s.Value)
+		max = math.Max(max, s.Value)
+	}
+	var v float64
+	switch q.Aggregation {
+	case AggregationSum:
+		v = sum
+	case AggregationAvg:
+		v = sum / float64(len(series))
+	case AggregationMin:
+		v = min
+	case AggregationMax:
+		v = max
+	}
+	return []QueryResult{{Value: v}}
+}
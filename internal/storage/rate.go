@@ -0,0 +1,71 @@
+// Пакет storage предоставляет интерфейс для хранилища.
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Тип CounterRate содержит прирост счетчика за окно, заканчивающееся
+// в Timestamp, и скорость прироста в секунду
+type CounterRate struct {
+	Timestamp time.Time `json:"timestamp"`
+	Increase  float64   `json:"increase"`
+	Rate      float64   `json:"rate"`
+}
+
+// Функция CounterIncrease вычисляет прирост счетчика по накопленным
+// значениям samples (упорядоченным по времени) за окно (end-window, end].
+// Базой служит последнее значение не позже начала окна, при его
+// отсутствии - первое значение в окне. Счетчик может уменьшаться
+// (отрицательным приращением или обнулением через ResetCounter),
+// уменьшение учитывается как отрицательный прирост.
+func CounterIncrease(samples []metric.Sample, end time.Time, window time.Duration) float64 {
+	start := end.Add(-window)
+	// Индекс первого значения внутри окна
+	i := sort.Search(len(samples), func(i int) bool {
+		return samples[i].Timestamp.After(start)
+	})
+	if i > 0 {
+		i--
+	}
+	var increase float64
+	var prev *int64
+	for ; i < len(samples) && !samples[i].Timestamp.After(end); i++ {
+		v := samples[i].Delta
+		if v == nil {
+			continue
+		}
+		if prev != nil {
+			increase += float64(*v - *prev)
+		}
+		prev = v
+	}
+	return increase
+}
+
+// Функция CounterRates вычисляет прирост и скорость счетчика за окно
+// window для моментов from, from+step, ..., to. При нулевом step
+// вычисляется одно значение для момента to. Чтобы у первого окна была
+// база, samples должны включать значения до from-window.
+func CounterRates(samples []metric.Sample, from, to time.Time, step, window time.Duration) []CounterRate {
+	ends := []time.Time{to}
+	if step > 0 {
+		ends = ends[:0]
+		for t := from; !t.After(to); t = t.Add(step) {
+			ends = append(ends, t)
+		}
+	}
+	res := make([]CounterRate, 0, len(ends))
+	for _, end := range ends {
+		increase := CounterIncrease(samples, end, window)
+		res = append(res, CounterRate{
+			Timestamp: end,
+			Increase:  increase,
+			Rate:      increase / window.Seconds(),
+		})
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+// Пакет storage предоставляет интерфейс для хранилища.
+package storage
+
+import (
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Тип RetentionTier описывает уровень хранения значений метрик.
+// Resolution - длина интервала агрегации, нулевое значение означает
+// исходные значения. Retention - срок хранения значений уровня.
+type RetentionTier struct {
+	Resolution time.Duration
+	Retention  time.Duration
+}
+
+// Тип Aggregate содержит агрегированные значения метрики за интервал.
+// Для gauge используются Min, Max, Sum (среднее - Sum/Count) и Last.
+// Для counter Sum содержит прирост за интервал, Rate - прирост в секунду,
+// Last - накопленное значение на конец интервала.
+type Aggregate struct {
+	Timestamp time.Time `json:"timestamp"`
+	Count     int64     `json:"count"`
+	Min       float64   `json:"min"`
+	Max       float64   `json:"max"`
+	Sum       float64   `json:"sum"`
+	Last      float64   `json:"last"`
+	Rate      float64   `json:"rate"`
+}
+
+// Функция AlignTime выравнивает время по началу интервала длиной step,
+// отсчитывая интервалы от начала эпохи
+func AlignTime(t time.Time, step time.Duration) time.Time {
+	if step <= 0 {
+		return t
+	}
+	return time.Unix(0, t.UnixNano()/int64(step)*int64(step)).UTC()
+}
+
+// Функция RollupSamples агрегирует отсортированные по времени значения
+// по интервалам длиной resolution. prev - накопленное значение счетчика
+// перед первым значением, если оно известно. Уменьшение накопленного
+// значения счетчика учитывается как отрицательный прирост.
+func RollupSamples(
+	metricType string,
+	samples []metric.Sample,
+	resolution time.Duration,
+	prev *float64,
+) []Aggregate {
+	res := make([]Aggregate, 0)
+	var cur *Aggregate
+	for _, smp := range samples {
+		var value float64
+		switch {
+		case smp.Value != nil:
+			value = *smp.Value
+		case smp.Delta != nil:
+			value = float64(*smp.Delta)
+		}
+		ts := AlignTime(smp.Timestamp, resolution)
+		if cur == nil || !cur.Timestamp.Equal(ts) {
+			res = append(res, Aggregate{Timestamp: ts, Min: value, Max: value})
+			cur = &res[len(res)-1]
+		}
+		cur.Count++
+		cur.Min = min(cur.Min, value)
+		cur.Max = max(cur.Max, value)
+		cur.Last = value
+		if metricType == metric.TypeCounter.String() {
+			if prev != nil {
+				cur.Sum += value - *prev
+			}
+			v := value
+			prev = &v
+		} else {
+			cur.Sum += value
+		}
+	}
+	if metricType == metric.TypeCounter.String() {
+		for i := range res {
+			res[i].Rate = res[i].Sum / resolution.Seconds()
+		}
+	}
+	return res
+}
+
+// Функция RollupAggregates агрегирует отсортированные по времени агрегаты
+// более мелкого уровня по интервалам длиной resolution
+func RollupAggregates(
+	metricType string,
+	aggs []Aggregate,
+	resolution time.Duration,
+) []Aggregate {
+	res := make([]Aggregate, 0)
+	var cur *Aggregate
+	for _, a := range aggs {
+		ts := AlignTime(a.Timestamp, resolution)
+		if cur == nil || !cur.Timestamp.Equal(ts) {
+			res = append(res, Aggregate{Timestamp: ts, Min: a.Min, Max: a.Max})
+			cur = &res[len(res)-1]
+		}
+		cur.Count += a.Count
+		cur.Min = min(cur.Min, a.Min)
+		cur.Max = max(cur.Max, a.Max)
+		cur.Sum += a.Sum
+		cur.Last = a.Last
+	}
+	if metricType == metric.TypeCounter.String() {
+		for i := range res {
+			res[i].Rate = res[i].Sum / resolution.Seconds()
+		}
+	}
+	return res
+}
+
+// Функция AggregateToSample приводит агрегат к значению метрики:
+// для gauge - среднее за интервал, для counter - накопленное значение
+func AggregateToSample(metricType string, a Aggregate) metric.Sample {
+	if metricType == metric.TypeCounter.String() {
+		delta := int64(a.Last)
+		return metric.Sample{Timestamp: a.Timestamp, Delta: &delta}
+	}
+	var avg float64
+	if a.Count > 0 {
+		avg = a.Sum / float64(a.Count)
+	}
+	return metric.Sample{Timestamp: a.Timestamp, Value: &avg}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/Eqke/metric-collector/internal/server/config"
 	"github.com/Eqke/metric-collector/internal/storage"
 	"os"
+	"time"
 
 	"github.com/Eqke/metric-collector/internal/storage/localstorage"
 	"github.com/Eqke/metric-collector/internal/storage/postgres"
@@ -24,11 +25,19 @@ func GetStorage(ctx context.Context, logger *zap.SugaredLogger, cfg *config.Serv
 	switch {
 	case cfg.DatabaseDSN != "":
 		{
-			return postgres.New(ctx, logger, cfg.DatabaseDSN)
+			s, err := postgres.New(ctx, logger, cfg.DatabaseDSN, cfg.Migrate != config.MigrateVerify)
+			if err != nil {
+				return nil, err
+			}
+			s.SetSeriesLimit(cfg.TenantMaxSeries)
+			s.SetBatchIDTTL(time.Duration(cfg.BatchIDTTL) * time.Second)
+			return s, nil
 		}
 	default:
 		{
 			s := localstorage.New(logger)
+			s.SetSeriesLimit(cfg.TenantMaxSeries)
+			s.SetBatchIDTTL(time.Duration(cfg.BatchIDTTL) * time.Second)
 			if cfg.Restore {
 				if err := s.FromFile(ctx, cfg.FileStoragePath); os.IsNotExist(err) {
 					err = creatingStorageFile(ctx, cfg, s, logger)
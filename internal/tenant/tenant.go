@@ -0,0 +1,121 @@
+// Пакет tenant определяет арендатора, от имени которого выполняются
+// операции с хранилищем. Каждый арендатор видит только свои метрики.
+package tenant
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// Арендатор запросов без идентификатора арендатора
+	Default = "default"
+	// HTTP-заголовок с идентификатором арендатора
+	Header = "X-Tenant-ID"
+	// HTTP-заголовок с токеном арендатора. Заголовок Authorization
+	// остается за токенами доступа и токеном администратора.
+	TokenHeader = "X-Tenant-Token"
+)
+
+var (
+	ErrInvalidTenant = errors.New("invalid tenant")
+	ErrInvalidTokens = errors.New("invalid tenant tokens")
+	ErrTokenRequired = errors.New("tenant token required")
+	ErrInvalidToken  = errors.New("invalid tenant token")
+	ErrTokenMismatch = errors.New("token belongs to another tenant")
+)
+
+// Допустимый идентификатор арендатора
+var tenantRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+type contextKey struct{}
+
+// Функция WithTenant возвращает контекст с арендатором id
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// Функция FromContext возвращает арендатора из контекста или Default,
+// если арендатор не задан
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+	return Default
+}
+
+// Функция Validate проверяет идентификатор арендатора: латинские буквы,
+// цифры, '_', '.', '-', не длиннее 64 символов
+func Validate(id string) error {
+	if !tenantRe.MatchString(id) {
+		return fmt.Errorf("%w: %q", ErrInvalidTenant, id)
+	}
+	return nil
+}
+
+// Тип Tokens - токены арендаторов <Tenant, Token>. Запрос с токеном
+// арендатора выполняется от его имени, к арендатору с токеном нельзя
+// обратиться только по идентификатору.
+type Tokens map[string]string
+
+// Функция ParseTokens разбирает токены арендаторов вида
+// "team-a:token1,team-b:token2"
+func ParseTokens(s string) (Tokens, error) {
+	tokens := make(Tokens)
+	if s == "" {
+		return tokens, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		id, token, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || token == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTokens, part)
+		}
+		if err := Validate(id); err != nil {
+			return nil, err
+		}
+		if _, ok := tokens[id]; ok {
+			return nil, fmt.Errorf("%w: duplicate tenant %q", ErrInvalidTokens, id)
+		}
+		tokens[id] = token
+	}
+	return tokens, nil
+}
+
+// Метод Resolve определяет арендатора запроса по запрошенному
+// идентификатору requested и токену token. Токен арендатора определяет
+// арендатора, запрошенный идентификатор при этом должен совпадать с ним
+// или отсутствовать. Без токена используется запрошенный идентификатор
+// или Default. Токен, не принадлежащий ни одному арендатору, отклоняется.
+func (t Tokens) Resolve(requested, token string) (string, error) {
+	owner := ""
+	if token != "" {
+		for id, tok := range t {
+			if subtle.ConstantTimeCompare([]byte(tok), []byte(token)) == 1 {
+				owner = id
+			}
+		}
+	}
+	if owner != "" {
+		if requested != "" && requested != owner {
+			return "", ErrTokenMismatch
+		}
+		return owner, nil
+	}
+	if token != "" {
+		return "", ErrInvalidToken
+	}
+	if requested == "" {
+		requested = Default
+	}
+	if err := Validate(requested); err != nil {
+		return "", err
+	}
+	if _, ok := t[requested]; ok {
+		return "", ErrTokenRequired
+	}
+	return requested, nil
+}
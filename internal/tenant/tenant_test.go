@@ -0,0 +1,98 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromContext(t *testing.T) {
+	require.Equal(t, Default, FromContext(context.Background()))
+	require.Equal(t, "team-a", FromContext(WithTenant(context.Background(), "team-a")))
+}
+
+func TestParseTokens(t *testing.T) {
+	tokens, err := ParseTokens("team-a:secret1, team-b:secret2")
+	require.NoError(t, err)
+	require.Equal(t, Tokens{"team-a": "secret1", "team-b": "secret2"}, tokens)
+
+	tokens, err = ParseTokens("")
+	require.NoError(t, err)
+	require.Empty(t, tokens)
+
+	for _, s := range []string{"team-a", "team-a:", "bad tenant:secret", "team-a:1,team-a:2"} {
+		_, err = ParseTokens(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestTokens_Resolve(t *testing.T) {
+	tokens := Tokens{"team-a": "secret1"}
+	tests := []struct {
+		name      string
+		requested string
+		token     string
+		want      string
+		wantErr   error
+	}{
+		{
+			name: "default",
+			want: Default,
+		},
+		{
+			name:      "header",
+			requested: "team-b",
+			want:      "team-b",
+		},
+		{
+			name:  "token",
+			token: "secret1",
+			want:  "team-a",
+		},
+		{
+			name:      "token_and_header",
+			requested: "team-a",
+			token:     "secret1",
+			want:      "team-a",
+		},
+		{
+			name:      "token_mismatch",
+			requested: "team-b",
+			token:     "secret1",
+			wantErr:   ErrTokenMismatch,
+		},
+		{
+			name:      "token_required",
+			requested: "team-a",
+			wantErr:   ErrTokenRequired,
+		},
+		{
+			name:    "invalid_token",
+			token:   "wrong",
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:      "invalid_token_of_open_tenant",
+			requested: "team-b",
+			token:     "revoked",
+			wantErr:   ErrInvalidToken,
+		},
+		{
+			name:      "invalid_tenant",
+			requested: "../team",
+			wantErr:   ErrInvalidTenant,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokens.Resolve(tt.requested, tt.token)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Пакет metric описывает метрики и приводит их перечень
+package metric
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sort"
+)
+
+// Объявление ошибок гистограммы
+var (
+	ErrInvalidHistogram = errors.New("invalid histogram")
+	ErrBucketsMismatch  = errors.New("histogram buckets mismatch")
+)
+
+// Тип Histogram представляет распределение значений по корзинам.
+// Bounds - верхние границы корзин по возрастанию, Counts - количество
+// значений в каждой корзине. Последний элемент Counts соответствует
+// корзине +Inf, поэтому len(Counts) == len(Bounds)+1.
+type Histogram struct {
+	Bounds []float64 `json:"bounds"`
+	Counts []uint64  `json:"counts"`
+	Sum    float64   `json:"sum"`
+	Count  uint64    `json:"count"`
+}
+
+// Функция NewHistogram возвращает пустую гистограмму с заданными границами
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		Bounds: slices.Clone(bounds),
+		Counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Метод Observe добавляет значение в гистограмму
+func (h *Histogram) Observe(v float64) {
+	h.ObserveN(v, 1)
+}
+
+// Метод ObserveN добавляет значение в гистограмму n раз, например
+// для значений, полученных с частотой выборки 1/n
+func (h *Histogram) ObserveN(v float64, n uint64) {
+	i := sort.SearchFloat64s(h.Bounds, v)
+	h.Counts[i] += n
+	h.Sum += v * float64(n)
+	h.Count += n
+}
+
+// Метод Validate проверяет согласованность границ и счетчиков
+func (h *Histogram) Validate() error {
+	if len(h.Counts) != len(h.Bounds)+1 || !sort.Float64sAreSorted(h.Bounds) {
+		return ErrInvalidHistogram
+	}
+	var count uint64
+	for _, c := range h.Counts {
+		count += c
+	}
+	if count != h.Count {
+		return ErrInvalidHistogram
+	}
+	return nil
+}
+
+// Метод Merge добавляет к гистограмме значения другой гистограммы
+// с такими же границами корзин
+func (h *Histogram) Merge(o Histogram) error {
+	if !slices.Equal(h.Bounds, o.Bounds) || len(h.Counts) != len(o.Counts) {
+		return ErrBucketsMismatch
+	}
+	for i := range h.Counts {
+		h.Counts[i] += o.Counts[i]
+	}
+	h.Sum += o.Sum
+	h.Count += o.Count
+	return nil
+}
+
+// Метод Quantile оценивает квантиль q (0 <= q <= 1) линейной интерполяцией
+// внутри корзины. Нижней границей первой корзины считается 0, для корзины
+// +Inf возвращается верхняя граница последней конечной корзины.
+func (h *Histogram) Quantile(q float64) float64 {
+	if h.Count == 0 || q < 0 || q > 1 {
+		return math.NaN()
+	}
+	rank := q * float64(h.Count)
+	var cumulative uint64
+	for i, c := range h.Counts {
+		if float64(cumulative+c) < rank || c == 0 {
+			cumulative += c
+			continue
+		}
+		if i == len(h.Bounds) {
+			if i == 0 {
+				return math.NaN()
+			}
+			return h.Bounds[i-1]
+		}
+		lower := float64(0)
+		if i > 0 {
+			lower = h.Bounds[i-1]
+		}
+		upper := h.Bounds[i]
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(c)
+	}
+	return h.Bounds[len(h.Bounds)-1]
+}
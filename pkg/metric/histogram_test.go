@@ -0,0 +1,49 @@
+package metric
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{1, 2, 4})
+	for _, v := range []float64{0.5, 1.5, 1.5, 3, 10} {
+		h.Observe(v)
+	}
+	require.NoError(t, h.Validate())
+	require.Equal(t, []uint64{1, 2, 1, 1}, h.Counts)
+	require.Equal(t, uint64(5), h.Count)
+	require.Equal(t, 16.5, h.Sum)
+
+	t.Run("quantiles", func(t *testing.T) {
+		require.InDelta(t, 1.75, h.Quantile(0.5), 1e-9)
+		require.InDelta(t, 4, h.Quantile(0.99), 1e-9)
+		require.True(t, math.IsNaN(h.Quantile(2)))
+		require.True(t, math.IsNaN(NewHistogram([]float64{1}).Quantile(0.5)))
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		o := NewHistogram([]float64{1, 2, 4})
+		o.Observe(0.1)
+		require.NoError(t, h.Merge(*o))
+		require.Equal(t, []uint64{2, 2, 1, 1}, h.Counts)
+		require.Equal(t, uint64(6), h.Count)
+
+		require.ErrorIs(t, h.Merge(*NewHistogram([]float64{1, 2})), ErrBucketsMismatch)
+	})
+
+	t.Run("observe_n", func(t *testing.T) {
+		o := NewHistogram([]float64{1, 2, 4})
+		o.ObserveN(3, 4)
+		require.Equal(t, []uint64{0, 0, 4, 0}, o.Counts)
+		require.Equal(t, uint64(4), o.Count)
+		require.Equal(t, 12.0, o.Sum)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		invalid := Histogram{Bounds: []float64{1}, Counts: []uint64{1}, Count: 1}
+		require.ErrorIs(t, invalid.Validate(), ErrInvalidHistogram)
+	})
+}
@@ -0,0 +1,210 @@
+// Пакет metric описывает метрики и приводит их перечень
+package metric
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Объявление ошибок меток
+var (
+	ErrInvalidLabel     = errors.New("invalid label")
+	ErrInvalidMatcher   = errors.New("invalid label matcher")
+	ErrInvalidSeriesKey = errors.New("invalid series key")
+)
+
+// Тип MatchType - вид сравнения значения метки
+type MatchType string
+
+// Перечень видов сравнения
+const (
+	MatchEqual     = MatchType("=")
+	MatchNotEqual  = MatchType("!=")
+	MatchRegexp    = MatchType("=~")
+	MatchNotRegexp = MatchType("!~")
+)
+
+// Тип LabelMatcher описывает условие на значение метки.
+// Отсутствующая метка считается меткой с пустым значением.
+type LabelMatcher struct {
+	Name  string    `json:"name"`
+	Type  MatchType `json:"type"`
+	Value string    `json:"value"`
+
+	re *regexp.Regexp
+}
+
+// Функция NewLabelMatcher возвращает условие на значение метки.
+// Регулярные выражения должны совпадать со значением целиком.
+func NewLabelMatcher(t MatchType, name, value string) (*LabelMatcher, error) {
+	if !validLabelName(name) {
+		return nil, ErrInvalidMatcher
+	}
+	m := &LabelMatcher{Name: name, Type: t, Value: value}
+	switch t {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, errors.Join(ErrInvalidMatcher, err)
+		}
+		m.re = re
+	default:
+		return nil, ErrInvalidMatcher
+	}
+	return m, nil
+}
+
+// Функция ParseLabelMatcher разбирает условие вида name=value,
+// name!=value, name=~regexp или name!~regexp
+func ParseLabelMatcher(s string) (*LabelMatcher, error) {
+	i := strings.IndexAny(s, "=!")
+	if i <= 0 {
+		return nil, ErrInvalidMatcher
+	}
+	var t MatchType
+	for _, op := range []MatchType{MatchRegexp, MatchNotRegexp, MatchNotEqual, MatchEqual} {
+		if strings.HasPrefix(s[i:], string(op)) {
+			t = op
+			break
+		}
+	}
+	if t == "" {
+		return nil, ErrInvalidMatcher
+	}
+	return NewLabelMatcher(t, s[:i], s[i+len(t):])
+}
+
+// Функция ParseLabelMatchers разбирает перечень условий
+func ParseLabelMatchers(ss []string) ([]*LabelMatcher, error) {
+	matchers := make([]*LabelMatcher, 0, len(ss))
+	for _, s := range ss {
+		m, err := ParseLabelMatcher(s)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+// Метод Matches проверяет, удовлетворяет ли набор меток условию
+func (m *LabelMatcher) Matches(labels map[string]string) bool {
+	v := labels[m.Name]
+	switch m.Type {
+	case MatchEqual:
+		return v == m.Value
+	case MatchNotEqual:
+		return v != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(v)
+	case MatchNotRegexp:
+		return !m.re.MatchString(v)
+	}
+	return false
+}
+
+// Функция MatchLabels проверяет, удовлетворяет ли набор меток всем условиям
+func MatchLabels(labels map[string]string, matchers []*LabelMatcher) bool {
+	for _, m := range matchers {
+		if !m.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// Функция ParseLabels разбирает метки из пар вида name=value
+func ParseLabels(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || !validLabelName(name) {
+			return nil, ErrInvalidLabel
+		}
+		labels[name] = value
+	}
+	return labels, nil
+}
+
+// Функция ValidateLabels проверяет имена меток
+func ValidateLabels(labels map[string]string) error {
+	for name := range labels {
+		if !validLabelName(name) {
+			return ErrInvalidLabel
+		}
+	}
+	return nil
+}
+
+// Функция SeriesKey возвращает каноническое представление идентичности
+// метрики: имя и отсортированные метки, например Alloc{host="web1"}.
+// Для метрики без меток ключ совпадает с именем.
+func SeriesKey(id string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return id
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(id)
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(labels[name]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Функция ParseSeriesKey разбирает ключ, сформированный SeriesKey
+func ParseSeriesKey(key string) (string, map[string]string, error) {
+	i := strings.IndexByte(key, '{')
+	if i < 0 {
+		return key, nil, nil
+	}
+	if !strings.HasSuffix(key, "}") {
+		return "", nil, ErrInvalidSeriesKey
+	}
+	id, rest := key[:i], key[i+1:len(key)-1]
+	labels := make(map[string]string)
+	for rest != "" {
+		name, tail, ok := strings.Cut(rest, "=")
+		if !ok || !validLabelName(name) {
+			return "", nil, ErrInvalidSeriesKey
+		}
+		quoted, err := strconv.QuotedPrefix(tail)
+		if err != nil {
+			return "", nil, ErrInvalidSeriesKey
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return "", nil, ErrInvalidSeriesKey
+		}
+		labels[name] = value
+		rest = strings.TrimPrefix(tail[len(quoted):], ",")
+	}
+	if len(labels) == 0 {
+		labels = nil
+	}
+	return id, labels, nil
+}
+
+// Функция validLabelName проверяет, что имя метки не содержит
+// символов, используемых в ключе серии и условиях
+func validLabelName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "{}=!~,\"")
+}
@@ -0,0 +1,78 @@
+package metric
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeriesKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		labels map[string]string
+		want   string
+	}{
+		{
+			name: "no_labels",
+			id:   "Alloc",
+			want: "Alloc",
+		},
+		{
+			name:   "sorted_labels",
+			id:     "Alloc",
+			labels: map[string]string{"zone": "eu", "host": "web1"},
+			want:   `Alloc{host="web1",zone="eu"}`,
+		},
+		{
+			name:   "escaped_value",
+			id:     "Alloc",
+			labels: map[string]string{"path": `a,"b"}`},
+			want:   `Alloc{path="a,\"b\"}"}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key := SeriesKey(test.id, test.labels)
+			require.Equal(t, test.want, key)
+
+			id, labels, err := ParseSeriesKey(key)
+			require.NoError(t, err)
+			require.Equal(t, test.id, id)
+			require.Equal(t, test.labels, labels)
+		})
+	}
+
+	_, _, err := ParseSeriesKey(`Alloc{host=web1}`)
+	require.ErrorIs(t, err, ErrInvalidSeriesKey)
+}
+
+func TestLabelMatchers(t *testing.T) {
+	labels := map[string]string{"host": "web1", "zone": "eu"}
+	tests := []struct {
+		name    string
+		exprs   []string
+		want    bool
+		wantErr bool
+	}{
+		{name: "equal", exprs: []string{"host=web1"}, want: true},
+		{name: "not_equal", exprs: []string{"host!=web1"}, want: false},
+		{name: "regexp", exprs: []string{"host=~web.*", "zone=eu"}, want: true},
+		{name: "regexp_is_anchored", exprs: []string{"host=~web"}, want: false},
+		{name: "not_regexp", exprs: []string{"zone!~us|ap"}, want: true},
+		{name: "missing_label_is_empty", exprs: []string{"dc="}, want: true},
+		{name: "invalid_operator", exprs: []string{"host"}, wantErr: true},
+		{name: "invalid_regexp", exprs: []string{"host=~("}, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matchers, err := ParseLabelMatchers(test.exprs)
+			if test.wantErr {
+				require.ErrorIs(t, err, ErrInvalidMatcher)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.want, MatchLabels(labels, matchers))
+		})
+	}
+}
@@ -2,6 +2,7 @@
 package metric
 
 import (
+	"encoding/json"
 	"math/rand"
 	"runtime"
 	"strconv"
@@ -45,18 +46,36 @@ const (
 	RandomValue   = Name("RandomValue")
 	TotalMemory   = Name("TotalMemory")
 	FreeMemory    = Name("FreeMemory")
+	GCPauseNs     = Name("GCPauseNs")
 
 	// Перечень типов метрик
-	TypeGauge   = MType("gauge")
-	TypeCounter = MType("counter")
+	TypeGauge     = MType("gauge")
+	TypeCounter   = MType("counter")
+	TypeHistogram = MType("histogram")
 )
 
+// Границы корзин гистограммы пауз GC в наносекундах: от 10мкс до 100мс
+var GCPauseBounds = []float64{1e4, 5e4, 1e5, 5e5, 1e6, 5e6, 1e7, 5e7, 1e8}
+
 // Тип Metrics используются для API
 type Metrics struct {
 	ID    string   `json:"id"`
 	MType string   `json:"type"`
 	Delta *int64   `json:"delta,omitempty"`
 	Value *float64 `json:"value,omitempty"`
+	// Histogram заполняется для метрик типа histogram
+	Histogram *Histogram `json:"histogram,omitempty"`
+	// Labels вместе с ID и MType определяют идентичность метрики
+	Labels map[string]string `json:"labels,omitempty"`
+	// Timestamp задает момент измерения gauge, по умолчанию значение
+	// сохраняется в историю с временем записи. Накопленное значение
+	// counter всегда сохраняется с временем записи.
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	// UpdatedAt - время последнего обновления, заполняется хранилищем
+	// при чтении. Stale отмечает метрики, не обновлявшиеся дольше
+	// срока устаревания.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Stale     bool       `json:"stale,omitempty"`
 }
 
 // Тип Name является аллиасом строки, который представляет из себя
@@ -115,6 +134,42 @@ func UpdateRuntimeMetrics(ms *runtime.MemStats, mp Map) {
 
 }
 
+// Функция GCPauseHistogram строит гистограмму пауз GC для сборок,
+// завершившихся после сборки с номером lastNumGC. ms.PauseNs хранит паузы
+// последних 256 сборок, поэтому более ранние паузы не учитываются.
+func GCPauseHistogram(ms *runtime.MemStats, lastNumGC uint32) *Histogram {
+	h := NewHistogram(GCPauseBounds)
+	n := ms.NumGC - lastNumGC
+	if n > uint32(len(ms.PauseNs)) {
+		n = uint32(len(ms.PauseNs))
+	}
+	for i := uint32(0); i < n; i++ {
+		h.Observe(float64(ms.PauseNs[(ms.NumGC-i+255)%256]))
+	}
+	return h
+}
+
+// Метод String возвращает гистограмму в формате JSON для хранения в Map
+func (h *Histogram) String() string {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Функция ParseHistogram разбирает гистограмму из формата JSON
+func ParseHistogram(s string) (*Histogram, error) {
+	h := &Histogram{}
+	if err := json.Unmarshal([]byte(s), h); err != nil {
+		return nil, err
+	}
+	if err := h.Validate(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
 // Функция UpdateUtilMetrics позволяет получить дополнительные метрики.
 // mp - карта, куда будут записаны метрики.
 func UpdateUtilMetrics(mp Map) error {
@@ -0,0 +1,12 @@
+// Пакет metric описывает метрики и приводит их перечень
+package metric
+
+import "time"
+
+// Тип Sample представляет значение метрики в определенный момент времени.
+// Для счетчика Delta содержит накопленное на этот момент значение.
+type Sample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Delta     *int64    `json:"delta,omitempty"`
+	Value     *float64  `json:"value,omitempty"`
+}
@@ -0,0 +1,338 @@
+package otlp
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+)
+
+// Время, после которого не обновлявшаяся кумулятивная серия забывается.
+// Значительно больше обычных периодов экспорта OTLP.
+const cumulativeTTL = 24 * time.Hour
+
+// Тип seriesID - идентичность кумулятивной серии арендатора
+type seriesID struct {
+	tenant string
+	mtype  string
+	series string
+}
+
+// Тип cumulativeState - последнее значение кумулятивной серии
+type cumulativeState struct {
+	start  uint64
+	value  int64
+	counts []uint64
+	sum    float64
+	seen   time.Time
+}
+
+// Тип Converter переводит метрики OTLP в metric.Metrics.
+// Хранилище накапливает counter и histogram как сумму изменений,
+// поэтому для кумулятивных серий Converter запоминает последнее значение
+// и передает разницу с ним. Сброс серии определяется по изменению
+// времени начала или уменьшению значения. Первое значение неизвестной
+// серии передается полностью, если серия началась после horizon: запуска
+// Converter или начала забытых серий. Иначе оно могло быть учтено до
+// перезапуска сервера и пропускается.
+type Converter struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	pruned     time.Time
+	horizon    uint64
+	cumulative map[seriesID]*cumulativeState
+	// Серии, запись изменений которых еще не завершена, и сигнал о
+	// завершении записи
+	inflight map[seriesID]struct{}
+	released *sync.Cond
+}
+
+// Функция NewConverter возвращает экземпляр Converter
+func NewConverter() *Converter {
+	c := &Converter{
+		now:        time.Now,
+		horizon:    uint64(time.Now().UnixNano()),
+		cumulative: make(map[seriesID]*cumulativeState),
+		inflight:   make(map[seriesID]struct{}),
+	}
+	c.released = sync.NewCond(&c.mu)
+	return c
+}
+
+// Метод Write переводит метрики запроса req арендатора tenant и передает
+// их write. Значения кумулятивных серий запоминаются, только если write
+// завершилась успешно, поэтому изменения неудачной записи передаются
+// при следующем запросе. Запросы с общими кумулятивными сериями
+// записываются по очереди, остальные - параллельно. Возвращает число
+// переданных метрик.
+//
+// Атрибуты ресурса, например service.name, и атрибуты точки становятся
+// метками, атрибуты точки имеют приоритет. Правила преобразования:
+// монотонная Sum - counter, немонотонная Sum с temporality delta - counter
+// с возможным отрицательным изменением, остальные Sum и Gauge - gauge,
+// Histogram - histogram. Остальные виды данных пропускаются.
+func (c *Converter) Write(tenant string, req *Request, write func([]metric.Metrics) error) (int, error) {
+	c.mu.Lock()
+	var (
+		states  map[seriesID]*cumulativeState
+		metrics []metric.Metrics
+	)
+	for {
+		// Изменения считаются заново после записи общих серий другим
+		// запросом, иначе они были бы переданы дважды
+		states = make(map[seriesID]*cumulativeState)
+		metrics = c.convert(tenant, req, states)
+		if !c.busy(states) {
+			break
+		}
+		c.released.Wait()
+	}
+	for id := range states {
+		c.inflight[id] = struct{}{}
+	}
+	c.mu.Unlock()
+
+	var err error
+	if len(metrics) > 0 {
+		err = write(metrics)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range states {
+		delete(c.inflight, id)
+	}
+	c.released.Broadcast()
+	if err != nil {
+		return 0, err
+	}
+	now := c.now()
+	for id, state := range states {
+		state.seen = now
+		c.cumulative[id] = state
+	}
+	c.prune(now)
+	return len(metrics), nil
+}
+
+// Метод busy сообщает, что изменения одной из серий states записываются
+// другим запросом
+func (c *Converter) busy(states map[seriesID]*cumulativeState) bool {
+	for id := range states {
+		if _, ok := c.inflight[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Метод convert возвращает метрики запроса. Новые значения кумулятивных
+// серий записываются в states.
+func (c *Converter) convert(tenant string, req *Request, states map[seriesID]*cumulativeState) []metric.Metrics {
+	var res []metric.Metrics
+	for _, rm := range req.ResourceMetrics {
+		resource := attributesToLabels(nil, rm.Resource.Attributes)
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				switch {
+				case m.Gauge != nil:
+					for _, p := range m.Gauge.DataPoints {
+						res = append(res, gauge(m.Name, resource, p))
+					}
+				case m.Sum != nil:
+					for _, p := range m.Sum.DataPoints {
+						if !m.Sum.IsMonotonic && m.Sum.AggregationTemporality != TemporalityDelta {
+							res = append(res, gauge(m.Name, resource, p))
+							continue
+						}
+						if out, ok := c.counter(tenant, states, m.Name, resource, m.Sum.AggregationTemporality, p); ok {
+							res = append(res, out)
+						}
+					}
+				case m.Histogram != nil:
+					for _, p := range m.Histogram.DataPoints {
+						if out, ok := c.histogram(tenant, states, m.Name, resource, m.Histogram.AggregationTemporality, p); ok {
+							res = append(res, out)
+						}
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// Метод lookup возвращает последнее значение серии: из states, если
+// серия уже встречалась в запросе, иначе запомненное
+func (c *Converter) lookup(states map[seriesID]*cumulativeState, id seriesID) (*cumulativeState, bool) {
+	if state, ok := states[id]; ok {
+		return state, true
+	}
+	state, ok := c.cumulative[id]
+	return state, ok
+}
+
+// Метод prune забывает серии, не обновлявшиеся дольше cumulativeTTL.
+// Horizon сдвигается к началу забытых серий, чтобы их продолжение не
+// было передано полностью повторно.
+func (c *Converter) prune(now time.Time) {
+	if now.Sub(c.pruned) < cumulativeTTL {
+		return
+	}
+	c.pruned = now
+	for id, state := range c.cumulative {
+		if now.Sub(state.seen) > cumulativeTTL {
+			c.horizon = max(c.horizon, state.start)
+			delete(c.cumulative, id)
+		}
+	}
+}
+
+// Метод unknownStart сообщает, что первое значение серии с временем
+// начала start могло быть учтено раньше
+func (c *Converter) unknownStart(start Uint64) bool {
+	return uint64(start) <= c.horizon
+}
+
+func gauge(name string, resource map[string]string, p NumberDataPoint) metric.Metrics {
+	value := p.Float()
+	return metric.Metrics{
+		ID:        name,
+		MType:     metric.TypeGauge.String(),
+		Value:     &value,
+		Labels:    attributesToLabels(resource, p.Attributes),
+		Timestamp: timestamp(p.TimeUnixNano),
+	}
+}
+
+// Метод counter возвращает изменение counter. Для кумулятивной серии
+// после сброса и для новой серии передается значение, накопленное с
+// момента начала.
+func (c *Converter) counter(
+	tenant string,
+	states map[seriesID]*cumulativeState,
+	name string,
+	resource map[string]string,
+	temporality Temporality,
+	p NumberDataPoint,
+) (metric.Metrics, bool) {
+	labels := attributesToLabels(resource, p.Attributes)
+	delta := int64(math.Round(p.Float()))
+	if temporality == TemporalityCumulative {
+		id := seriesID{tenant: tenant, mtype: metric.TypeCounter.String(), series: metric.SeriesKey(name, labels)}
+		state, ok := c.lookup(states, id)
+		states[id] = &cumulativeState{start: uint64(p.StartTimeUnixNano), value: delta}
+		switch {
+		case !ok && c.unknownStart(p.StartTimeUnixNano):
+			return metric.Metrics{}, false
+		case ok && state.start == uint64(p.StartTimeUnixNano) && delta >= state.value:
+			delta -= state.value
+		}
+	}
+	if delta == 0 {
+		return metric.Metrics{}, false
+	}
+	return metric.Metrics{
+		ID:        name,
+		MType:     metric.TypeCounter.String(),
+		Delta:     &delta,
+		Labels:    labels,
+		Timestamp: timestamp(p.TimeUnixNano),
+	}, true
+}
+
+// Метод histogram возвращает изменение гистограммы. Точка без корзин
+// или с несогласованным числом корзин пропускается.
+func (c *Converter) histogram(
+	tenant string,
+	states map[seriesID]*cumulativeState,
+	name string,
+	resource map[string]string,
+	temporality Temporality,
+	p HistogramDataPoint,
+) (metric.Metrics, bool) {
+	if len(p.BucketCounts) != len(p.ExplicitBounds)+1 {
+		return metric.Metrics{}, false
+	}
+	labels := attributesToLabels(resource, p.Attributes)
+	h := metric.NewHistogram(p.ExplicitBounds)
+	for i, count := range p.BucketCounts {
+		h.Counts[i] = uint64(count)
+		h.Count += uint64(count)
+	}
+	if p.Sum != nil {
+		h.Sum = *p.Sum
+	}
+	if temporality == TemporalityCumulative {
+		id := seriesID{tenant: tenant, mtype: metric.TypeHistogram.String(), series: metric.SeriesKey(name, labels)}
+		state, ok := c.lookup(states, id)
+		states[id] = &cumulativeState{start: uint64(p.StartTimeUnixNano), counts: slices.Clone(h.Counts), sum: h.Sum}
+		switch {
+		case !ok && c.unknownStart(p.StartTimeUnixNano):
+			return metric.Metrics{}, false
+		case ok && state.start == uint64(p.StartTimeUnixNano):
+			subtractCounts(h, state)
+		}
+	}
+	if h.Count == 0 {
+		return metric.Metrics{}, false
+	}
+	return metric.Metrics{
+		ID:        name,
+		MType:     metric.TypeHistogram.String(),
+		Histogram: h,
+		Labels:    labels,
+		Timestamp: timestamp(p.TimeUnixNano),
+	}, true
+}
+
+// Функция subtractCounts вычитает из гистограммы предыдущее кумулятивное
+// состояние. Если серия была сброшена, гистограмма не изменяется и, как
+// и counter, передается полностью.
+func subtractCounts(h *metric.Histogram, state *cumulativeState) {
+	if len(state.counts) != len(h.Counts) {
+		return
+	}
+	for i := range h.Counts {
+		if h.Counts[i] < state.counts[i] {
+			return
+		}
+	}
+	h.Count = 0
+	for i := range h.Counts {
+		h.Counts[i] -= state.counts[i]
+		h.Count += h.Counts[i]
+	}
+	h.Sum -= state.sum
+}
+
+// Функция attributesToLabels добавляет атрибуты к копии меток base.
+// Атрибуты с недопустимыми для метки именами пропускаются.
+func attributesToLabels(base map[string]string, attrs []KeyValue) map[string]string {
+	if len(base) == 0 && len(attrs) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(base)+len(attrs))
+	for k, v := range base {
+		labels[k] = v
+	}
+	for _, kv := range attrs {
+		v, ok := kv.Value.Text()
+		if !ok || metric.ValidateLabels(map[string]string{kv.Key: v}) != nil {
+			continue
+		}
+		labels[kv.Key] = v
+	}
+	return labels
+}
+
+// Функция timestamp возвращает время точки, нулевое время не задано
+func timestamp(ns Uint64) *time.Time {
+	if ns == 0 {
+		return nil
+	}
+	ts := time.Unix(0, int64(ns)).UTC()
+	return &ts
+}
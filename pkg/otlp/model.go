@@ -0,0 +1,194 @@
+// Пакет otlp описывает подмножество сообщений OpenTelemetry
+// ExportMetricsServiceRequest, необходимое для приема метрик по
+// OTLP/HTTP, и их разбор из protobuf и JSON без сгенерированного кода.
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// Ошибки разбора сообщений
+var (
+	ErrInvalidMessage     = errors.New("invalid otlp message")
+	ErrInvalidTemporality = errors.New("invalid aggregation temporality")
+)
+
+// Тип Temporality - временная семантика значений Sum и Histogram
+type Temporality int32
+
+// Перечень значений AggregationTemporality
+const (
+	TemporalityUnspecified = Temporality(0)
+	TemporalityDelta       = Temporality(1)
+	TemporalityCumulative  = Temporality(2)
+)
+
+// Имена значений перечисления в JSON-кодировке OTLP
+var temporalityNames = map[string]Temporality{
+	"AGGREGATION_TEMPORALITY_UNSPECIFIED": TemporalityUnspecified,
+	"AGGREGATION_TEMPORALITY_DELTA":       TemporalityDelta,
+	"AGGREGATION_TEMPORALITY_CUMULATIVE":  TemporalityCumulative,
+}
+
+// Тип Request - сообщение ExportMetricsServiceRequest
+type Request struct {
+	ResourceMetrics []ResourceMetrics `json:"resourceMetrics"`
+}
+
+type ResourceMetrics struct {
+	Resource     Resource       `json:"resource"`
+	ScopeMetrics []ScopeMetrics `json:"scopeMetrics"`
+}
+
+type Resource struct {
+	Attributes []KeyValue `json:"attributes"`
+}
+
+type ScopeMetrics struct {
+	Metrics []Metric `json:"metrics"`
+}
+
+// Тип Metric - метрика OTLP. Заполнено не более одного из полей
+// Gauge, Sum и Histogram, остальные виды данных не поддерживаются.
+type Metric struct {
+	Name      string     `json:"name"`
+	Gauge     *Gauge     `json:"gauge,omitempty"`
+	Sum       *Sum       `json:"sum,omitempty"`
+	Histogram *Histogram `json:"histogram,omitempty"`
+}
+
+type Gauge struct {
+	DataPoints []NumberDataPoint `json:"dataPoints"`
+}
+
+type Sum struct {
+	DataPoints             []NumberDataPoint `json:"dataPoints"`
+	AggregationTemporality Temporality       `json:"aggregationTemporality"`
+	IsMonotonic            bool              `json:"isMonotonic"`
+}
+
+type Histogram struct {
+	DataPoints             []HistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality Temporality          `json:"aggregationTemporality"`
+}
+
+type NumberDataPoint struct {
+	Attributes        []KeyValue `json:"attributes"`
+	StartTimeUnixNano Uint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      Uint64     `json:"timeUnixNano"`
+	AsDouble          *float64   `json:"asDouble,omitempty"`
+	AsInt             *Int64     `json:"asInt,omitempty"`
+}
+
+// Метод Float возвращает значение точки независимо от его представления
+func (p NumberDataPoint) Float() float64 {
+	if p.AsInt != nil {
+		return float64(*p.AsInt)
+	}
+	if p.AsDouble != nil {
+		return *p.AsDouble
+	}
+	return 0
+}
+
+type HistogramDataPoint struct {
+	Attributes        []KeyValue `json:"attributes"`
+	StartTimeUnixNano Uint64     `json:"startTimeUnixNano"`
+	TimeUnixNano      Uint64     `json:"timeUnixNano"`
+	Count             Uint64     `json:"count"`
+	Sum               *float64   `json:"sum,omitempty"`
+	BucketCounts      []Uint64   `json:"bucketCounts"`
+	ExplicitBounds    []float64  `json:"explicitBounds"`
+}
+
+type KeyValue struct {
+	Key   string   `json:"key"`
+	Value AnyValue `json:"value"`
+}
+
+// Тип AnyValue - значение атрибута. Массивы и вложенные наборы
+// атрибутов не поддерживаются и пропускаются.
+type AnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *Int64   `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// Метод Text возвращает строковое представление значения и признак
+// того, что значение задано
+func (v AnyValue) Text() (string, bool) {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue, true
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue), true
+	case v.IntValue != nil:
+		return strconv.FormatInt(int64(*v.IntValue), 10), true
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(*v.DoubleValue, 'g', -1, 64), true
+	}
+	return "", false
+}
+
+// Тип Int64 - целое число, которое в JSON-кодировке OTLP
+// передается строкой, но может быть передано и числом
+type Int64 int64
+
+func (i *Int64) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	v, err := strconv.ParseInt(string(bytes.Trim(b, `"`)), 10, 64)
+	if err != nil {
+		return errors.Join(ErrInvalidMessage, err)
+	}
+	*i = Int64(v)
+	return nil
+}
+
+// Тип Uint64 - беззнаковый аналог Int64
+type Uint64 uint64
+
+func (u *Uint64) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	v, err := strconv.ParseUint(string(bytes.Trim(b, `"`)), 10, 64)
+	if err != nil {
+		return errors.Join(ErrInvalidMessage, err)
+	}
+	*u = Uint64(v)
+	return nil
+}
+
+// Метод UnmarshalJSON принимает как номер, так и имя значения перечисления
+func (t *Temporality) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		v, ok := temporalityNames[name]
+		if !ok {
+			return ErrInvalidTemporality
+		}
+		*t = v
+		return nil
+	}
+	var v int32
+	if err := json.Unmarshal(b, &v); err != nil {
+		return errors.Join(ErrInvalidTemporality, err)
+	}
+	*t = Temporality(v)
+	return nil
+}
+
+// Функция UnmarshalJSON разбирает запрос в JSON-кодировке OTLP
+func UnmarshalJSON(b []byte) (*Request, error) {
+	req := &Request{}
+	if err := json.Unmarshal(b, req); err != nil {
+		return nil, errors.Join(ErrInvalidMessage, err)
+	}
+	return req, nil
+}
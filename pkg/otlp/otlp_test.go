@@ -0,0 +1,266 @@
+package otlp
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func appendMessage(b []byte, num protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+func appendFixed64(b []byte, num protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, v)
+}
+
+func stringAttribute(key, value string) []byte {
+	var v []byte
+	v = protowire.AppendTag(v, 1, protowire.BytesType)
+	v = protowire.AppendString(v, value)
+	var kv []byte
+	kv = protowire.AppendTag(kv, 1, protowire.BytesType)
+	kv = protowire.AppendString(kv, key)
+	return appendMessage(kv, 2, v)
+}
+
+func TestUnmarshal(t *testing.T) {
+	// Sum: монотонная кумулятивная точка с атрибутом
+	var point []byte
+	point = appendMessage(point, 7, stringAttribute("route", "/api"))
+	point = appendFixed64(point, 2, 100)
+	point = appendFixed64(point, 3, 1700000000000000000)
+	point = appendFixed64(point, 6, 42)
+	var sum []byte
+	sum = appendMessage(sum, 1, point)
+	sum = protowire.AppendTag(sum, 2, protowire.VarintType)
+	sum = protowire.AppendVarint(sum, uint64(TemporalityCumulative))
+	sum = protowire.AppendTag(sum, 3, protowire.VarintType)
+	sum = protowire.AppendVarint(sum, 1)
+
+	// Histogram с упакованными корзинами
+	var counts, bounds []byte
+	for _, c := range []uint64{1, 2, 3} {
+		counts = protowire.AppendFixed64(counts, c)
+	}
+	for _, b := range []float64{10, 100} {
+		bounds = protowire.AppendFixed64(bounds, math.Float64bits(b))
+	}
+	var hpoint []byte
+	hpoint = appendFixed64(hpoint, 4, 6)
+	hpoint = appendFixed64(hpoint, 5, math.Float64bits(321))
+	hpoint = appendMessage(hpoint, 6, counts)
+	hpoint = appendMessage(hpoint, 7, bounds)
+	var hist []byte
+	hist = appendMessage(hist, 1, hpoint)
+	hist = protowire.AppendTag(hist, 2, protowire.VarintType)
+	hist = protowire.AppendVarint(hist, uint64(TemporalityDelta))
+
+	var sumMetric, histMetric []byte
+	sumMetric = appendMessage(sumMetric, 1, []byte("requests"))
+	sumMetric = appendMessage(sumMetric, 7, sum)
+	histMetric = appendMessage(histMetric, 1, []byte("latency"))
+	histMetric = appendMessage(histMetric, 9, hist)
+	var scope []byte
+	scope = appendMessage(scope, 2, sumMetric)
+	scope = appendMessage(scope, 2, histMetric)
+	var resource []byte
+	resource = appendMessage(resource, 1, stringAttribute("service.name", "shop"))
+	var rm []byte
+	rm = appendMessage(rm, 1, resource)
+	rm = appendMessage(rm, 2, scope)
+	body := appendMessage(nil, 1, rm)
+
+	req, err := Unmarshal(body)
+	require.NoError(t, err)
+	require.Len(t, req.ResourceMetrics, 1)
+	metrics := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	require.Len(t, metrics, 2)
+
+	require.Equal(t, "requests", metrics[0].Name)
+	require.True(t, metrics[0].Sum.IsMonotonic)
+	require.Equal(t, TemporalityCumulative, metrics[0].Sum.AggregationTemporality)
+	require.Equal(t, 42.0, metrics[0].Sum.DataPoints[0].Float())
+	require.Equal(t, Uint64(100), metrics[0].Sum.DataPoints[0].StartTimeUnixNano)
+
+	require.Equal(t, "latency", metrics[1].Name)
+	hp := metrics[1].Histogram.DataPoints[0]
+	require.Equal(t, []Uint64{1, 2, 3}, hp.BucketCounts)
+	require.Equal(t, []float64{10, 100}, hp.ExplicitBounds)
+	require.Equal(t, 321.0, *hp.Sum)
+
+	_, err = Unmarshal([]byte{0x0a, 0x05, 0x01})
+	require.ErrorIs(t, err, ErrInvalidMessage)
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	body := `{"resourceMetrics":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"shop"}}]},
+		"scopeMetrics":[{"metrics":[
+			{"name":"temperature","gauge":{"dataPoints":[{"asDouble":21.5,"timeUnixNano":"1700000000000000000",
+				"attributes":[{"key":"room","value":{"intValue":"7"}}]}]}},
+			{"name":"queue","sum":{"aggregationTemporality":"AGGREGATION_TEMPORALITY_DELTA","dataPoints":[{"asInt":"-3"}]}}
+		]}]}]}`
+	req, err := UnmarshalJSON([]byte(body))
+	require.NoError(t, err)
+
+	got := convert(t, NewConverter(), "", req)
+	require.Len(t, got, 2)
+	require.Equal(t, metric.TypeGauge.String(), got[0].MType)
+	require.Equal(t, 21.5, *got[0].Value)
+	require.Equal(t, map[string]string{"service.name": "shop", "room": "7"}, got[0].Labels)
+	require.Equal(t, int64(1700000000), got[0].Timestamp.Unix())
+
+	// Немонотонная delta Sum передается как изменение counter
+	require.Equal(t, metric.TypeCounter.String(), got[1].MType)
+	require.Equal(t, int64(-3), *got[1].Delta)
+	require.Nil(t, got[1].Timestamp)
+
+	_, err = UnmarshalJSON([]byte(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"sum":{"aggregationTemporality":"BAD"}}]}]}]}`))
+	require.Error(t, err)
+}
+
+func TestConverter_Cumulative(t *testing.T) {
+	c := NewConverter()
+	sum := func(start Uint64, value Int64) *Request {
+		return &Request{ResourceMetrics: []ResourceMetrics{{ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+			Name: "requests",
+			Sum: &Sum{
+				AggregationTemporality: TemporalityCumulative,
+				IsMonotonic:            true,
+				DataPoints:             []NumberDataPoint{{StartTimeUnixNano: start, AsInt: &value}},
+			},
+		}}}}}}}
+	}
+	hist := func(counts ...Uint64) *Request {
+		var total Uint64
+		for _, c := range counts {
+			total += c
+		}
+		return &Request{ResourceMetrics: []ResourceMetrics{{ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+			Name: "latency",
+			Histogram: &Histogram{
+				AggregationTemporality: TemporalityCumulative,
+				DataPoints: []HistogramDataPoint{{
+					StartTimeUnixNano: 1, Count: total, BucketCounts: counts, ExplicitBounds: []float64{10},
+				}},
+			},
+		}}}}}}}
+	}
+
+	deltas := make([]int64, 0)
+	for _, req := range []*Request{sum(1, 10), sum(1, 15), sum(1, 15), sum(1, 4), sum(2, 3)} {
+		for _, m := range convert(t, c, "", req) {
+			deltas = append(deltas, *m.Delta)
+		}
+	}
+	// Первое значение пропускается, повтор значения не дает изменения,
+	// уменьшение и новое время начала - сброс
+	require.Equal(t, []int64{5, 4, 3}, deltas)
+
+	// Серии арендаторов независимы
+	require.Empty(t, convert(t, c, "acme", sum(2, 10)))
+	require.Equal(t, int64(2), *convert(t, c, "acme", sum(2, 12))[0].Delta)
+
+	// После неудачной записи изменение передается повторно
+	_, err := c.Write("", sum(2, 8), func([]metric.Metrics) error { return errors.New("unavailable") })
+	require.Error(t, err)
+	require.Equal(t, int64(5), *convert(t, c, "", sum(2, 8))[0].Delta)
+
+	require.Empty(t, convert(t, c, "", hist(1, 2)))
+	got := convert(t, c, "", hist(4, 2))
+	require.Equal(t, []uint64{3, 0}, got[0].Histogram.Counts)
+	require.Equal(t, uint64(3), got[0].Histogram.Count)
+
+	// Не обновлявшиеся серии забываются
+	now := time.Now()
+	c.now = func() time.Time { return now.Add(2 * cumulativeTTL) }
+	convert(t, c, "acme", sum(2, 13))
+	require.Len(t, c.cumulative, 1)
+}
+
+func TestConverter_FirstPoint(t *testing.T) {
+	c := NewConverter()
+	started := Uint64(c.horizon)
+	sum := func(start Uint64, value Int64) *Request {
+		return &Request{ResourceMetrics: []ResourceMetrics{{ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+			Name: "requests",
+			Sum: &Sum{
+				AggregationTemporality: TemporalityCumulative,
+				IsMonotonic:            true,
+				DataPoints:             []NumberDataPoint{{StartTimeUnixNano: start, AsInt: &value}},
+			},
+		}}}}}}}
+	}
+
+	// Серия, начавшаяся после запуска, передается с первого значения
+	require.Equal(t, int64(7), *convert(t, c, "", sum(started+1, 7))[0].Delta)
+	require.Equal(t, int64(3), *convert(t, c, "", sum(started+1, 10))[0].Delta)
+
+	// Продолжение забытой серии не передается повторно полностью
+	now := time.Now()
+	c.now = func() time.Time { return now.Add(2 * cumulativeTTL) }
+	convert(t, c, "acme", sum(started+1, 1))
+	require.Empty(t, convert(t, c, "", sum(started+1, 12)))
+	require.Equal(t, int64(5), *convert(t, c, "", sum(started+2, 5))[0].Delta)
+}
+
+func TestConverter_ConcurrentWrite(t *testing.T) {
+	c := NewConverter()
+	started := Uint64(c.horizon)
+	sum := func(name string, value Int64) *Request {
+		return &Request{ResourceMetrics: []ResourceMetrics{{ScopeMetrics: []ScopeMetrics{{Metrics: []Metric{{
+			Name: name,
+			Sum: &Sum{
+				AggregationTemporality: TemporalityCumulative,
+				IsMonotonic:            true,
+				DataPoints:             []NumberDataPoint{{StartTimeUnixNano: started + 1, AsInt: &value}},
+			},
+		}}}}}}}
+	}
+
+	writing := make(chan struct{})
+	unblock := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.Write("", sum("requests", 10), func([]metric.Metrics) error {
+			close(writing)
+			<-unblock
+			return nil
+		})
+	}()
+	<-writing
+
+	// Запись другой серии не ждет медленную запись
+	require.Equal(t, int64(4), *convert(t, c, "", sum("errors", 4))[0].Delta)
+
+	// Запись той же серии ждет и считает изменение от ее результата
+	second := make(chan []metric.Metrics)
+	go func() { second <- convert(t, c, "", sum("requests", 15)) }()
+	select {
+	case <-second:
+		t.Fatal("write of the same series did not wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(unblock)
+	<-done
+	require.Equal(t, int64(5), *(<-second)[0].Delta)
+}
+
+// Функция convert возвращает метрики, которые Converter передает на запись
+func convert(t *testing.T, c *Converter, tenant string, req *Request) []metric.Metrics {
+	var res []metric.Metrics
+	_, err := c.Write(tenant, req, func(metrics []metric.Metrics) error {
+		res = metrics
+		return nil
+	})
+	require.NoError(t, err)
+	return res
+}
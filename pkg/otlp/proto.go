@@ -0,0 +1,296 @@
+package otlp
+
+import (
+	"errors"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Тип field - поле сообщения protobuf. Для полей фиксированной длины
+// и varint значение хранится в u, для полей с длиной - в b.
+type field struct {
+	num protowire.Number
+	typ protowire.Type
+	u   uint64
+	b   []byte
+}
+
+// Метод float возвращает значение поля double
+func (f field) float() float64 {
+	return math.Float64frombits(f.u)
+}
+
+// Функция parseFields вызывает fn для каждого поля сообщения.
+// Неизвестные поля пропускаются вызывающим.
+func parseFields(b []byte, fn func(field) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return errors.Join(ErrInvalidMessage, protowire.ParseError(n))
+		}
+		b = b[n:]
+		f := field{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			f.u, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed64Type:
+			f.u, n = protowire.ConsumeFixed64(b)
+		case protowire.Fixed32Type:
+			var v uint32
+			v, n = protowire.ConsumeFixed32(b)
+			f.u = uint64(v)
+		case protowire.BytesType:
+			f.b, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return errors.Join(ErrInvalidMessage, protowire.ParseError(n))
+		}
+		b = b[n:]
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Функция parseFixed64s разбирает повторяющееся поле fixed64 или double,
+// которое может быть передано как упакованным, так и поэлементно
+func parseFixed64s(f field) ([]uint64, error) {
+	if f.typ == protowire.Fixed64Type {
+		return []uint64{f.u}, nil
+	}
+	if f.typ != protowire.BytesType {
+		return nil, ErrInvalidMessage
+	}
+	res := make([]uint64, 0, len(f.b)/8)
+	for b := f.b; len(b) > 0; {
+		v, n := protowire.ConsumeFixed64(b)
+		if n < 0 {
+			return nil, errors.Join(ErrInvalidMessage, protowire.ParseError(n))
+		}
+		res = append(res, v)
+		b = b[n:]
+	}
+	return res, nil
+}
+
+// Функция Unmarshal разбирает запрос в protobuf-кодировке OTLP
+func Unmarshal(b []byte) (*Request, error) {
+	req := &Request{}
+	err := parseFields(b, func(f field) error {
+		if f.num == 1 && f.typ == protowire.BytesType {
+			rm, err := unmarshalResourceMetrics(f.b)
+			if err != nil {
+				return err
+			}
+			req.ResourceMetrics = append(req.ResourceMetrics, rm)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func unmarshalResourceMetrics(b []byte) (ResourceMetrics, error) {
+	var rm ResourceMetrics
+	err := parseFields(b, func(f field) error {
+		if f.typ != protowire.BytesType {
+			return nil
+		}
+		switch f.num {
+		case 1:
+			return parseFields(f.b, func(f field) error {
+				if f.num == 1 && f.typ == protowire.BytesType {
+					kv, err := unmarshalKeyValue(f.b)
+					if err != nil {
+						return err
+					}
+					rm.Resource.Attributes = append(rm.Resource.Attributes, kv)
+				}
+				return nil
+			})
+		case 2:
+			var sm ScopeMetrics
+			err := parseFields(f.b, func(f field) error {
+				if f.num == 2 && f.typ == protowire.BytesType {
+					m, err := unmarshalMetric(f.b)
+					if err != nil {
+						return err
+					}
+					sm.Metrics = append(sm.Metrics, m)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+		}
+		return nil
+	})
+	return rm, err
+}
+
+func unmarshalMetric(b []byte) (Metric, error) {
+	var m Metric
+	err := parseFields(b, func(f field) error {
+		if f.typ != protowire.BytesType {
+			return nil
+		}
+		switch f.num {
+		case 1:
+			m.Name = string(f.b)
+		case 5:
+			m.Gauge = &Gauge{}
+			return parseFields(f.b, func(f field) error {
+				if f.num == 1 && f.typ == protowire.BytesType {
+					p, err := unmarshalNumberDataPoint(f.b)
+					if err != nil {
+						return err
+					}
+					m.Gauge.DataPoints = append(m.Gauge.DataPoints, p)
+				}
+				return nil
+			})
+		case 7:
+			m.Sum = &Sum{}
+			return parseFields(f.b, func(f field) error {
+				switch {
+				case f.num == 1 && f.typ == protowire.BytesType:
+					p, err := unmarshalNumberDataPoint(f.b)
+					if err != nil {
+						return err
+					}
+					m.Sum.DataPoints = append(m.Sum.DataPoints, p)
+				case f.num == 2 && f.typ == protowire.VarintType:
+					m.Sum.AggregationTemporality = Temporality(f.u)
+				case f.num == 3 && f.typ == protowire.VarintType:
+					m.Sum.IsMonotonic = f.u != 0
+				}
+				return nil
+			})
+		case 9:
+			m.Histogram = &Histogram{}
+			return parseFields(f.b, func(f field) error {
+				switch {
+				case f.num == 1 && f.typ == protowire.BytesType:
+					p, err := unmarshalHistogramDataPoint(f.b)
+					if err != nil {
+						return err
+					}
+					m.Histogram.DataPoints = append(m.Histogram.DataPoints, p)
+				case f.num == 2 && f.typ == protowire.VarintType:
+					m.Histogram.AggregationTemporality = Temporality(f.u)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return m, err
+}
+
+func unmarshalNumberDataPoint(b []byte) (NumberDataPoint, error) {
+	var p NumberDataPoint
+	err := parseFields(b, func(f field) error {
+		switch {
+		case f.num == 7 && f.typ == protowire.BytesType:
+			kv, err := unmarshalKeyValue(f.b)
+			if err != nil {
+				return err
+			}
+			p.Attributes = append(p.Attributes, kv)
+		case f.num == 2 && f.typ == protowire.Fixed64Type:
+			p.StartTimeUnixNano = Uint64(f.u)
+		case f.num == 3 && f.typ == protowire.Fixed64Type:
+			p.TimeUnixNano = Uint64(f.u)
+		case f.num == 4 && f.typ == protowire.Fixed64Type:
+			v := f.float()
+			p.AsDouble = &v
+		case f.num == 6 && f.typ == protowire.Fixed64Type:
+			v := Int64(f.u)
+			p.AsInt = &v
+		}
+		return nil
+	})
+	return p, err
+}
+
+func unmarshalHistogramDataPoint(b []byte) (HistogramDataPoint, error) {
+	var p HistogramDataPoint
+	err := parseFields(b, func(f field) error {
+		switch {
+		case f.num == 9 && f.typ == protowire.BytesType:
+			kv, err := unmarshalKeyValue(f.b)
+			if err != nil {
+				return err
+			}
+			p.Attributes = append(p.Attributes, kv)
+		case f.num == 2 && f.typ == protowire.Fixed64Type:
+			p.StartTimeUnixNano = Uint64(f.u)
+		case f.num == 3 && f.typ == protowire.Fixed64Type:
+			p.TimeUnixNano = Uint64(f.u)
+		case f.num == 4 && f.typ == protowire.Fixed64Type:
+			p.Count = Uint64(f.u)
+		case f.num == 5 && f.typ == protowire.Fixed64Type:
+			v := f.float()
+			p.Sum = &v
+		case f.num == 6:
+			counts, err := parseFixed64s(f)
+			if err != nil {
+				return err
+			}
+			for _, c := range counts {
+				p.BucketCounts = append(p.BucketCounts, Uint64(c))
+			}
+		case f.num == 7:
+			bounds, err := parseFixed64s(f)
+			if err != nil {
+				return err
+			}
+			for _, b := range bounds {
+				p.ExplicitBounds = append(p.ExplicitBounds, math.Float64frombits(b))
+			}
+		}
+		return nil
+	})
+	return p, err
+}
+
+func unmarshalKeyValue(b []byte) (KeyValue, error) {
+	var kv KeyValue
+	err := parseFields(b, func(f field) error {
+		if f.typ != protowire.BytesType {
+			return nil
+		}
+		switch f.num {
+		case 1:
+			kv.Key = string(f.b)
+		case 2:
+			return parseFields(f.b, func(f field) error {
+				switch {
+				case f.num == 1 && f.typ == protowire.BytesType:
+					v := string(f.b)
+					kv.Value.StringValue = &v
+				case f.num == 2 && f.typ == protowire.VarintType:
+					v := f.u != 0
+					kv.Value.BoolValue = &v
+				case f.num == 3 && f.typ == protowire.VarintType:
+					v := Int64(f.u)
+					kv.Value.IntValue = &v
+				case f.num == 4 && f.typ == protowire.Fixed64Type:
+					v := f.float()
+					kv.Value.DoubleValue = &v
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	return kv, err
+}
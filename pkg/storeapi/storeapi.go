@@ -0,0 +1,99 @@
+// Пакет storeapi дополняет gRPC API сборщика метрик: ключи метаданных
+// вызовов и перевод сообщений API в типы пакета metric и обратно.
+package storeapi
+
+import (
+	"fmt"
+
+	"github.com/Eqke/metric-collector/pkg/metric"
+	pb "github.com/eqkez0r/metric-collector-grpc-api/grpc/metric_collector"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Ключ метаданных gRPC с токеном в виде Bearer <token>
+const AuthorizationMetadataKey = "authorization"
+
+// Ключ метаданных gRPC с идентификатором арендатора
+const TenantMetadataKey = "x-tenant-id"
+
+// Ключ метаданных gRPC с токеном арендатора
+const TenantTokenMetadataKey = "x-tenant-token"
+
+// Ключ метаданных gRPC с идентификатором пакета метрик. Пакет с
+// идентификатором, который сервер уже применил, повторно не применяется.
+const BatchIDMetadataKey = "idempotency-key"
+
+// Функция SignedPayload возвращает подписываемые данные вызова: полное
+// имя метода method и сообщение req в детерминированной сериализации,
+// чтобы клиент и сервер получили одинаковые байты независимо от порядка
+// меток
+func SignedPayload(method string, req any) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("request of %s is not a proto message: %T", method, req)
+	}
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(method+"\n"), b...), nil
+}
+
+// Функция ToProto переводит метрику в сообщение API
+func ToProto(m metric.Metrics) *pb.Metric {
+	res := &pb.Metric{
+		MetricName: m.ID,
+		MetricType: m.MType,
+		Delta:      m.Delta,
+		Value:      m.Value,
+		Labels:     m.Labels,
+		Stale:      m.Stale,
+	}
+	if m.Histogram != nil {
+		res.Histogram = &pb.Histogram{
+			Bounds: m.Histogram.Bounds,
+			Counts: m.Histogram.Counts,
+			Sum:    m.Histogram.Sum,
+			Count:  m.Histogram.Count,
+		}
+	}
+	if m.UpdatedAt != nil {
+		res.UpdatedAt = timestamppb.New(*m.UpdatedAt)
+	}
+	return res
+}
+
+// Функция UpdateFromProto переводит сообщение API с обновлением метрики
+// в метрику. Время обновления и признак устаревания ведет сервер, поэтому
+// значения клиента не копируются.
+func UpdateFromProto(m *pb.Metric) metric.Metrics {
+	if m == nil {
+		return metric.Metrics{}
+	}
+	res := metric.Metrics{
+		ID:     m.MetricName,
+		MType:  m.MetricType,
+		Delta:  m.Delta,
+		Value:  m.Value,
+		Labels: m.Labels,
+	}
+	if h := m.Histogram; h != nil {
+		res.Histogram = &metric.Histogram{
+			Bounds: h.Bounds,
+			Counts: h.Counts,
+			Sum:    h.Sum,
+			Count:  h.Count,
+		}
+	}
+	return res
+}
+
+// Функция SampleToProto переводит значение истории метрики в сообщение API
+func SampleToProto(s metric.Sample) *pb.Sample {
+	return &pb.Sample{
+		Timestamp: timestamppb.New(s.Timestamp),
+		Delta:     s.Delta,
+		Value:     s.Value,
+	}
+}
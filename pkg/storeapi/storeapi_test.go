@@ -0,0 +1,55 @@
+package storeapi
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/eqkez0r/metric-collector-grpc-api/grpc/metric_collector"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSignedPayload(t *testing.T) {
+	const method = "/metric_collector.MetricCollector/ReceiveMetric"
+	delta := int64(1)
+	req := func(delta int64) *pb.ReceiveMetricRequest {
+		return &pb.ReceiveMetricRequest{Metric: &pb.Metric{
+			MetricName: "PollCount",
+			MetricType: "counter",
+			Delta:      &delta,
+			Labels:     map[string]string{"host": "web1", "zone": "eu", "app": "agent"},
+		}}
+	}
+
+	first, err := SignedPayload(method, req(delta))
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		again, err := SignedPayload(method, req(delta))
+		require.NoError(t, err)
+		require.Equal(t, first, again)
+	}
+
+	other, err := SignedPayload(method, req(delta+1))
+	require.NoError(t, err)
+	require.NotEqual(t, first, other)
+
+	_, err = SignedPayload(method, "not a message")
+	require.Error(t, err)
+}
+
+func TestUpdateFromProto(t *testing.T) {
+	delta := int64(3)
+	m := UpdateFromProto(&pb.Metric{
+		MetricName: "PollCount",
+		MetricType: "counter",
+		Delta:      &delta,
+		Labels:     map[string]string{"host": "web1"},
+		Stale:      true,
+		UpdatedAt:  timestamppb.New(time.Unix(0, 0)),
+	})
+	require.Equal(t, "PollCount", m.ID)
+	require.Equal(t, &delta, m.Delta)
+	require.Equal(t, map[string]string{"host": "web1"}, m.Labels)
+	require.False(t, m.Stale)
+	require.Nil(t, m.UpdatedAt)
+}
@@ -0,0 +1,443 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: metric_collector.proto
+
+package metric_collector_v1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// MetricCollectorClient is the client API for MetricCollector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MetricCollectorClient interface {
+	ReceiveMetric(ctx context.Context, in *ReceiveMetricRequest, opts ...grpc.CallOption) (*ReceiveMetricResponse, error)
+	ReceiveMetricBatch(ctx context.Context, in *ReceiveMetricBatchRequest, opts ...grpc.CallOption) (*ReceiveMetricResponse, error)
+	ReadMetric(ctx context.Context, in *ReadMetricRequest, opts ...grpc.CallOption) (*ReadMetricResponse, error)
+	ReadMetricRange(ctx context.Context, in *ReadMetricRangeRequest, opts ...grpc.CallOption) (*ReadMetricRangeResponse, error)
+	FindMetrics(ctx context.Context, in *FindMetricsRequest, opts ...grpc.CallOption) (*FindMetricsResponse, error)
+	ReadAllMetric(ctx context.Context, in *ReadAllMetricRequest, opts ...grpc.CallOption) (*ReadAllMetricResponse, error)
+}
+
+type metricCollectorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMetricCollectorClient(cc grpc.ClientConnInterface) MetricCollectorClient {
+	return &metricCollectorClient{cc}
+}
+
+func (c *metricCollectorClient) ReceiveMetric(ctx context.Context, in *ReceiveMetricRequest, opts ...grpc.CallOption) (*ReceiveMetricResponse, error) {
+	out := new(ReceiveMetricResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollector/ReceiveMetric", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricCollectorClient) ReceiveMetricBatch(ctx context.Context, in *ReceiveMetricBatchRequest, opts ...grpc.CallOption) (*ReceiveMetricResponse, error) {
+	out := new(ReceiveMetricResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollector/ReceiveMetricBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricCollectorClient) ReadMetric(ctx context.Context, in *ReadMetricRequest, opts ...grpc.CallOption) (*ReadMetricResponse, error) {
+	out := new(ReadMetricResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollector/ReadMetric", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricCollectorClient) ReadMetricRange(ctx context.Context, in *ReadMetricRangeRequest, opts ...grpc.CallOption) (*ReadMetricRangeResponse, error) {
+	out := new(ReadMetricRangeResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollector/ReadMetricRange", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricCollectorClient) FindMetrics(ctx context.Context, in *FindMetricsRequest, opts ...grpc.CallOption) (*FindMetricsResponse, error) {
+	out := new(FindMetricsResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollector/FindMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricCollectorClient) ReadAllMetric(ctx context.Context, in *ReadAllMetricRequest, opts ...grpc.CallOption) (*ReadAllMetricResponse, error) {
+	out := new(ReadAllMetricResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollector/ReadAllMetric", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MetricCollectorServer is the server API for MetricCollector service.
+// All implementations must embed UnimplementedMetricCollectorServer
+// for forward compatibility
+type MetricCollectorServer interface {
+	ReceiveMetric(context.Context, *ReceiveMetricRequest) (*ReceiveMetricResponse, error)
+	ReceiveMetricBatch(context.Context, *ReceiveMetricBatchRequest) (*ReceiveMetricResponse, error)
+	ReadMetric(context.Context, *ReadMetricRequest) (*ReadMetricResponse, error)
+	ReadMetricRange(context.Context, *ReadMetricRangeRequest) (*ReadMetricRangeResponse, error)
+	FindMetrics(context.Context, *FindMetricsRequest) (*FindMetricsResponse, error)
+	ReadAllMetric(context.Context, *ReadAllMetricRequest) (*ReadAllMetricResponse, error)
+	mustEmbedUnimplementedMetricCollectorServer()
+}
+
+// UnimplementedMetricCollectorServer must be embedded to have forward compatible implementations.
+type UnimplementedMetricCollectorServer struct {
+}
+
+func (UnimplementedMetricCollectorServer) ReceiveMetric(context.Context, *ReceiveMetricRequest) (*ReceiveMetricResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveMetric not implemented")
+}
+func (UnimplementedMetricCollectorServer) ReceiveMetricBatch(context.Context, *ReceiveMetricBatchRequest) (*ReceiveMetricResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveMetricBatch not implemented")
+}
+func (UnimplementedMetricCollectorServer) ReadMetric(context.Context, *ReadMetricRequest) (*ReadMetricResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadMetric not implemented")
+}
+func (UnimplementedMetricCollectorServer) ReadMetricRange(context.Context, *ReadMetricRangeRequest) (*ReadMetricRangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadMetricRange not implemented")
+}
+func (UnimplementedMetricCollectorServer) FindMetrics(context.Context, *FindMetricsRequest) (*FindMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindMetrics not implemented")
+}
+func (UnimplementedMetricCollectorServer) ReadAllMetric(context.Context, *ReadAllMetricRequest) (*ReadAllMetricResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadAllMetric not implemented")
+}
+func (UnimplementedMetricCollectorServer) mustEmbedUnimplementedMetricCollectorServer() {}
+
+// UnsafeMetricCollectorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MetricCollectorServer will
+// result in compilation errors.
+type UnsafeMetricCollectorServer interface {
+	mustEmbedUnimplementedMetricCollectorServer()
+}
+
+func RegisterMetricCollectorServer(s grpc.ServiceRegistrar, srv MetricCollectorServer) {
+	s.RegisterService(&MetricCollector_ServiceDesc, srv)
+}
+
+func _MetricCollector_ReceiveMetric_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiveMetricRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorServer).ReceiveMetric(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollector/ReceiveMetric",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorServer).ReceiveMetric(ctx, req.(*ReceiveMetricRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricCollector_ReceiveMetricBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiveMetricBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorServer).ReceiveMetricBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollector/ReceiveMetricBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorServer).ReceiveMetricBatch(ctx, req.(*ReceiveMetricBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricCollector_ReadMetric_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMetricRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorServer).ReadMetric(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollector/ReadMetric",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorServer).ReadMetric(ctx, req.(*ReadMetricRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricCollector_ReadMetricRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMetricRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorServer).ReadMetricRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollector/ReadMetricRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorServer).ReadMetricRange(ctx, req.(*ReadMetricRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricCollector_FindMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindMetricsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorServer).FindMetrics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollector/FindMetrics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorServer).FindMetrics(ctx, req.(*FindMetricsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricCollector_ReadAllMetric_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadAllMetricRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorServer).ReadAllMetric(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollector/ReadAllMetric",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorServer).ReadAllMetric(ctx, req.(*ReadAllMetricRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MetricCollector_ServiceDesc is the grpc.ServiceDesc for MetricCollector service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MetricCollector_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "metric_collector_grpc.MetricCollector",
+	HandlerType: (*MetricCollectorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReceiveMetric",
+			Handler:    _MetricCollector_ReceiveMetric_Handler,
+		},
+		{
+			MethodName: "ReceiveMetricBatch",
+			Handler:    _MetricCollector_ReceiveMetricBatch_Handler,
+		},
+		{
+			MethodName: "ReadMetric",
+			Handler:    _MetricCollector_ReadMetric_Handler,
+		},
+		{
+			MethodName: "ReadMetricRange",
+			Handler:    _MetricCollector_ReadMetricRange_Handler,
+		},
+		{
+			MethodName: "FindMetrics",
+			Handler:    _MetricCollector_FindMetrics_Handler,
+		},
+		{
+			MethodName: "ReadAllMetric",
+			Handler:    _MetricCollector_ReadAllMetric_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "metric_collector.proto",
+}
+
+// MetricCollectorAdminClient is the client API for MetricCollectorAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MetricCollectorAdminClient interface {
+	DeleteMetric(ctx context.Context, in *DeleteMetricRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	DeleteByPrefix(ctx context.Context, in *DeleteByPrefixRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	ResetCounter(ctx context.Context, in *ResetCounterRequest, opts ...grpc.CallOption) (*ResetCounterResponse, error)
+}
+
+type metricCollectorAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMetricCollectorAdminClient(cc grpc.ClientConnInterface) MetricCollectorAdminClient {
+	return &metricCollectorAdminClient{cc}
+}
+
+func (c *metricCollectorAdminClient) DeleteMetric(ctx context.Context, in *DeleteMetricRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollectorAdmin/DeleteMetric", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricCollectorAdminClient) DeleteByPrefix(ctx context.Context, in *DeleteByPrefixRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollectorAdmin/DeleteByPrefix", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *metricCollectorAdminClient) ResetCounter(ctx context.Context, in *ResetCounterRequest, opts ...grpc.CallOption) (*ResetCounterResponse, error) {
+	out := new(ResetCounterResponse)
+	err := c.cc.Invoke(ctx, "/metric_collector_grpc.MetricCollectorAdmin/ResetCounter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MetricCollectorAdminServer is the server API for MetricCollectorAdmin service.
+// All implementations must embed UnimplementedMetricCollectorAdminServer
+// for forward compatibility
+type MetricCollectorAdminServer interface {
+	DeleteMetric(context.Context, *DeleteMetricRequest) (*DeleteResponse, error)
+	DeleteByPrefix(context.Context, *DeleteByPrefixRequest) (*DeleteResponse, error)
+	ResetCounter(context.Context, *ResetCounterRequest) (*ResetCounterResponse, error)
+	mustEmbedUnimplementedMetricCollectorAdminServer()
+}
+
+// UnimplementedMetricCollectorAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedMetricCollectorAdminServer struct {
+}
+
+func (UnimplementedMetricCollectorAdminServer) DeleteMetric(context.Context, *DeleteMetricRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMetric not implemented")
+}
+func (UnimplementedMetricCollectorAdminServer) DeleteByPrefix(context.Context, *DeleteByPrefixRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteByPrefix not implemented")
+}
+func (UnimplementedMetricCollectorAdminServer) ResetCounter(context.Context, *ResetCounterRequest) (*ResetCounterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetCounter not implemented")
+}
+func (UnimplementedMetricCollectorAdminServer) mustEmbedUnimplementedMetricCollectorAdminServer() {}
+
+// UnsafeMetricCollectorAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MetricCollectorAdminServer will
+// result in compilation errors.
+type UnsafeMetricCollectorAdminServer interface {
+	mustEmbedUnimplementedMetricCollectorAdminServer()
+}
+
+func RegisterMetricCollectorAdminServer(s grpc.ServiceRegistrar, srv MetricCollectorAdminServer) {
+	s.RegisterService(&MetricCollectorAdmin_ServiceDesc, srv)
+}
+
+func _MetricCollectorAdmin_DeleteMetric_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMetricRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorAdminServer).DeleteMetric(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollectorAdmin/DeleteMetric",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorAdminServer).DeleteMetric(ctx, req.(*DeleteMetricRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricCollectorAdmin_DeleteByPrefix_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteByPrefixRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorAdminServer).DeleteByPrefix(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollectorAdmin/DeleteByPrefix",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorAdminServer).DeleteByPrefix(ctx, req.(*DeleteByPrefixRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MetricCollectorAdmin_ResetCounter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetCounterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricCollectorAdminServer).ResetCounter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metric_collector_grpc.MetricCollectorAdmin/ResetCounter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricCollectorAdminServer).ResetCounter(ctx, req.(*ResetCounterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MetricCollectorAdmin_ServiceDesc is the grpc.ServiceDesc for MetricCollectorAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MetricCollectorAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "metric_collector_grpc.MetricCollectorAdmin",
+	HandlerType: (*MetricCollectorAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "DeleteMetric",
+			Handler:    _MetricCollectorAdmin_DeleteMetric_Handler,
+		},
+		{
+			MethodName: "DeleteByPrefix",
+			Handler:    _MetricCollectorAdmin_DeleteByPrefix_Handler,
+		},
+		{
+			MethodName: "ResetCounter",
+			Handler:    _MetricCollectorAdmin_ResetCounter_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "metric_collector.proto",
+}
@@ -3,8 +3,22 @@ package hash
 
 import (
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовки подписанного запроса. Метаданные gRPC используют те же
+// имена в нижнем регистре.
+const (
+	HeaderSignature = "HashSHA256"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
 )
 
 // Функция хеширования, которая получает data - хешируемые данные,
@@ -20,3 +34,39 @@ func Hash(data []byte, key string) []byte {
 func Sign(data []byte, key string) string {
 	return base64.StdEncoding.EncodeToString(Hash(data, key))
 }
+
+// Функция SignRequest подписывает данные запроса вместе со временем
+// отправки timestamp и nonce, чтобы сервер мог отклонить повтор запроса
+func SignRequest(data []byte, timestamp, nonce, key string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(timestamp + "\n" + nonce + "\n"))
+	h.Write(data)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Функция RequestPayload возвращает подписываемые данные HTTP-запроса:
+// метод, путь без завершающей косой черты, параметры запроса и тело.
+// Параметры упорядочиваются по имени, чтобы подпись не зависела от
+// порядка параметров в URL.
+func RequestPayload(method, path string, query url.Values, body []byte) []byte {
+	target := method + " " + strings.TrimSuffix(path, "/") + "?" + query.Encode() + "\n"
+	return append([]byte(target), body...)
+}
+
+// Функция Equal сравнивает подписи за постоянное время
+func Equal(a, b string) bool {
+	return hmac.Equal([]byte(a), []byte(b))
+}
+
+// Функция Timestamp возвращает время отправки запроса в секундах Unix
+func Timestamp(now time.Time) string {
+	return strconv.FormatInt(now.Unix(), 10)
+}
+
+// Функция NewNonce возвращает случайный nonce запроса
+func NewNonce() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read не возвращает ошибок на поддерживаемых платформах
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}